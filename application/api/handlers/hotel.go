@@ -10,6 +10,7 @@ import (
 	"hotel-engine/utils/date"
 	_ "hotel-engine/utils/indraframework"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -26,11 +27,13 @@ type HotelHandler interface {
 	SyncSomeHotels(c *gin.Context)
 	SyncedHotels(c *gin.Context)
 	Rooms(c *gin.Context)
+	PriceCalendar(c *gin.Context)
 	Info(c *gin.Context)
 	RoomsWithSession(c *gin.Context)
 	Available(c *gin.Context)
 	FinalizeOrder(c *gin.Context)
 	OrderDetail(c *gin.Context)
+	CancellationPenalty(c *gin.Context)
 	SetAmenityIcon(c *gin.Context)
 	SetAmenityCategory(c *gin.Context)
 
@@ -39,8 +42,11 @@ type HotelHandler interface {
 	GetOrderStatus(c *gin.Context)
 	GetOrderEnquiry(c *gin.Context)
 	RefundOrder(c *gin.Context)
+	RefundPreview(c *gin.Context)
+	StuckOrderSagas(c *gin.Context)
 
 	GetHotelsList(c *gin.Context)
+	Orders(c *gin.Context)
 	GetHotelById(c *gin.Context)
 	SetHotelSeoTags(c *gin.Context)
 
@@ -72,7 +78,7 @@ func (h *hotelHandler) GetHotelDetails(c *gin.Context) {
 		return
 	}
 	detailsRequest.SetDefaults()
-	hotel, err := h.service.GetHotelDetails(detailsRequest)
+	hotel, err := h.service.GetHotelDetails(c.Request.Context(), detailsRequest)
 
 	if err == common.HotelNotFound {
 		jsonNotFound(c, &dto.HotelPDPDto{}, err)
@@ -103,7 +109,7 @@ func (h *hotelHandler) FindHotels(c *gin.Context) {
 		func() (error error, data dto.Dto) { return c.BindJSON(&detailsRequest), &dto.FindHotelResponseDto{} }); !success {
 		return
 	}
-	hotel, err := h.service.GetHotels(*detailsRequest.HotelIDs)
+	hotel, err := h.service.GetHotels(c.Request.Context(), *detailsRequest.HotelIDs, detailsRequest.Supplier)
 
 	if err == common.HotelNotFound {
 		jsonNotFound(c, &dto.FindHotelResponseDto{}, err)
@@ -127,12 +133,13 @@ func (h *hotelHandler) FindHotels(c *gin.Context) {
 // @Param hotelId path string true "hotel id"
 // @Param roomId path string true "room id"
 // @Param sessionId path string true "session id"
+// @Param supplier query string false "supplier of the hotel, the default supplier when it is empty"
 // @Success 200 {object} dto.RoomCancellationPolicyDto
 // @Failure 400 {object}  indraframework.IndraException
 // @Router /v1/hotel/room-cancellation-policy/{hotelId}/{roomId}/{sessionId} [get]
 func (h *hotelHandler) GetRoomCancellationPolicy(c *gin.Context) {
-	policyDto, err := h.service.GetRoomCancellationPolicy(c.Param("hotelId"),
-		c.Param("roomId"), c.Param("sessionId"))
+	policyDto, err := h.service.GetRoomCancellationPolicy(c.Request.Context(), c.Param("hotelId"),
+		c.Param("roomId"), c.Param("sessionId"), c.Query("supplier"))
 	if err != nil {
 		jsonBadRequest(c, &dto.TaskRunningResult{}, err)
 		return
@@ -159,7 +166,7 @@ func (h *hotelHandler) UpdateAllHotels(c *gin.Context) {
 		func() (error error, data dto.Dto) { return updateDto.Validate(), &dto.TaskRunningResult{} }); !success {
 		return
 	}
-	item, err := h.service.UpdateAll(date.StringToDateOrDefault(updateDto.Date))
+	item, err := h.service.UpdateAll(c.Request.Context(), date.StringToDateOrDefault(updateDto.Date))
 	if err != nil {
 		jsonBadRequest(c, &dto.TaskRunningResult{}, err)
 		return
@@ -189,7 +196,7 @@ func (h *hotelHandler) Search(c *gin.Context) {
 		return
 	}
 
-	result, err := h.service.SearchResult(searchDto)
+	result, err := h.service.SearchResult(c.Request.Context(), searchDto)
 
 	if err != nil {
 		jsonBadRequest(c, &dto.SearchResponseDto{}, err)
@@ -218,7 +225,7 @@ func (h *hotelHandler) Rooms(c *gin.Context) {
 		return
 	}
 	roomDto.SetDefaults()
-	res, err := h.service.GetHotelRooms(roomDto)
+	res, err := h.service.GetHotelRooms(c.Request.Context(), roomDto)
 
 	if err != nil {
 		jsonBadRequest(c, &dto.RateRoomResponseDto{}, err)
@@ -228,6 +235,35 @@ func (h *hotelHandler) Rooms(c *gin.Context) {
 	jsonSuccess(c, res)
 }
 
+// PriceCalendar godoc
+// @Summary get hotel price calendar
+// @Description get the cheapest price of a night for each check-in day, the range is 30 days when to is not set and an indicative day is priced from the synced prices
+// @ID PriceCalendar
+// @tags Hotel
+// @Accept  json
+// @Produce  json
+// @Param calendarDto body dto.PriceCalendarRequestDto true "get hotel price calendar"
+// @Success 200 {object} dto.PriceCalendarResponseDto
+// @Failure 400 {object} indraframework.IndraException
+// @Router /v1/hotel/price-calendar [post]
+func (h *hotelHandler) PriceCalendar(c *gin.Context) {
+	var calendarDto dto.PriceCalendarRequestDto
+	if success := tryActions(c,
+		func() (error error, data dto.Dto) { return c.BindJSON(&calendarDto), &dto.PriceCalendarResponseDto{} },
+		func() (error error, data dto.Dto) { return calendarDto.Validate(), &dto.PriceCalendarResponseDto{} }); !success {
+		return
+	}
+	calendarDto.SetDefaults()
+	res, err := h.service.GetPriceCalendar(c.Request.Context(), calendarDto)
+
+	if err != nil {
+		jsonBadRequest(c, &dto.PriceCalendarResponseDto{}, err)
+		return
+	}
+
+	jsonSuccess(c, res)
+}
+
 // Info godoc
 // @Summary get hotel option Info
 // @Description get hotel option Info
@@ -247,7 +283,7 @@ func (h *hotelHandler) Info(c *gin.Context) {
 		return
 	}
 
-	res, err := h.service.GetHotelOptionInfo(infoDto)
+	res, err := h.service.GetHotelOptionInfo(c.Request.Context(), infoDto)
 
 	if err != nil {
 		jsonBadRequest(c, &dto.OptionInfoResponseDto{}, err)
@@ -275,7 +311,7 @@ func (h *hotelHandler) RoomsWithSession(c *gin.Context) {
 		return
 	}
 
-	res, err := h.service.GetHotelRoomsWithSession(roomDto)
+	res, err := h.service.GetHotelRoomsWithSession(c.Request.Context(), roomDto)
 
 	if err != nil {
 		jsonBadRequest(c, &dto.RateRoomResponseDto{}, err)
@@ -292,9 +328,12 @@ func (h *hotelHandler) RoomsWithSession(c *gin.Context) {
 // @tags Hotel - Order
 // @Accept  json
 // @Produce  json
+// @Param Idempotency-Key header string false "the key of the request, a retry with the same key gets the first response"
 // @Param availableDto body dto.AvailableDto true "get order id if hotel is available"
 // @Success 200 {object} dto.AvailableResponseDto
 // @Failure 400 {object} indraframework.IndraException
+// @Failure 409 {object} indraframework.IndraException
+// @Failure 422 {object} indraframework.IndraException
 // @Router /v1/hotel/available [post]
 func (h *hotelHandler) Available(c *gin.Context) {
 	var availableDto dto.AvailableDto
@@ -304,7 +343,7 @@ func (h *hotelHandler) Available(c *gin.Context) {
 		return
 	}
 
-	res, err := h.service.HotelAvailable(availableDto)
+	res, err := h.service.HotelAvailable(c.Request.Context(), availableDto)
 
 	if err != nil {
 		jsonBadRequest(c, &dto.AvailableResponseDto{}, err)
@@ -321,9 +360,12 @@ func (h *hotelHandler) Available(c *gin.Context) {
 // @tags Hotel - Order
 // @Accept  json
 // @Produce  json
+// @Param Idempotency-Key header string false "the key of the request, a retry with the same key gets the first response"
 // @Param finalizeOrderDto body dto.FinalizeOrderDto true "get hotel rooms"
 // @Success 200 {object} dto.FinalizeOrderResponseDto
 // @Failure 400 {object} indraframework.IndraException
+// @Failure 409 {object} indraframework.IndraException
+// @Failure 422 {object} indraframework.IndraException
 // @Router /v1/hotel/finalize-order [post]
 func (h *hotelHandler) FinalizeOrder(c *gin.Context) {
 	var finalizeOrderDto dto.FinalizeOrderDto
@@ -337,7 +379,7 @@ func (h *hotelHandler) FinalizeOrder(c *gin.Context) {
 		return
 	}
 
-	res, err := h.service.FinalizeHotelOrder(finalizeOrderDto)
+	res, err := h.service.FinalizeHotelOrder(c.Request.Context(), finalizeOrderDto)
 
 	if err != nil {
 		jsonBadRequest(c, &dto.FinalizeOrderResponseDto{}, err)
@@ -364,7 +406,7 @@ func (h *hotelHandler) OrderDetail(c *gin.Context) {
 		jsonBadRequest(c, &dto.OrderDetailDto{}, errors.New("order id is required"))
 		return
 	}
-	detail, err := h.service.GetAnOrderDetail(id)
+	detail, err := h.service.GetAnOrderDetail(c.Request.Context(), id)
 
 	if err == common.OrderNotFound {
 		jsonNotFound(c, &dto.OrderDetailDto{}, err)
@@ -378,6 +420,41 @@ func (h *hotelHandler) OrderDetail(c *gin.Context) {
 	jsonSuccess(c, detail)
 }
 
+// CancellationPenalty godoc
+// @Summary get order cancellation penalty
+// @Description get the penalty of cancelling the order at a time, the time is now when it is not set
+// @ID CancellationPenalty
+// @tags Hotel - Order
+// @Accept  json
+// @Produce  json
+// @Param orderId path string true "order id"
+// @Param at query string false "cancellation time in RFC3339"
+// @Success 200 {object} dto.CancellationPenaltyDto
+// @Failure 400 {object} indraframework.IndraException
+// @Router /v1/hotel/order/cancellation-penalty/{orderId} [get]
+func (h *hotelHandler) CancellationPenalty(c *gin.Context) {
+	orderId := c.Param("orderId")
+	at := time.Now()
+	if value := c.Query("at"); value != "" {
+		var err error
+		if at, err = time.Parse(time.RFC3339, value); err != nil {
+			jsonBadRequest(c, &dto.CancellationPenaltyDto{}, errors.New("at is not a valid RFC3339 time"))
+			return
+		}
+	}
+	res, err := h.service.GetCancellationPenalty(c.Request.Context(), orderId, at)
+
+	if err == common.OrderNotFound {
+		jsonNotFound(c, &dto.CancellationPenaltyDto{}, err)
+		return
+	}
+	if err != nil {
+		jsonBadRequest(c, &dto.CancellationPenaltyDto{}, err)
+		return
+	}
+	jsonSuccess(c, res)
+}
+
 // SyncAllHotels godoc
 // @Summary sync all hotels
 // @Description sync all hotels from provider
@@ -394,7 +471,7 @@ func (h *hotelHandler) SyncAllHotels(c *gin.Context) {
 		jsonForbiddenRequest(c, &dto.TaskRunningResult{}, errors.New("secret key is not correct"))
 		return
 	}
-	taskRes, err := h.service.SyncAllHotels()
+	taskRes, err := h.service.SyncAllHotels(c.Request.Context())
 	if err != nil {
 		jsonBadRequest(c, &dto.TaskRunningResult{}, err)
 		return
@@ -476,7 +553,7 @@ func (h *hotelHandler) SyncSomeHotels(c *gin.Context) {
 		return
 	}
 
-	item, err := h.service.SyncSomeHotels(syncDto)
+	item, err := h.service.SyncSomeHotels(c.Request.Context(), syncDto)
 	if err != nil {
 		jsonBadRequest(c, &dto.UpdateResultDto{}, err)
 		return
@@ -494,7 +571,7 @@ func (h *hotelHandler) SyncSomeHotels(c *gin.Context) {
 // @Success 200 {object} dto.SyncedHotelsDetail
 // @Router /v1/hotel/synced-hotel [get]
 func (h *hotelHandler) SyncedHotels(c *gin.Context) {
-	details, err := h.service.SyncedHotels()
+	details, err := h.service.SyncedHotels(c.Request.Context())
 	if err != nil {
 		jsonBadRequest(c, &dto.SyncedHotelsDetail{}, err)
 		return
@@ -522,7 +599,7 @@ func (h *hotelHandler) SetAmenityIcon(c *gin.Context) {
 		return
 	}
 
-	item, err := h.service.SetAmenityIcon(body)
+	item, err := h.service.SetAmenityIcon(c.Request.Context(), body)
 	if err == common.AmenityNotFound {
 		jsonNotFound(c, &dto.HotelAmenityDto{}, err)
 		return
@@ -555,7 +632,7 @@ func (h *hotelHandler) SetAmenityCategory(c *gin.Context) {
 		return
 	}
 
-	item, err := h.service.SetAmenityCategory(body)
+	item, err := h.service.SetAmenityCategory(c.Request.Context(), body)
 	if err == common.AmenityNotFound {
 		jsonNotFound(c, &dto.HotelAmenityDto{}, err)
 		return
@@ -584,7 +661,7 @@ func (h *hotelHandler) SetAmenityCategory(c *gin.Context) {
 // @Router /v1/hotel/order/confirm/{orderId} [put]
 func (h *hotelHandler) ConfirmOrder(c *gin.Context) {
 	orderId := c.Param("orderId")
-	res, err := h.service.ConfirmOrder(orderId)
+	res, err := h.service.ConfirmOrder(c.Request.Context(), orderId)
 
 	if err == common.OrderNotFound {
 		jsonNotFound(c, &dto.ConfirmResponseDto{}, err)
@@ -604,13 +681,16 @@ func (h *hotelHandler) ConfirmOrder(c *gin.Context) {
 // @tags Hotel - Order
 // @Accept  json
 // @Produce  json
+// @Param Idempotency-Key header string false "the key of the request, a retry with the same key gets the first response"
 // @Param orderId path string true "order id"
 // @Success 200 {object} dto.OrderPayByAccountResponseDto
 // @Failure 400 {object} indraframework.IndraException
+// @Failure 409 {object} indraframework.IndraException
+// @Failure 422 {object} indraframework.IndraException
 // @Router /v1/hotel/order/pay-by-account/{orderId} [put]
 func (h *hotelHandler) PayByAccount(c *gin.Context) {
 	orderId := c.Param("orderId")
-	res, err := h.service.PayByAccount(orderId)
+	res, err := h.service.PayByAccount(c.Request.Context(), orderId)
 
 	if err == common.OrderNotFound {
 		jsonNotFound(c, &dto.OrderPayByAccountResponseDto{}, err)
@@ -636,7 +716,7 @@ func (h *hotelHandler) PayByAccount(c *gin.Context) {
 // @Router /v1/hotel/order/status/{orderId} [get]
 func (h *hotelHandler) GetOrderStatus(c *gin.Context) {
 	orderId := c.Param("orderId")
-	res, err := h.service.GetOrderStatus(orderId)
+	res, err := h.service.GetOrderStatus(c.Request.Context(), orderId)
 
 	if err == common.OrderNotFound {
 		jsonNotFound(c, &dto.OrderStatusResponseDto{}, err)
@@ -662,7 +742,7 @@ func (h *hotelHandler) GetOrderStatus(c *gin.Context) {
 // @Router /v1/hotel/order/enquiry/{orderId} [get]
 func (h *hotelHandler) GetOrderEnquiry(c *gin.Context) {
 	orderId := c.Param("orderId")
-	res, err := h.service.GetOrderEnquiry(orderId)
+	res, err := h.service.GetOrderEnquiry(c.Request.Context(), orderId)
 
 	if err == common.OrderNotFound {
 		jsonNotFound(c, &dto.OrderEnquiryResponseDto{}, err)
@@ -677,14 +757,17 @@ func (h *hotelHandler) GetOrderEnquiry(c *gin.Context) {
 
 // RefundOrder godoc
 // @Summary refund an order
-// @Description refund an order
+// @Description refund an order or some of its rooms or passengers by their reference codes, the quote token of the refund preview is required
 // @ID RefundOrder
 // @tags Hotel - Order
 // @Accept  json
 // @Produce  json
+// @Param Idempotency-Key header string false "the key of the request, a retry with the same key gets the first response"
 // @Param infoDto body dto.OrderRefundRequestDto true "refund request dto"
 // @Success 200 {object} dto.OrderRefundResponseDto
 // @Failure 400 {object} indraframework.IndraException
+// @Failure 409 {object} indraframework.IndraException
+// @Failure 422 {object} indraframework.IndraException
 // @Router /v1/hotel/order/refund [post]
 func (h *hotelHandler) RefundOrder(c *gin.Context) {
 	var body dto.OrderRefundRequestDto
@@ -694,7 +777,7 @@ func (h *hotelHandler) RefundOrder(c *gin.Context) {
 		return
 	}
 
-	item, err := h.service.RefundOrder(body)
+	item, err := h.service.RefundOrder(c.Request.Context(), body)
 	if err == common.OrderNotFound {
 		jsonNotFound(c, &dto.OrderRefundResponseDto{}, err)
 		return
@@ -706,6 +789,58 @@ func (h *hotelHandler) RefundOrder(c *gin.Context) {
 	jsonSuccess(c, item)
 }
 
+// RefundPreview godoc
+// @Summary preview the refund of an order
+// @Description get the penalty and refundable amount per room and passenger without refunding, the quote token must be sent with the refund of the same reference codes
+// @ID RefundPreview
+// @tags Hotel - Order
+// @Accept  json
+// @Produce  json
+// @Param orderId path string true "order id"
+// @Param referenceCode query []string false "reference codes of the refunded rooms or passengers, the whole order when not set" collectionFormat(multi)
+// @Success 200 {object} dto.RefundPreviewDto
+// @Failure 400 {object} indraframework.IndraException
+// @Router /v1/hotel/order/refund-preview/{orderId} [get]
+func (h *hotelHandler) RefundPreview(c *gin.Context) {
+	orderId := c.Param("orderId")
+	res, err := h.service.PreviewRefund(c.Request.Context(), orderId, c.QueryArray("referenceCode"))
+
+	if err == common.OrderNotFound {
+		jsonNotFound(c, &dto.RefundPreviewDto{}, err)
+		return
+	}
+	if err != nil {
+		jsonBadRequest(c, &dto.RefundPreviewDto{}, err)
+		return
+	}
+	jsonSuccess(c, res)
+}
+
+// StuckOrderSagas godoc
+// @Summary get stuck order finalizations
+// @Description get the order finalizations that are not changed for a while and the ones that are left for the support
+// @ID StuckOrderSagas
+// @tags Hotel - management
+// @Produce  json
+// @Param secret path string true "the sync secret"
+// @Success 200 {object} dto.StuckOrderSagasDto
+// @Failure 400 {object} indraframework.IndraException
+// @Failure 403 {object} indraframework.IndraException
+// @Router /v1/hotel/order-sagas/stuck/{secret} [get]
+func (h *hotelHandler) StuckOrderSagas(c *gin.Context) {
+	secret := c.Param("secret")
+	if config.Get().SyncSecret != secret {
+		jsonForbiddenRequest(c, &dto.StuckOrderSagasDto{}, errors.New("secret key is not correct"))
+		return
+	}
+	sagas, err := h.service.GetStuckOrderSagas(c.Request.Context())
+	if err != nil {
+		jsonBadRequest(c, &dto.StuckOrderSagasDto{}, err)
+		return
+	}
+	jsonSuccess(c, dto.StuckOrderSagasDto{Sagas: sagas})
+}
+
 // GetHotelsList godoc
 // @Summary get hotel lists
 // @Description get hotel lists
@@ -725,7 +860,7 @@ func (h *hotelHandler) GetHotelsList(c *gin.Context) {
 		return
 	}
 
-	res, err := h.service.GetHotelsList(body)
+	res, err := h.service.GetHotelsList(c.Request.Context(), body)
 	if err != nil {
 		jsonBadRequest(c, &dto.HotelsPageResponseDto{}, err)
 		return
@@ -733,6 +868,51 @@ func (h *hotelHandler) GetHotelsList(c *gin.Context) {
 	jsonSuccess(c, res)
 }
 
+// Orders godoc
+// @Summary get orders list
+// @Description get a page of the orders by hotel, phone number, status, state, applicant order id and dates for the support
+// @ID Orders
+// @tags Hotel - Admin
+// @Produce  json
+// @Param secret path string true "the sync secret"
+// @Param pageNumber query int true "page number"
+// @Param pageSize query int true "page size, at most 100"
+// @Param hotelId query string false "place id of the hotel"
+// @Param phoneNumber query string false "phone number of the reservation, the old orders have no phone number"
+// @Param status query string false "supplier status of the order"
+// @Param state query string false "state of the order"
+// @Param applicantOrderId query int false "order id of the applicant"
+// @Param checkInFrom query string false "first check in day like 2006-01-02"
+// @Param checkInTo query string false "last check in day like 2006-01-02"
+// @Param createdFrom query string false "first reservation day like 2006-01-02"
+// @Param createdTo query string false "last reservation day like 2006-01-02"
+// @Param sortField query string false "createdAt, checkIn or totalPrice" default(createdAt)
+// @Param ascending query bool false "ascending sort"
+// @Success 200 {object} dto.OrdersPageResponseDto
+// @Failure 400 {object} indraframework.IndraException
+// @Failure 403 {object} indraframework.IndraException
+// @Router /v1/hotel/orders/{secret} [get]
+func (h *hotelHandler) Orders(c *gin.Context) {
+	secret := c.Param("secret")
+	if config.Get().SyncSecret != secret {
+		jsonForbiddenRequest(c, &dto.OrdersPageResponseDto{}, errors.New("secret key is not correct"))
+		return
+	}
+	var body dto.OrdersPageRequestDto
+	if success := tryActions(c,
+		func() (error error, data dto.Dto) { return c.BindQuery(&body), &dto.OrdersPageResponseDto{} },
+		func() (error error, data dto.Dto) { return body.Validate(), &dto.OrdersPageResponseDto{} }); !success {
+		return
+	}
+
+	res, err := h.service.GetOrdersList(c.Request.Context(), body)
+	if err != nil {
+		jsonBadRequest(c, &dto.OrdersPageResponseDto{}, err)
+		return
+	}
+	jsonSuccess(c, res)
+}
+
 // GetHotelById godoc
 // @Summary get hotel by id
 // @Description get hotel by id
@@ -741,11 +921,12 @@ func (h *hotelHandler) GetHotelsList(c *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Param hotelId path string true "hotel id"
+// @Param supplier query string false "supplier of the hotel, the default supplier when it is empty"
 // @Success 200 {object} dto.HotelDto
 // @Failure 400 {object} indraframework.IndraException
 // @Router /v1/hotel/find/{hotelId} [get]
 func (h *hotelHandler) GetHotelById(c *gin.Context) {
-	res, err := h.service.FindHotelById(c.Param("hotelId"))
+	res, err := h.service.FindHotelById(c.Request.Context(), c.Param("hotelId"), c.Query("supplier"))
 	if err != nil {
 		jsonBadRequest(c, &dto.HotelDto{}, err)
 		return
@@ -773,7 +954,7 @@ func (h *hotelHandler) SetHotelSeoTags(c *gin.Context) {
 		return
 	}
 
-	item, err := h.service.SetHotelSeoDetails(hotelSeoRequestDto)
+	item, err := h.service.SetHotelSeoDetails(c.Request.Context(), hotelSeoRequestDto)
 	if err == common.HotelNotFound {
 		jsonNotFound(c, &dto.HotelDto{}, err)
 		return
@@ -805,7 +986,7 @@ func (h *hotelHandler) SetHotelFaq(c *gin.Context) {
 		return
 	}
 
-	item, err := h.service.SetHotelFaq(hotelFaqRequestDto)
+	item, err := h.service.SetHotelFaq(c.Request.Context(), hotelFaqRequestDto)
 	if err == common.HotelNotFound {
 		jsonNotFound(c, &dto.HotelDto{}, err)
 		return
@@ -826,6 +1007,7 @@ func (h *hotelHandler) SetHotelFaq(c *gin.Context) {
 // @Produce  json
 // @Param hotelId path string true "hotel id"
 // @Param faqId path integer true "faq id"
+// @Param supplier query string false "supplier of the hotel, the default supplier when it is empty"
 // @Success 200 {object} dto.HotelDto
 // @Failure 400 {object}  indraframework.IndraException
 // @Router /v1/hotel/delete-hotel-faq/{hotelId}/{faqId} [delete]
@@ -838,7 +1020,7 @@ func (h *hotelHandler) DeleteHotelFaq(c *gin.Context) {
 		return
 	}
 
-	item, err := h.service.RemoveHotelFaq(hotelId, uint(faqId))
+	item, err := h.service.RemoveHotelFaq(c.Request.Context(), hotelId, c.Query("supplier"), uint(faqId))
 	if err == common.HotelNotFound || err == common.FAQNotFound {
 		jsonNotFound(c, &dto.HotelDto{}, err)
 		return
@@ -27,6 +27,7 @@ type PublicHandler interface {
 	Cities(c *gin.Context)
 	Sorts(c *gin.Context)
 	Places(c *gin.Context)
+	Autocomplete(c *gin.Context)
 	ChildAgeRanges(c *gin.Context)
 	Filters(c *gin.Context)
 	LoadInnerTypes(c *gin.Context)
@@ -121,6 +122,30 @@ func (h *publicHandler) Places(c *gin.Context) {
 	jsonSuccess(c, places)
 }
 
+// Autocomplete godoc
+// @Summary autocomplete cities, provinces and hotels
+// @Description search the cities, provinces and hotels by their persian, english or finglish names
+// @ID Autocomplete
+// @tags Public
+// @Produce  json
+// @Param q query string true "search text"
+// @Param limit query int false "maximum number of items"
+// @Success 200 {object} dto.AutocompleteResponseDto
+// @Failure 400 {object} dto.AutocompleteResponseDto
+// @Router /v1/public/autocomplete [get]
+func (h *publicHandler) Autocomplete(c *gin.Context) {
+	limit := 0
+	if limitString := c.Query("limit"); limitString != "" {
+		var err error
+		if limit, err = parser.ParseNumber(limitString); err != nil {
+			response := dto.NewAutocompleteResponseDto(nil)
+			jsonBadRequest(c, &response, err)
+			return
+		}
+	}
+	jsonSuccess(c, dto.NewAutocompleteResponseDto(h.service.Autocomplete(c.Query("q"), limit)))
+}
+
 // GetProviderToken godoc
 // @Summary get provider token
 // @Description get provider token
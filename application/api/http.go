@@ -10,7 +10,8 @@ import (
 	_ "net/http/pprof"
 )
 
-func CreateRoute(hotelHandler handlers.HotelHandler, publicHandler handlers.PublicHandler) *gin.Engine {
+func CreateRoute(hotelHandler handlers.HotelHandler, publicHandler handlers.PublicHandler,
+	idempotency gin.HandlerFunc) *gin.Engine {
 
 	route := gin.Default()
 
@@ -32,21 +33,26 @@ func CreateRoute(hotelHandler handlers.HotelHandler, publicHandler handlers.Publ
 		hotelV1.PUT("/sync-elastic/:secret", hotelHandler.SyncElastic)
 
 		hotelV1.POST("/rooms", hotelHandler.Rooms)
+		hotelV1.POST("/price-calendar", hotelHandler.PriceCalendar)
 		hotelV1.POST("/info", hotelHandler.Info)
 		hotelV1.POST("/rooms-by-session", hotelHandler.RoomsWithSession)
-		hotelV1.POST("/available", hotelHandler.Available)
-		hotelV1.POST("/finalize-order", hotelHandler.FinalizeOrder)
+		hotelV1.POST("/available", idempotency, hotelHandler.Available)
+		hotelV1.POST("/finalize-order", idempotency, hotelHandler.FinalizeOrder)
 		hotelV1.PUT("/order/confirm/:orderId", hotelHandler.ConfirmOrder)
-		hotelV1.PUT("/order/pay-by-account/:orderId", hotelHandler.PayByAccount)
+		hotelV1.PUT("/order/pay-by-account/:orderId", idempotency, hotelHandler.PayByAccount)
 		hotelV1.GET("/order/status/:orderId", hotelHandler.GetOrderStatus)
 		hotelV1.GET("/order/enquiry/:orderId", hotelHandler.GetOrderEnquiry)
-		hotelV1.POST("/order/refund", hotelHandler.RefundOrder)
+		hotelV1.GET("/order/cancellation-penalty/:orderId", hotelHandler.CancellationPenalty)
+		hotelV1.POST("/order/refund", idempotency, hotelHandler.RefundOrder)
+		hotelV1.GET("/order/refund-preview/:orderId", hotelHandler.RefundPreview)
 		hotelV1.GET("/order-detail/:id", hotelHandler.OrderDetail)
+		hotelV1.GET("/order-sagas/stuck/:secret", hotelHandler.StuckOrderSagas)
 
 		hotelV1.PUT("/set-amenity-icon", hotelHandler.SetAmenityIcon)
 		hotelV1.PUT("/set-amenity-category", hotelHandler.SetAmenityCategory)
 
 		hotelV1.POST("/list", hotelHandler.GetHotelsList)
+		hotelV1.GET("/orders/:secret", hotelHandler.Orders)
 		hotelV1.GET("/find/:hotelId", hotelHandler.GetHotelById)
 		hotelV1.PUT("/set-hotel-meta-tags", hotelHandler.SetHotelSeoTags)
 
@@ -61,6 +67,7 @@ func CreateRoute(hotelHandler handlers.HotelHandler, publicHandler handlers.Publ
 		publicV1.GET("/filters", publicHandler.Filters)
 		publicV1.GET("/sorts", publicHandler.Sorts)
 		publicV1.GET("/places", publicHandler.Places)
+		publicV1.GET("/autocomplete", publicHandler.Autocomplete)
 		publicV1.GET("/child-age-ranges", publicHandler.ChildAgeRanges)
 		publicV1.GET("/load-inner-types", publicHandler.LoadInnerTypes)
 		publicV1.GET("/provider-token", publicHandler.GetProviderToken)
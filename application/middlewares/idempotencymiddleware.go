@@ -0,0 +1,187 @@
+package middlewares
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hotel-engine/core"
+	"hotel-engine/core/common"
+	"hotel-engine/core/common/logtags"
+	"hotel-engine/core/dbmodel"
+	"hotel-engine/infrastructure/logger"
+	"hotel-engine/utils/indraframework"
+	"hotel-engine/utils/sideeffect"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotency-Replayed"
+)
+
+//NewIdempotencyMiddleware makes a request with an Idempotency-Key header run once, a retry with the same key and
+//request gets the stored response and the same key with another request is rejected. the successful responses and
+//the responses of the requests that have called the supplier with a side effect are kept for the ttl, the key of a
+//request that failed or panicked before calling the supplier is released so the client can retry it. the key is kept
+//alive while the request runs, a key in progress for longer than staleAfter is left by a crashed instance and is
+//reserved again
+func NewIdempotencyMiddleware(repository core.IdempotencyKeyRepository, ttl, staleAfter time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		body, err := ioutil.ReadAll(c.Request.Body)
+		if err != nil {
+			abortIdempotentRequest(c, http.StatusBadRequest, err)
+			return
+		}
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		item, err := reserveIdempotencyKey(repository, key, requestHash(c.Request.Method, c.Request.URL.Path, body), ttl,
+			staleAfter)
+		switch {
+		case err == common.IdempotencyKeyReused:
+			abortIdempotentRequest(c, http.StatusUnprocessableEntity, err)
+			return
+		case err == common.IdempotencyKeyInProgress:
+			abortIdempotentRequest(c, http.StatusConflict, err)
+			return
+		case err != nil:
+			logger.WithName(logtags.IdempotencyKeyError).WithData(key).
+				ErrorException(err, "error while reserving an idempotency key")
+			abortIdempotentRequest(c, http.StatusInternalServerError, err)
+			return
+		}
+		if item.Completed {
+			logger.WithName(logtags.IdempotencyKeyReplayed).WithData(key).WithUrl(c.Request.RequestURI).
+				Info("response of the idempotency key is replayed")
+			c.Header(IdempotencyReplayedHeader, "true")
+			c.Data(item.StatusCode, gin.MIMEJSON+"; charset=utf-8", []byte(item.Response))
+			c.Abort()
+			return
+		}
+
+		ctx, supplierCalled := sideeffect.Track(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+		stopKeepAlive := keepIdempotencyKeyAlive(repository, *item, staleAfter/3)
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			stopKeepAlive()
+			if supplierCalled.Get() {
+				response, _ := json.Marshal(indraframework.NewIndraException(
+					"request has failed after calling the supplier", "idempotency key", http.StatusInternalServerError))
+				completeIdempotencyKey(repository, item, http.StatusInternalServerError, string(response))
+				return
+			}
+			if err := repository.Delete(*item); err != nil {
+				logger.WithName(logtags.IdempotencyKeyError).WithData(key).
+					ErrorException(err, "error while releasing an idempotency key")
+			}
+		}()
+		writer := &bodyLogWriter{body: bytes.NewBufferString(""), ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		status := c.Writer.Status()
+		if status >= http.StatusOK && status < http.StatusMultipleChoices || supplierCalled.Get() {
+			completed = true
+			stopKeepAlive()
+			completeIdempotencyKey(repository, item, status, writer.body.String())
+		}
+	}
+}
+
+func completeIdempotencyKey(repository core.IdempotencyKeyRepository, item *dbmodel.IdempotencyKey, status int,
+	response string) {
+	item.Complete(status, response)
+	if err := repository.StoreOrUpdate(*item); err != nil {
+		logger.WithName(logtags.IdempotencyKeyError).WithData(item.Key).
+			ErrorException(err, "error while storing the result of an idempotency key")
+	}
+}
+
+//keepIdempotencyKeyAlive touches the key in progress every interval until the returned func is called so a long
+//request is not taken for a crashed one
+func keepIdempotencyKeyAlive(repository core.IdempotencyKeyRepository, item dbmodel.IdempotencyKey,
+	interval time.Duration) func() {
+	if interval <= 0 {
+		return func() {}
+	}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := repository.Touch(item); err != nil {
+					logger.WithName(logtags.IdempotencyKeyError).WithData(item.Key).
+						ErrorException(err, "error while keeping an idempotency key alive")
+				}
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-stopped
+		})
+	}
+}
+
+//reserveIdempotencyKey returns the stored key of the same request or a new reserved key, a completed key older than
+//the ttl and a key in progress for longer than staleAfter are reclaimed only when no other request has reclaimed or
+//touched them since they were read
+func reserveIdempotencyKey(repository core.IdempotencyKeyRepository, key, hash string,
+	ttl, staleAfter time.Duration) (*dbmodel.IdempotencyKey, error) {
+	item, err := repository.Get(key)
+	if err == common.IdempotencyKeyNotFound {
+		item, err = repository.Insert(dbmodel.IdempotencyKey{Key: key, RequestHash: hash})
+		if err != nil {
+			if _, found := repository.Get(key); found == nil {
+				return nil, common.IdempotencyKeyInProgress
+			}
+			return nil, err
+		}
+		return item, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if time.Since(item.CreatedAt) > ttl || !item.Completed && time.Since(item.UpdatedAt) > staleAfter {
+		return repository.Reclaim(*item, hash)
+	}
+	if item.RequestHash != hash {
+		return nil, common.IdempotencyKeyReused
+	}
+	if !item.Completed {
+		return nil, common.IdempotencyKeyInProgress
+	}
+	return item, nil
+}
+
+func requestHash(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func abortIdempotentRequest(c *gin.Context, code int, err error) {
+	c.AbortWithStatusJSON(code, indraframework.NewIndraException(err.Error(), "idempotency key", code))
+}
@@ -0,0 +1,215 @@
+package middlewares
+
+import (
+	"bytes"
+	"errors"
+	"hotel-engine/core/common"
+	"hotel-engine/core/dbmodel"
+	"hotel-engine/utils/sideeffect"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+)
+
+type memoryIdempotencyKeys struct {
+	items map[string]dbmodel.IdempotencyKey
+	//touchedAfterGet is another request keeping the key alive between the read and the reclaim of the key
+	touchedAfterGet bool
+}
+
+func (r *memoryIdempotencyKeys) Get(key string) (*dbmodel.IdempotencyKey, error) {
+	item, found := r.items[key]
+	if !found {
+		return nil, common.IdempotencyKeyNotFound
+	}
+	if r.touchedAfterGet {
+		touched := r.items[key]
+		touched.UpdatedAt = time.Now()
+		r.items[key] = touched
+	}
+	return &item, nil
+}
+
+func (r *memoryIdempotencyKeys) Insert(item dbmodel.IdempotencyKey) (*dbmodel.IdempotencyKey, error) {
+	if _, found := r.items[item.Key]; found {
+		return nil, errors.New("duplicate key")
+	}
+	item.ID = uint(len(r.items) + 1)
+	item.CreatedAt, item.UpdatedAt = time.Now(), time.Now()
+	r.items[item.Key] = item
+	return &item, nil
+}
+
+func (r *memoryIdempotencyKeys) StoreOrUpdate(item dbmodel.IdempotencyKey) error {
+	r.items[item.Key] = item
+	return nil
+}
+
+func (r *memoryIdempotencyKeys) Delete(item dbmodel.IdempotencyKey) error {
+	delete(r.items, item.Key)
+	return nil
+}
+
+func (r *memoryIdempotencyKeys) Reclaim(item dbmodel.IdempotencyKey, hash string) (*dbmodel.IdempotencyKey, error) {
+	if !r.items[item.Key].UpdatedAt.Equal(item.UpdatedAt) {
+		return nil, common.IdempotencyKeyInProgress
+	}
+	item.RequestHash, item.Completed, item.StatusCode, item.Response = hash, false, 0, ""
+	item.CreatedAt, item.UpdatedAt = time.Now(), time.Now()
+	r.items[item.Key] = item
+	return &item, nil
+}
+
+func (r *memoryIdempotencyKeys) Touch(item dbmodel.IdempotencyKey) error {
+	return nil
+}
+
+func TestNewIdempotencyMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	body := []byte(`{"orderId":"1"}`)
+	hash := requestHash(http.MethodPost, "/order", body)
+	old := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name            string
+		stored          *dbmodel.IdempotencyKey
+		touchedAfterGet bool
+		status          int
+		callSupplier    bool
+		wantStatus      int
+		wantCalls       int
+		wantReplayed    bool
+		wantStored      bool
+		wantCompleted   bool
+	}{
+		{
+			name:          "new key",
+			status:        http.StatusOK,
+			wantStatus:    http.StatusOK,
+			wantCalls:     1,
+			wantStored:    true,
+			wantCompleted: true,
+		},
+		{
+			name: "completed key is replayed",
+			stored: &dbmodel.IdempotencyKey{Key: "k", RequestHash: hash, Completed: true,
+				StatusCode: http.StatusOK, Response: `{"replayed":true}`},
+			status:        http.StatusOK,
+			wantStatus:    http.StatusOK,
+			wantReplayed:  true,
+			wantStored:    true,
+			wantCompleted: true,
+		},
+		{
+			name:       "key in progress",
+			stored:     &dbmodel.IdempotencyKey{Key: "k", RequestHash: hash},
+			status:     http.StatusOK,
+			wantStatus: http.StatusConflict,
+			wantStored: true,
+		},
+		{
+			name: "key of another request",
+			stored: &dbmodel.IdempotencyKey{Key: "k", RequestHash: "another", Completed: true,
+				StatusCode: http.StatusOK},
+			status:        http.StatusOK,
+			wantStatus:    http.StatusUnprocessableEntity,
+			wantStored:    true,
+			wantCompleted: true,
+		},
+		{
+			name:       "key is released on an error before the supplier is called",
+			status:     http.StatusBadRequest,
+			wantStatus: http.StatusBadRequest,
+			wantCalls:  1,
+		},
+		{
+			name:          "error after the supplier is called is kept",
+			status:        http.StatusBadRequest,
+			callSupplier:  true,
+			wantStatus:    http.StatusBadRequest,
+			wantCalls:     1,
+			wantStored:    true,
+			wantCompleted: true,
+		},
+		{
+			name: "stale key is reclaimed",
+			stored: &dbmodel.IdempotencyKey{Key: "k", RequestHash: hash, Model: modelAt(old)},
+			status:        http.StatusOK,
+			wantStatus:    http.StatusOK,
+			wantCalls:     1,
+			wantStored:    true,
+			wantCompleted: true,
+		},
+		{
+			name: "stale key kept alive by another request",
+			stored: &dbmodel.IdempotencyKey{Key: "k", RequestHash: hash, Model: modelAt(old)},
+			touchedAfterGet: true,
+			status:          http.StatusOK,
+			wantStatus:      http.StatusConflict,
+			wantStored:      true,
+		},
+		{
+			name: "expired key of another request is reclaimed",
+			stored: &dbmodel.IdempotencyKey{Key: "k", RequestHash: "another", Completed: true,
+				StatusCode: http.StatusOK, Model: modelAt(old.Add(-48 * time.Hour))},
+			status:        http.StatusOK,
+			wantStatus:    http.StatusOK,
+			wantCalls:     1,
+			wantStored:    true,
+			wantCompleted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &memoryIdempotencyKeys{items: map[string]dbmodel.IdempotencyKey{}}
+			if tt.stored != nil {
+				if tt.stored.CreatedAt.IsZero() {
+					tt.stored.Model = modelAt(time.Now())
+				}
+				repository.items[tt.stored.Key] = *tt.stored
+			}
+			repository.touchedAfterGet = tt.touchedAfterGet
+			calls := 0
+			router := gin.New()
+			router.POST("/order", NewIdempotencyMiddleware(repository, 24*time.Hour, time.Minute), func(c *gin.Context) {
+				calls++
+				if tt.callSupplier {
+					sideeffect.Mark(c.Request.Context())
+				}
+				c.JSON(tt.status, gin.H{"calls": calls})
+			})
+
+			request := httptest.NewRequest(http.MethodPost, "/order", bytes.NewReader(body))
+			request.Header.Set(IdempotencyKeyHeader, "k")
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("status = %v, want %v", recorder.Code, tt.wantStatus)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("handler calls = %v, want %v", calls, tt.wantCalls)
+			}
+			if replayed := recorder.Header().Get(IdempotencyReplayedHeader) == "true"; replayed != tt.wantReplayed {
+				t.Errorf("replayed = %v, want %v", replayed, tt.wantReplayed)
+			}
+			item, stored := repository.items["k"]
+			if stored != tt.wantStored {
+				t.Fatalf("key stored = %v, want %v", stored, tt.wantStored)
+			}
+			if stored && item.Completed != tt.wantCompleted {
+				t.Errorf("key completed = %v, want %v", item.Completed, tt.wantCompleted)
+			}
+			if stored && tt.wantCalls > 0 && (item.StatusCode != tt.wantStatus || item.RequestHash != hash) {
+				t.Errorf("stored key = %+v, want the response of the request", item)
+			}
+		})
+	}
+}
+
+func modelAt(at time.Time) gorm.Model {
+	return gorm.Model{ID: 1, CreatedAt: at, UpdatedAt: at}
+}
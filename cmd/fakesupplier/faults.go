@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+//errorRule fails the requests of an endpoint with the status code at the given rate. the "*" path matches every endpoint
+type errorRule struct {
+	path   string
+	status int
+	rate   float64
+}
+
+type faults struct {
+	latency time.Duration
+	jitter  time.Duration
+	rules   []errorRule
+	lock    sync.Mutex
+	random  *rand.Rand
+}
+
+//inject delays the response and fails it when an error rule matches the request path
+func (f *faults) inject(c *gin.Context) {
+	if delay := f.delay(); delay > 0 {
+		time.Sleep(delay)
+	}
+	rule, found := f.match(c.Request.URL.Path)
+	if !found {
+		c.Next()
+		return
+	}
+	if rule.status == http.StatusGatewayTimeout {
+		c.Data(rule.status, "text/html", []byte("<html><body><h1>504 Gateway Time-out</h1></body></html>"))
+		c.Abort()
+		return
+	}
+	c.AbortWithStatusJSON(rule.status, gin.H{
+		"statusCode": rule.status,
+		"error":      http.StatusText(rule.status),
+		"message":    "error injected by the fake supplier",
+	})
+}
+
+func (f *faults) delay() time.Duration {
+	if f.jitter <= 0 {
+		return f.latency
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.latency + time.Duration(f.random.Int63n(int64(f.jitter)))
+}
+
+func (f *faults) match(path string) (errorRule, bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for _, rule := range f.rules {
+		if rule.path != "*" && rule.path != path {
+			continue
+		}
+		if f.random.Float64() < rule.rate {
+			return rule, true
+		}
+	}
+	return errorRule{}, false
+}
+
+//parseErrorRules reads rules like "/api/v1/hotel/result=503:0.5,*=500:0.01", the rate is optional and defaults to 1
+func parseErrorRules(definition string) ([]errorRule, error) {
+	rules := make([]errorRule, 0)
+	if strings.TrimSpace(definition) == "" {
+		return rules, nil
+	}
+	for _, part := range strings.Split(definition, ",") {
+		pair := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(pair) != 2 || pair[0] == "" {
+			return nil, fmt.Errorf("invalid error rule %q", part)
+		}
+		value := strings.SplitN(pair[1], ":", 2)
+		status, err := strconv.Atoi(value[0])
+		if err != nil || status < 400 || status > 599 {
+			return nil, fmt.Errorf("invalid status code in error rule %q", part)
+		}
+		rate := 1.0
+		if len(value) == 2 {
+			if rate, err = strconv.ParseFloat(value[1], 64); err != nil {
+				return nil, err
+			}
+		}
+		if rate < 0 || rate > 1 {
+			return nil, errors.New("error rule rate must be between 0 and 1")
+		}
+		rules = append(rules, errorRule{path: pair[0], status: status, rate: rate})
+	}
+	return rules, nil
+}
+
+func newFaults(latency, jitter time.Duration, rules []errorRule) *faults {
+	return &faults{
+		latency: latency,
+		jitter:  jitter,
+		rules:   rules,
+		random:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
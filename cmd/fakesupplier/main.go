@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+//fakesupplier is a local stand in for the alibaba supplier endpoints. point HOTEL_ENGINE_HOTEL_PROVIDER_ENDPOINT,
+//HOTEL_ENGINE_ORDER_ENDPOINT and HOTEL_ENGINE_ORDER_SERVICE_ENDPOINT at it to run the engine offline.
+//
+//	go run ./cmd/fakesupplier -addr :8090 -fixtures ./fixtures -pending-polls 2 -latency 100ms \
+//		-errors "/api/v1/hotel/result=503:0.2,*=500:0.01"
+//
+//with -record the requests are proxied to the real supplier and the successful responses are stored as fixtures
+func main() {
+	addr := flag.String("addr", ":8090", "address to listen on")
+	fixturesDir := flag.String("fixtures", "", "directory of recorded json fixtures replayed instead of the built in responses")
+	record := flag.String("record", "", "upstream supplier url, requests are proxied to it and responses are stored in the fixtures directory")
+	latency := flag.Duration("latency", 0, "latency added to every response")
+	jitter := flag.Duration("jitter", 0, "random latency added on top of -latency")
+	pendingPolls := flag.Int("pending-polls", 1, "number of not final responses returned for each session before the final result")
+	errorRules := flag.String("errors", "", "comma separated error injection rules like \"/api/v1/hotel/result=503:0.5,*=500:0.01\"")
+	hotels := flag.Int("hotels", 20, "number of hotels served by the built in responses")
+	tokenTtl := flag.Duration("token-ttl", time.Hour, "lifetime of the issued access tokens")
+	flag.Parse()
+
+	if *record != "" && *fixturesDir == "" {
+		log.Fatalln("-record needs a -fixtures directory to store the responses")
+	}
+	rules, err := parseErrorRules(*errorRules)
+	if err != nil {
+		log.Fatalln("invalid -errors value:", err)
+	}
+
+	supplier := newSupplier(*hotels, *tokenTtl, *pendingPolls)
+	faults := newFaults(*latency, *jitter, rules)
+	fixtures := newFixtures(*fixturesDir, *record)
+
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	router.Use(gin.Logger(), gin.Recovery(), faults.inject, fixtures.record)
+
+	router.POST("/api/v1/hoteladmin/login", fixtures.replay, supplier.login)
+	router.POST("/api/v1/hotel/search/direct", fixtures.replay, supplier.searchDirect)
+	router.POST("/api/v1/hotel/search", fixtures.replay, supplier.search)
+	router.POST("/api/v1/hotel/result", supplier.pending, fixtures.replay, supplier.result)
+	router.POST("/api/v1/hotel/rate/room", supplier.pending, fixtures.replay, supplier.rateRoom)
+	router.POST("/api/v1/hotel/general/info", fixtures.replay, supplier.generalInfo)
+	router.POST("/api/v1/hotel/rooms/getCancellationPolicy", fixtures.replay, supplier.cancellationPolicy)
+	router.GET("/api/v1/management/refunds", fixtures.replay, supplier.refundsStatus)
+
+	authenticated := router.Group("", supplier.authenticate, fixtures.replay)
+	authenticated.POST("/api/v1/hoteladmin/get-hotels", supplier.hotelsList)
+	authenticated.POST("/api/v2/hotel/book/available", supplier.available)
+	authenticated.POST("/api/v1/coordinator/order/:orderId/confirm", supplier.confirm)
+	authenticated.POST("/api/v1/coordinator/order/:orderId/pay-by-bank-and-account", supplier.pay)
+	authenticated.GET("/api/v1/coordinator/order/:orderId/status", supplier.status)
+	authenticated.POST("/api/v1/profile/refunds", supplier.refund)
+	authenticated.GET("/api/v1/profile/refunds/enquiry/:orderId", supplier.enquiry)
+	authenticated.GET("/api/v1/profile/account/balance", supplier.balance)
+
+	log.Println("fake supplier is listening on", *addr)
+	if err = router.Run(*addr); err != nil {
+		log.Fatalln(err)
+	}
+}
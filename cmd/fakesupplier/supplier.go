@@ -0,0 +1,557 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hotel-engine/core/common"
+	"hotel-engine/core/dto"
+	"hotel-engine/infrastructure/hotelproviderinterface/dtos"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var mealPlans = []string{"RO", "BB", "HB"}
+
+type session struct {
+	polls int
+}
+
+type order struct {
+	id              int64
+	hotelId         string
+	optionId        string
+	totalPrice      int64
+	checkIn         string
+	checkOut        string
+	status          string
+	refundRequestId int64
+	refundPolls     int
+}
+
+//supplier keeps the sessions, orders and tokens of the built in responses in memory
+type supplier struct {
+	lock         sync.Mutex
+	hotels       int
+	tokenTtl     time.Duration
+	pendingPolls int
+	tokens       map[string]time.Time
+	sessions     map[string]*session
+	orders       map[int64]*order
+	lastId       int64
+}
+
+func (s *supplier) nextId() int64 {
+	s.lastId++
+	return s.lastId
+}
+
+func (s *supplier) newSession() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	id := fmt.Sprintf("fake-session-%d", s.nextId())
+	s.sessions[id] = &session{}
+	return id
+}
+
+func (s *supplier) login(c *gin.Context) {
+	s.lock.Lock()
+	token := fmt.Sprintf("fake-token-%d", s.nextId())
+	s.tokens[token] = time.Now().Add(s.tokenTtl)
+	s.lock.Unlock()
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"error":  false,
+		"result": dtos.AccessTokenResult{Token: token, ExpiresIn: int64(s.tokenTtl.Seconds())},
+	})
+}
+
+//authenticate rejects requests without a valid bearer token so token refresh can be exercised with -token-ttl
+func (s *supplier) authenticate(c *gin.Context) {
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	s.lock.Lock()
+	expiresAt, found := s.tokens[token]
+	s.lock.Unlock()
+	if !found || time.Now().After(expiresAt) {
+		abortWithError(c, http.StatusUnauthorized, "access token is not valid")
+		return
+	}
+	c.Next()
+}
+
+//pending answers the polling endpoints with not final results until the session is polled -pending-polls times
+func (s *supplier) pending(c *gin.Context) {
+	body, _ := ioutil.ReadAll(c.Request.Body)
+	c.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	var request struct {
+		SessionId string `json:"sessionId"`
+	}
+	_ = json.Unmarshal(body, &request)
+
+	s.lock.Lock()
+	current, found := s.sessions[request.SessionId]
+	ready := !found || current.polls >= s.pendingPolls
+	if found {
+		current.polls++
+	}
+	s.lock.Unlock()
+	if ready {
+		c.Next()
+		return
+	}
+	if strings.HasSuffix(c.FullPath(), "/result") {
+		c.AbortWithStatusJSON(http.StatusOK, gin.H{
+			"status": "success",
+			"error":  false,
+			"result": gin.H{"result": []interface{}{}, "progress": 50, "info": gin.H{"resultNo": 0}},
+		})
+		return
+	}
+	c.AbortWithStatusJSON(http.StatusOK, gin.H{
+		"status": "success",
+		"error":  false,
+		"result": gin.H{"finalResult": false, "rooms": []interface{}{}},
+	})
+}
+
+func (s *supplier) hotelsList(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	skip, _ := strconv.Atoi(c.DefaultQuery("skip", "0"))
+	var request struct {
+		HotelGiataId string `json:"hotelGiataId"`
+	}
+	_ = c.ShouldBindJSON(&request)
+
+	items := make([]dtos.HotelItem, 0)
+	for i := skip + 1; i <= s.hotels && len(items) < limit; i++ {
+		id := hotelId(i)
+		if request.HotelGiataId != "" && request.HotelGiataId != id {
+			continue
+		}
+		items = append(items, dtos.HotelItem{
+			Id:                id,
+			GiataId:           id,
+			Name:              dtos.HotelItemName{EN: hotelName(i), ENIndex: strings.ToLower(hotelName(i))},
+			CityName:          dtos.HotelItemCityName{EN: "Tehran"},
+			AccommodationType: strconv.Itoa(common.HotelTypes[common.HotelType_Hotel]),
+		})
+	}
+	c.JSON(http.StatusOK, dtos.HotelsListResponse{
+		Status: "success",
+		Result: dtos.HotelsListResult{HotelsList: items, Total: s.hotels},
+	})
+}
+
+func (s *supplier) searchDirect(c *gin.Context) {
+	var request dtos.ProviderRequestSessionDto
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abortWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	sessionId := s.newSession()
+	c.JSON(http.StatusOK, dtos.SearchDirectResponse{
+		BaseResponse: dtos.BaseResponse{Status: "success"},
+		Result: dtos.SearchDirectResult{
+			SearchResult: dtos.SearchResult{SessionId: sessionId},
+			HotelId:      request.Destination.Id,
+		},
+	})
+}
+
+func (s *supplier) search(c *gin.Context) {
+	c.JSON(http.StatusOK, dtos.SearchResponse{
+		BaseResponse: dtos.BaseResponse{Status: "success"},
+		Result:       dtos.SearchResult{SessionId: s.newSession()},
+	})
+}
+
+func (s *supplier) result(c *gin.Context) {
+	var request dtos.ProviderSearchHotelsRequestDto
+	_ = c.ShouldBindJSON(&request)
+	if request.Limit <= 0 {
+		request.Limit = 10
+	}
+	results := make([]dtos.Result, 0)
+	for i := int(request.Skip) + 1; i <= s.hotels && int64(len(results)) < request.Limit; i++ {
+		price := float64(roomPrice(i, 1))
+		results = append(results, dtos.Result{
+			ID:            hotelId(i),
+			Score:         7,
+			Star:          1 + i%5,
+			MinPrice:      price,
+			PricePerNight: price,
+			Usable:        true,
+			Currency:      "IRR",
+			Name:          dtos.Name{En: hotelName(i), Fa: hotelNameFa(i)},
+			Location:      dtos.Location{Type: "Point", Coordinates: hotelCoordinates(i)},
+			Country:       dtos.Country{Code: "IR"},
+			Images:        []string{},
+			Badges:        []dtos.Badge{},
+			Places:        []interface{}{},
+			Facilities:    []int{},
+			Providers:     []interface{}{},
+		})
+	}
+	c.JSON(http.StatusOK, dtos.ResultResponse{
+		BaseResponse: dtos.BaseResponse{Status: "success"},
+		Result: dtos.Result2{
+			Result:    results,
+			Info:      dtos.Info{ResultNo: s.hotels, TotalResultNo: s.hotels},
+			Progress:  100,
+			LastChunk: true,
+		},
+	})
+}
+
+func (s *supplier) rateRoom(c *gin.Context) {
+	var request struct {
+		SessionId string `json:"sessionId"`
+		HotelId   string `json:"hotelId"`
+	}
+	_ = c.ShouldBindJSON(&request)
+	index := hotelIndex(request.HotelId)
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"error":  false,
+		"result": gin.H{
+			"finalResult": true,
+			"hotel": gin.H{
+				"hotelId":      request.HotelId,
+				"name":         gin.H{"en": hotelName(index), "fa": hotelNameFa(index)},
+				"description":  gin.H{"fa": "<p>هتل آزمایشی برای توسعه محلی</p>"},
+				"address":      "Tehran, Fake street " + strconv.Itoa(index),
+				"checkinTime":  "14:00",
+				"checkoutTime": "12:00",
+				"city":         gin.H{"en": "Tehran", "fa": "تهران"},
+				"state":        gin.H{"en": "Tehran", "fa": "تهران"},
+				"country":      gin.H{"en": "Iran", "fa": "ایران", "code": "IR"},
+				"star":         1 + index%5,
+				"score":        7,
+				"location":     gin.H{"type": "Point", "coordinates": hotelCoordinates(index)},
+				"images":       []gin.H{{"url": "https://example.com/hotel.jpg"}},
+				"facilities":   []gin.H{{"id": 1027, "name": gin.H{"en": "Wifi", "fa": "اینترنت"}, "groupId": 1}},
+				"places":       []gin.H{},
+				"badges":       []gin.H{},
+			},
+			"rooms": roomOptions(request.HotelId, index),
+		},
+	})
+}
+
+func (s *supplier) generalInfo(c *gin.Context) {
+	var request struct {
+		HotelId  string `json:"hotelId"`
+		OptionId string `json:"optionId"`
+	}
+	_ = c.ShouldBindJSON(&request)
+	index := hotelIndex(request.HotelId)
+	option := optionNumber(request.OptionId)
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"error":  false,
+		"result": gin.H{
+			"detail": gin.H{
+				"price":            roomPrice(index, option),
+				"provider":         "fake",
+				"currency":         "IRR",
+				"mealPlan":         mealPlans[(option-1)%len(mealPlans)],
+				"restrictedMarkup": gin.H{"amount": 0, "type": "Fixed"},
+				"rooms":            []gin.H{{"name": "Double room"}},
+			},
+			"policy": cancellationPolicy(option),
+		},
+	})
+}
+
+func (s *supplier) cancellationPolicy(c *gin.Context) {
+	var request struct {
+		OptionId string `json:"optionId"`
+	}
+	_ = c.ShouldBindJSON(&request)
+	c.JSON(http.StatusOK, gin.H{"policy": cancellationPolicy(optionNumber(request.OptionId))})
+}
+
+func (s *supplier) available(c *gin.Context) {
+	var request dto.AvailableDto
+	if err := c.ShouldBindJSON(&request); err != nil {
+		abortWithError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.lock.Lock()
+	created := &order{
+		id:         s.nextId(),
+		hotelId:    request.HotelId,
+		optionId:   request.OptionId,
+		totalPrice: roomPrice(hotelIndex(request.HotelId), optionNumber(request.OptionId)),
+		checkIn:    request.CheckIn,
+		checkOut:   request.CheckOut,
+		status:     "Reserved",
+	}
+	s.orders[created.id] = created
+	s.lock.Unlock()
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"error":  false,
+		"result": gin.H{
+			"id":         created.id,
+			"totalPrice": created.totalPrice,
+			"details": gin.H{
+				"orderId": strconv.FormatInt(created.id, 10),
+				"status":  created.status,
+				"detail":  gin.H{"checkIn": created.checkIn, "checkOut": created.checkOut},
+			},
+		},
+	})
+}
+
+func (s *supplier) confirm(c *gin.Context) {
+	if _, found := s.updateOrder(c, "Confirmed"); found {
+		c.JSON(http.StatusOK, gin.H{"status": "success", "error": false, "result": true})
+	}
+}
+
+func (s *supplier) pay(c *gin.Context) {
+	if current, found := s.updateOrder(c, "Issued"); found {
+		c.JSON(http.StatusOK, gin.H{
+			"status": "success",
+			"error":  false,
+			"result": gin.H{
+				"transactionStatus": "Succeeded",
+				"requestId":         fmt.Sprintf("fake-request-%d", current.id),
+				"transactionIds":    []string{fmt.Sprintf("fake-transaction-%d", current.id)},
+				"resultMessage":     "",
+			},
+		})
+	}
+}
+
+func (s *supplier) status(c *gin.Context) {
+	if current, found := s.updateOrder(c, ""); found {
+		c.JSON(http.StatusOK, gin.H{"status": "success", "error": false, "result": current.status})
+	}
+}
+
+func (s *supplier) enquiry(c *gin.Context) {
+	current, found := s.updateOrder(c, "")
+	if !found {
+		return
+	}
+	penalty := current.totalPrice / 10
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"error":  false,
+		"result": gin.H{
+			"allowedRefundPaymentMethods": []string{"UserAccount"},
+			"items": []gin.H{{
+				"providerId":          c.Query("providerId"),
+				"productProviderType": "DomesticHotel",
+				"destination":         current.hotelId,
+				"destinationName":     hotelName(hotelIndex(current.hotelId)),
+				"items": []gin.H{{
+					"referenceCode":      referenceCode(current.id),
+					"isRefundable":       true,
+					"paidAmount":         current.totalPrice,
+					"totalPenaltyAmount": penalty,
+					"refundableAmount":   current.totalPrice - penalty,
+					"refundableType":     "Partial",
+					"refundableStatus":   "Refundable",
+					"refundStatus":       "None",
+					"passengerInformation": gin.H{
+						"title": "Mr", "name": "Fake", "lastName": "Guest",
+						"namePersian": "مهمان", "lastNamePersian": "آزمایشی",
+					},
+				}},
+			}},
+		},
+	})
+}
+
+func (s *supplier) refund(c *gin.Context) {
+	var request dtos.RefundRequestDto
+	_ = c.ShouldBindJSON(&request)
+	id, _ := strconv.ParseInt(request.OrderId, 10, 64)
+	s.lock.Lock()
+	current, found := s.orders[id]
+	if found && current.refundRequestId == 0 {
+		current.refundRequestId = s.nextId()
+		current.status = "RefundRequested"
+	}
+	s.lock.Unlock()
+	if !found {
+		abortWithError(c, http.StatusNotFound, "order is not found")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"error":  false,
+		"result": gin.H{"refundRequestId": current.refundRequestId},
+	})
+}
+
+//refundsStatus finalizes a refund after it is queried -pending-polls times
+func (s *supplier) refundsStatus(c *gin.Context) {
+	items := make([]dto.OrdersRefundStatusResponseDtoResultItem, 0)
+	s.lock.Lock()
+	for _, value := range c.QueryArray("orderIds") {
+		id, _ := strconv.ParseInt(value, 10, 64)
+		current, found := s.orders[id]
+		if !found || current.refundRequestId == 0 {
+			continue
+		}
+		status := "InProgress"
+		if current.refundPolls >= s.pendingPolls {
+			status = common.RefundStatus_PaymentFinalized
+			current.status = "Refunded"
+		}
+		current.refundPolls++
+		penalty := float32(current.totalPrice / 10)
+		items = append(items, dto.OrdersRefundStatusResponseDtoResultItem{
+			RefundRequestId:     current.refundRequestId,
+			OrderId:             current.id,
+			RequestedRefundType: "Personal",
+			RefundStatus:        status,
+			RefundPaymentMethod: "UserAccount",
+			ReferenceCodes:      referenceCode(current.id),
+			Items: []dto.OrdersRefundStatusResponseDtoResultItemItem{{
+				ReferenceCode:      referenceCode(current.id),
+				TotalPenaltyAmount: penalty,
+				TotalAmount:        float32(current.totalPrice),
+				PaidAmount:         float32(current.totalPrice),
+				RefundableAmount:   float32(current.totalPrice) - penalty,
+				IsRefunded:         status == common.RefundStatus_PaymentFinalized,
+			}},
+		})
+	}
+	s.lock.Unlock()
+	c.JSON(http.StatusOK, dto.OrdersRefundStatusResponseDto{
+		Success: true,
+		Result: dto.OrdersRefundStatusResponseDtoResult{
+			PageNumber: 1,
+			PageSize:   len(items),
+			TotalCount: len(items),
+			Items:      items,
+		},
+	})
+}
+
+func (s *supplier) balance(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "success", "error": false, "result": gin.H{"balance": 1000000000}})
+}
+
+//updateOrder finds the order of the orderId path parameter and sets its status when one is given
+func (s *supplier) updateOrder(c *gin.Context, status string) (order, bool) {
+	id, _ := strconv.ParseInt(c.Param("orderId"), 10, 64)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	current, found := s.orders[id]
+	if !found {
+		abortWithError(c, http.StatusNotFound, "order is not found")
+		return order{}, false
+	}
+	if status != "" {
+		current.status = status
+	}
+	return *current, true
+}
+
+func abortWithError(c *gin.Context, status int, message string) {
+	c.AbortWithStatusJSON(status, dtos.ErrorResponseDto{
+		StatusCode: status,
+		Error:      http.StatusText(status),
+		Message:    message,
+	})
+}
+
+func roomOptions(hotelId string, index int) []gin.H {
+	options := make([]gin.H, 0, len(mealPlans))
+	for option := 1; option <= len(mealPlans); option++ {
+		price := roomPrice(index, option)
+		options = append(options, gin.H{
+			"id":            fmt.Sprintf("%s-option-%d", hotelId, option),
+			"providerName":  "Fake",
+			"provider":      "fake",
+			"nonRefundable": option == len(mealPlans),
+			"mealPlan":      mealPlans[option-1],
+			"currency":      "IRR",
+			"price":         price,
+			"oldPrice":      price + price/10,
+			"rooms": []gin.H{{
+				"name":          "اتاق دو تخته",
+				"name_en":       "Double room",
+				"price":         price,
+				"pricePerNight": price,
+			}},
+		})
+	}
+	return options
+}
+
+func cancellationPolicy(option int) gin.H {
+	return gin.H{
+		"nonRefundable": option == len(mealPlans),
+		"general": gin.H{"policies": []string{
+			"لغو تا ۷۲ ساعت قبل از ورود بدون جریمه",
+			"لغو کمتر از ۷۲ ساعت قبل از ورود ۵۰ درصد جریمه دارد",
+		}},
+	}
+}
+
+func hotelId(index int) string {
+	return strconv.Itoa(1000 + index)
+}
+
+func hotelIndex(hotelId string) int {
+	id, err := strconv.Atoi(hotelId)
+	if err != nil || id <= 1000 {
+		return 1
+	}
+	return id - 1000
+}
+
+func hotelName(index int) string {
+	return fmt.Sprintf("Fake Hotel %d", index)
+}
+
+func hotelNameFa(index int) string {
+	return fmt.Sprintf("هتل آزمایشی %d", index)
+}
+
+func hotelCoordinates(index int) []float64 {
+	return []float64{51.3890 + float64(index)*0.001, 35.6892 + float64(index)*0.001}
+}
+
+func roomPrice(index, option int) int64 {
+	return int64(1000000*(10+index%7) + 250000*(option-1))
+}
+
+func optionNumber(optionId string) int {
+	parts := strings.Split(optionId, "-option-")
+	number, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil || number < 1 || number > len(mealPlans) {
+		return 1
+	}
+	return number
+}
+
+func referenceCode(orderId int64) string {
+	return fmt.Sprintf("FAKE-%d", orderId)
+}
+
+func newSupplier(hotels int, tokenTtl time.Duration, pendingPolls int) *supplier {
+	return &supplier{
+		hotels:       hotels,
+		tokenTtl:     tokenTtl,
+		pendingPolls: pendingPolls,
+		tokens:       make(map[string]time.Time),
+		sessions:     make(map[string]*session),
+		orders:       make(map[int64]*order),
+	}
+}
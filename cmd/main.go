@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"hotel-engine/application/api"
 	"hotel-engine/application/api/handlers"
+	"hotel-engine/application/middlewares"
 	"hotel-engine/cmd/docs"
 	"hotel-engine/core"
+	"hotel-engine/core/common"
 	"hotel-engine/core/common/logtags"
 	"hotel-engine/core/logic"
 	"hotel-engine/core/logic/balancenotifiers"
@@ -44,9 +46,15 @@ func main() {
 	db = sql.InitDatabase(c.ConnectionString)
 	defer db.Close()
 	health.ConfigureHealthChecks(db)
+	health.Add(health.NewCircuitBreakerHealthChecker("providerCircuitBreakers", provider.Breakers()))
 	unit := repository.NewUnitOfWork(db)
 	hotelMapper := mapper.NewHotelMapper()
-	hotelProvider := provider.NewHotelProvider()
+	hotelProviders := logic.NewHotelProviderRegistry(c.DefaultSupplier)
+	err := hotelProviders.Register(common.Supplier_Alibaba, provider.NewHotelProvider())
+	if err != nil {
+		logger.WithName(logtags.RegisterSupplierError).
+			PanicException(err, "error while registering a supplier")
+	}
 	basicInfoProvider := provider.NewBasicInformationProvider()
 	messagingClient := messaging.NewBusClient(c.Rabbitmq.ConnectionString)
 
@@ -54,9 +62,10 @@ func main() {
 	providerSearchDtoFactory := logic.NewProviderSearchDtoFactory(cacheStore)
 	balanceCheckerService := logic.NewProviderBalanceChecker(balancenotifiers.CreateBalanceAlertNotifiers())
 	publicService := logic.NewPublicService(unit, hotelMapper, cacheStore, basicInfoProvider)
-	orderEventDispatcher := logic.NewOrderEventDispatcher(messagingClient, c.RefundEventTopic)
-	hotelService := logic.NewHotelService(unit, hotelMapper, hotelProvider, providerSearchDtoFactory,
-		publicService, cacheStore, balanceCheckerService, orderEventDispatcher)
+	orderEventDispatcher := logic.NewOrderEventDispatcher(messagingClient, c.RefundEventTopic, c.OrderEventTopic)
+	redisMemoryStorage := logic.NewRedisLocker()
+	hotelService := logic.NewHotelService(unit, hotelMapper, hotelProviders, providerSearchDtoFactory,
+		publicService, cacheStore, balanceCheckerService, orderEventDispatcher, logic.NewTtlCache(), redisMemoryStorage)
 
 	logic.NewRateReviewEventHandler(messagingClient, hotelService, c.RateReviewSubscribeString)
 	//feeder
@@ -67,13 +76,15 @@ func main() {
 	}
 	defer feeder.Close()
 	syncService := logic.NewSyncService(feeder, unit, hotelService)
-	redisMemoryStorage := logic.NewRedisLocker()
 	jobs.RegisterCronJobs(syncService, hotelService, redisMemoryStorage)
 
 	hotelHandler := handlers.NewHotelHandler(hotelService, syncService)
 	publicHandler := handlers.NewPublicHandler(publicService, balanceCheckerService)
 
-	route := api.CreateRoute(hotelHandler, publicHandler)
+	idempotency := middlewares.NewIdempotencyMiddleware(unit.IdempotencyKey(), c.IdempotencyKey.Ttl,
+		c.IdempotencyKey.StaleAfter)
+
+	route := api.CreateRoute(hotelHandler, publicHandler, idempotency)
 
 	docs.SwaggerInfo.Host = fmt.Sprintf("%s:%v", c.ContainerName, c.ContainerPort)
 	route.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -91,7 +102,7 @@ func close() {
 }
 
 func init() {
-	c := make(chan os.Signal)
+	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		<-c
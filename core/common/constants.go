@@ -32,13 +32,50 @@ var (
 	HotelReserveForbidden          = errors.New("رزرو این هتل فقط در محیط پروداکشن امکان پذیر می باشد")
 	RoomIsNotAvailable             = errors.New("رزرو این اتاق امکال پذیر نیست")
 	DatesNotMatchError             = errors.New("تاریخ های انتخابی مغایرت دارد")
+	ProviderCircuitOpenProblem     = errors.New("provider is temporarily unavailable, circuit breaker is open")
+	ProviderTooManyRequestsProblem = errors.New("too many concurrent requests to provider")
+	SupplierNotFound               = errors.New("supplier is not registered")
+	SupplierAlreadyRegistered      = errors.New("supplier is already registered")
+	ProviderResponseSchemaChanged  = errors.New("provider response does not match the expected schema")
+	ProviderRequestBudgetExhausted = errors.New("provider request budget is exhausted, try again later")
+	InvalidGeoLocation             = errors.New("geo location is not valid")
+	InvalidGeoSearchRadius         = errors.New("geo search radius is not valid")
+	MultipleGeoSearchCriteria      = errors.New("only one of geo, bounding box and near place can be searched")
+	PlaceNotFound                  = errors.New("place cannot be found")
+	InvalidMealPlan                = errors.New("meal plan is not valid")
+	PriceCalendarRangeTooLong      = errors.New("price calendar date range is too long")
+	CancellationPolicyUnknown      = errors.New("قوانین لغو این سفارش قابل محاسبه نیست")
+	RefundAlreadyRequested         = errors.New("refund of the order is already requested")
+	InvalidRefundQuote             = errors.New("refund quote is not valid")
+	RefundQuoteExpired             = errors.New("refund quote is expired, preview the refund again")
+	RefundQuoteChanged             = errors.New("refund penalty has changed since the quote, preview the refund again")
+	RefundReferenceCodeNotFound    = errors.New("reference code is not an item of the order")
+	IdempotencyKeyNotFound         = errors.New("idempotency key cannot be found")
+	IdempotencyKeyReused           = errors.New("idempotency key is already used for another request")
+	IdempotencyKeyInProgress       = errors.New("request of the idempotency key is in progress")
+	OrderSagaNotFound              = errors.New("finalization of the order cannot be found")
+	OrderSagaCompensated           = errors.New("order cannot be finalized and its reservation is cancelled")
+	OrderSagaNeedsSupport          = errors.New("order cannot be finalized and needs the support")
+	OrderIssuedWithoutPayment      = errors.New("order is issued by the supplier but its payment is not stored")
+	OrderPaymentPending            = errors.New("payment of the order is not completed by the supplier yet")
+	OrderPaymentUnknown            = errors.New("result of the payment of the order is not known")
+	OrderSagaInProgress            = errors.New("finalization of the order is running")
 
 	HotelType_Hotel          = "hotel"
 	HotelType_HotelApartment = "hotelapartment"
+
+	Supplier_Alibaba = "alibaba"
 )
 
 const MaxAgeAsAChild = 12
+const DefaultSearchPageSize = 20
+const MaxGeoSearchRadius = 100.0
+const DefaultNearPlaceRadius = 5.0
+const SortByDistance = "distance"
+const DefaultAutocompleteSize = 10
+const MaxAutocompleteSize = 50
 const NonRefundableDefaultMessage = "امکان لغو رزرو وجود ندارد"
+const DefaultCheckInTime = "14:00"
 
 var HotelAmenities = map[int]string{
 	1027: "استخر",
@@ -61,8 +98,16 @@ var HotelTypesString = map[int]string{
 
 const (
 	RefundStatus_PaymentFinalized = "PaymentFinalized"
+	RefundStatus_Rejected         = "Rejected"
+	RefundStatus_Failed           = "Failed"
+	RefundStatus_Canceled         = "Canceled"
+	RefundStatus_Cancelled        = "Cancelled"
 )
 
+//RefundClosedStatuses are the statuses of the refund requests that the supplier does not change anymore
+var RefundClosedStatuses = []string{RefundStatus_PaymentFinalized, RefundStatus_Rejected, RefundStatus_Failed,
+	RefundStatus_Canceled, RefundStatus_Cancelled}
+
 const MaxUint = ^uint(0)
 const MinUint = 0
 const MaxInt = int(MaxUint >> 1)
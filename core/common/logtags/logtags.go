@@ -23,6 +23,8 @@ const (
 	CannotCreateRefundEventError        = "CannotCreateRefundEventError"
 	CannotPublishRefundEventError       = "CannotPublishRefundEventError"
 	RefundRequestCompleted              = "RefundRequestCompleted"
+	CannotCreateOrderEventError         = "CannotCreateOrderEventError"
+	CannotPublishOrderEventError        = "CannotPublishOrderEventError"
 	CastRateReviewEventObjectError      = "CastRateReviewEventObjectError"
 	CannotSubscribeToRateReviewQueue    = "CannotSubscribeToRateReviewQueue"
 	NewHotelIdDetected                  = "NewHotelIdDetected"
@@ -32,6 +34,16 @@ const (
 	GettingListOfHotelsError            = "GettingListOfHotelsError"
 	FeedElasticError                    = "FeedElasticError"
 	SearchCompleted                     = "SearchCompleted"
+	SearchFallbackUsed                  = "SearchFallbackUsed"
+	SearchFacetsError                   = "SearchFacetsError"
+	PriceCalendarError                  = "PriceCalendarError"
+	SyncHotelCalendarError              = "SyncHotelCalendarError"
+	SyncingHotelCalendarsCompleted      = "SyncingHotelCalendarsCompleted"
+	CancellationPolicyNotParsed         = "CancellationPolicyNotParsed"
+	RefundPolicyMismatch                = "RefundPolicyMismatch"
+	RefundQuoteRejected                 = "RefundQuoteRejected"
+	FinishingOrderRefundError           = "FinishingOrderRefundError"
+	OrderTransitionRejected             = "OrderTransitionRejected"
 	CallingAliasError                   = "CallingAliasError"
 	CreatingSeederError                 = "CreatingSeederError"
 	RabbitUnknownError                  = "RabbitUnknownError"
@@ -49,6 +61,25 @@ const (
 	InvalidJsonResponseError            = "InvalidJsonResponseError"
 	GettingCancellationPolicyError      = "GettingCancellationPolicyError"
 	DatesNotMatchError                  = "DatesNotMatchError"
+	RegisterSupplierError               = "RegisterSupplierError"
+	ProviderCircuitBreakerStateChanged  = "ProviderCircuitBreakerStateChanged"
+	ProviderCircuitBreakerHealthCheck   = "ProviderCircuitBreakerHealthCheck"
+	ProviderTokenRefreshed              = "ProviderTokenRefreshed"
+	ProviderTokenRejected               = "ProviderTokenRejected"
+	ProviderSchemaDrift                 = "ProviderSchemaDrift"
+	ProviderRequestThrottled            = "ProviderRequestThrottled"
+	TtlCacheError                       = "TtlCacheError"
+	RoomCacheInvalidated                = "RoomCacheInvalidated"
+	IdempotencyKeyError                 = "IdempotencyKeyError"
+	IdempotencyKeyReplayed              = "IdempotencyKeyReplayed"
+	OrderSagaStepFailed                 = "OrderSagaStepFailed"
+	OrderSagaCompensated                = "OrderSagaCompensated"
+	OrderSagaCompensationFailed         = "OrderSagaCompensationFailed"
+	OrderSagaNeedsSupport               = "OrderSagaNeedsSupport"
+	OrderSagaResumeError                = "OrderSagaResumeError"
+	OrderReconciliationError            = "OrderReconciliationError"
+	OrderReconciliationMismatch         = "OrderReconciliationMismatch"
+	OrderReconciled                     = "OrderReconciled"
 
 	GetAccessTokenRequest        = "GetAccessTokenRequest"
 	SearchHotelsRequest          = "SearchHotelsRequest"
@@ -60,6 +91,7 @@ const (
 	GetOrdersRefundStatusRequest = "GetOrdersRefundStatusRequest"
 	RefundOrderRequest           = "RefundOrderRequest"
 	GettingHotelsListError       = "GettingHotelsListError"
+	GettingOrdersListError       = "GettingOrdersListError"
 
 	GinRequestFailed = "GinRequestFailed"
 )
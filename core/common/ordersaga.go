@@ -0,0 +1,20 @@
+package common
+
+//the statuses of the finalization saga of an order, a saga that cannot be compensated and a paid saga that has no
+//attempt left are left for the support
+const (
+	OrderSagaStatus_Running            = "Running"
+	OrderSagaStatus_Completed          = "Completed"
+	OrderSagaStatus_Compensated        = "Compensated"
+	OrderSagaStatus_CompensationFailed = "CompensationFailed"
+	OrderSagaStatus_NeedsSupport       = "NeedsSupport"
+)
+
+//the steps of the finalization saga, release and refund are the compensations of confirm and pay
+const (
+	OrderSagaStep_Confirm = "Confirm"
+	OrderSagaStep_Pay     = "Pay"
+	OrderSagaStep_Status  = "Status"
+	OrderSagaStep_Release = "Release"
+	OrderSagaStep_Refund  = "Refund"
+)
@@ -0,0 +1,31 @@
+package common
+
+import "fmt"
+
+//the states of the order lifecycle, the supplier status of the order is kept apart from its state
+const (
+	OrderState_Draft           = "Draft"
+	OrderState_Held            = "Held"
+	OrderState_Confirmed       = "Confirmed"
+	OrderState_PaymentPending  = "PaymentPending"
+	OrderState_Paid            = "Paid"
+	OrderState_Issued          = "Issued"
+	OrderState_RefundRequested = "RefundRequested"
+	OrderState_Refunded        = "Refunded"
+	OrderState_Failed          = "Failed"
+	OrderState_Expired         = "Expired"
+)
+
+//TransactionStatus_Pending is the status of a payment that the supplier has not completed yet, like a payment of an
+//account without enough balance
+const TransactionStatus_Pending = "Pending"
+
+//OrderTransitionError is returned when the state of an order does not allow an action
+type OrderTransitionError struct {
+	From string
+	To   string
+}
+
+func (e OrderTransitionError) Error() string {
+	return fmt.Sprintf("order is %s and cannot become %s", e.From, e.To)
+}
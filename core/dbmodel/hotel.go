@@ -10,8 +10,8 @@ import (
 
 type Hotel struct {
 	gorm.Model
-	PlaceID         string `gorm:"column:PlaceId;type:nvarchar(50);not null;unique_index"`
-	HotelCode       string `gorm:"column:HotelCode;type:nvarchar(5);not null;unique_index"`
+	PlaceID         string `gorm:"column:PlaceId;type:nvarchar(50);not null;unique_index:uix_hotels_place_supplier"`
+	HotelCode       string `gorm:"column:HotelCode;type:nvarchar(5);not null;unique_index:uix_hotels_code_supplier"`
 	RoomID          string `gorm:"column:RoomId;type:nvarchar(50);not null"`
 	Type            string `gorm:"column:Type;type:nvarchar(50);not null"`
 	Kind            string `gorm:"column:Kind;type:nvarchar(50);not null"`
@@ -66,6 +66,9 @@ type Hotel struct {
 	SeoCanonical       string `gorm:"column:SeoCanonical;type:nvarchar(4000)"`
 	SeoMetaDescription string `gorm:"column:SeoMetaDescription;type:nvarchar(4000)"`
 	Code               int    `gorm:"column:Code;not null;default:0"`
+	Supplier           string `gorm:"column:Supplier;type:nvarchar(50);not null;default:'alibaba';unique_index:uix_hotels_place_supplier,uix_hotels_code_supplier"`
+
+	CalendarSyncedAt *time.Time `gorm:"column:CalendarSyncedAt;null"`
 }
 
 func (h *Hotel) UpdateWith(newHotel Hotel) *Hotel {
@@ -110,6 +113,9 @@ func (h *Hotel) UpdateWith(newHotel Hotel) *Hotel {
 	h.Address = newHotel.Address
 	h.Type = newHotel.Type
 	h.Sort = newHotel.Sort
+	if newHotel.Supplier != "" {
+		h.Supplier = newHotel.Supplier
+	}
 
 	return h
 }
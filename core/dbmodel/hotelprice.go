@@ -0,0 +1,15 @@
+package dbmodel
+
+import "time"
+
+//HotelPrice is the synced price of a one night stay, the days of a hotel are replaced on every sync. a place id is only
+//unique between the hotels of a supplier so the days are kept per supplier
+type HotelPrice struct {
+	ID        uint      `gorm:"primary_key"`
+	PlaceID   string    `gorm:"column:PlaceId;type:nvarchar(50);not null;unique_index:uix_hotel_price_supplier_day"`
+	Supplier  string    `gorm:"column:Supplier;type:nvarchar(50);not null;default:'alibaba';unique_index:uix_hotel_price_supplier_day"`
+	Date      time.Time `gorm:"column:Date;type:date;not null;unique_index:uix_hotel_price_supplier_day"`
+	Price     int64     `gorm:"column:Price;not null"`
+	OldPrice  int64     `gorm:"column:OldPrice;not null;default:0"`
+	Available bool      `gorm:"column:Available;not null"`
+}
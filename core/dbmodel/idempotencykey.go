@@ -0,0 +1,22 @@
+package dbmodel
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+//IdempotencyKey is a request that the client can retry with the same key, the response is kept once the request is
+//completed so a retry gets the same result
+type IdempotencyKey struct {
+	gorm.Model
+	Key         string `gorm:"column:IdempotencyKey;type:nvarchar(100);not null;unique_index"`
+	RequestHash string `gorm:"column:RequestHash;type:nvarchar(64);not null"`
+	Completed   bool   `gorm:"column:Completed;not null;default:0"`
+	StatusCode  int    `gorm:"column:StatusCode;not null;default:0"`
+	Response    string `gorm:"column:Response;type:nvarchar(max)"`
+}
+
+func (k *IdempotencyKey) Complete(statusCode int, response string) {
+	k.Completed = true
+	k.StatusCode = statusCode
+	k.Response = response
+}
@@ -1,39 +1,97 @@
 package dbmodel
 
 import (
+	"hotel-engine/core/common"
+	"time"
+
 	"github.com/jinzhu/gorm"
 )
 
 type Order struct {
 	gorm.Model
 	ProviderOrderId        string      `gorm:"column:ProviderOrderId;type:nvarchar(50);not null"`
-	IndraOrderId           int64       `gorm:"column:IndraOrderId;not null"`
+	IndraOrderId           int64       `gorm:"column:IndraOrderId;not null;index:idx_order_indra_order_id"`
 	HotelID                uint        `gorm:"column:HotelID;not null"`
-	ProviderHotelId        string      `gorm:"column:ProviderHotelId;type:nvarchar(50);not null"`
+	ProviderHotelId        string      `gorm:"column:ProviderHotelId;type:nvarchar(50);not null;index:idx_order_hotel"`
 	NonRefundable          bool        `gorm:"column:NonRefundable;not null"`
 	GeneralPolicies        string      `gorm:"column:GeneralPolicies;not null,type:nvarchar(2500)"`
 	TotalPrice             int64       `gorm:"column:TotalPrice;not null"`
 	Provider               string      `gorm:"column:Provider;type:nvarchar(50)"`
 	ProviderName           string      `gorm:"column:ProviderName;type:nvarchar(50)"`
+	Supplier               string      `gorm:"column:Supplier;type:nvarchar(50);not null;default:'alibaba'"`
 	Currency               string      `gorm:"column:Currency;type:nvarchar(50);not null"`
 	MealPlan               string      `gorm:"column:MealPlan;type:nvarchar(100);not null"`
 	RestrictedMarkupAmount int64       `gorm:"column:RestrictedMarkupAmount;not null"`
 	RestrictedMarkupType   string      `gorm:"column:RestrictedMarkupType;type:nvarchar(100);not null"`
-	Status                 string      `gorm:"column:Status;type:nvarchar(50);not null"`
+	Status                 string      `gorm:"column:Status;type:nvarchar(50);not null;index:idx_order_status"`
 	Rooms                  []OrderRoom `gorm:"foreignKey:OrderID"`
 	TransactionStatus      string      `gorm:"column:TransactionStatus;type:nvarchar(50);null"`
 	TransactionRequestId   string      `gorm:"column:TransactionRequestId;type:nvarchar(50);null"`
 	TransactionIds         string      `gorm:"column:TransactionIds;type:nvarchar(2500);null"`
 	Confirmed              bool        `gorm:"column:Confirmed;not null;default:0"`
 
+	CheckIn           *time.Time              `gorm:"column:CheckIn;type:date;null;index:idx_order_check_in"`
+	CheckOut          *time.Time              `gorm:"column:CheckOut;type:date;null"`
+	CancellationRules []OrderCancellationRule `gorm:"foreignKey:OrderID"`
+
 	RefundRequestId          int64   `gorm:"column:RefundRequestId;not null;default:0"`
 	ApplicantRefundRequestId int64   `gorm:"column:ApplicantRefundRequestId;not null;default:0"`
-	ApplicantOrderId         int64   `gorm:"column:ApplicantOrderId;not null;default:0"`
+	ApplicantOrderId         int64   `gorm:"column:ApplicantOrderId;not null;default:0;index:idx_order_applicant_order_id"`
 	PaidAmount               float32 `gorm:"column:PaidAmount;not null;type:decimal(10,2);default:0.0"`
 	ReferenceCode            string  `gorm:"column:ReferenceCode;not null;type:nvarchar(50)"`
 	RefundStatus             string  `gorm:"column:RefundStatus;not null;type:nvarchar(50)"`
 	RefundableAmount         float32 `gorm:"column:RefundableAmount;not null;type:decimal(10,2);default:0.0"`
 	TotalPenaltyAmount       float32 `gorm:"column:TotalPenaltyAmount;not null;type:decimal(10,2);default:0.0"`
+
+	Refunds []OrderRefund `gorm:"foreignKey:OrderID"`
+
+	State        string                 `gorm:"column:State;type:nvarchar(50);not null;default:'';index:idx_order_state"`
+	StateHistory []OrderStateTransition `gorm:"foreignKey:OrderID"`
+
+	ReconciliationMismatch string `gorm:"column:ReconciliationMismatch;type:nvarchar(2500);not null;default:''"`
+
+	PhoneNumber string `gorm:"column:PhoneNumber;type:nvarchar(20);not null;default:'';index:idx_order_phone_number"`
+}
+
+//OrderStateTotal is the number and the price of the orders of a state
+type OrderStateTotal struct {
+	State      string `gorm:"column:State"`
+	Count      int    `gorm:"column:Count"`
+	TotalPrice int64  `gorm:"column:TotalPrice"`
+}
+
+//CurrentState returns the state of the order, the orders that were stored before the states were kept get their
+//state from the old status fields
+func (h *Order) CurrentState() string {
+	switch {
+	case h.State != "":
+		return h.State
+	case h.RefundStatus == common.RefundStatus_PaymentFinalized:
+		return common.OrderState_Refunded
+	case h.RefundRequestId != 0:
+		return common.OrderState_RefundRequested
+	case h.TransactionRequestId != "" && h.Status == common.OrderState_Issued:
+		return common.OrderState_Issued
+	case h.TransactionRequestId != "" && h.TransactionStatus == common.TransactionStatus_Pending:
+		return common.OrderState_PaymentPending
+	case h.TransactionRequestId != "":
+		return common.OrderState_Paid
+	case h.Confirmed:
+		return common.OrderState_Confirmed
+	}
+	return common.OrderState_Held
+}
+
+//UpdateState moves the order to the state and records the transition, the guards of the transitions are the ones of
+//the order service
+func (h *Order) UpdateState(state, event string) {
+	h.StateHistory = append(h.StateHistory, OrderStateTransition{
+		OrderID:   h.ID,
+		FromState: h.CurrentState(),
+		ToState:   state,
+		Event:     event,
+	})
+	h.State = state
 }
 
 func (h *Order) UpdateStatus(status string) {
@@ -65,3 +123,7 @@ func (h *Order) UpdateTransaction(transactionStatus, transactionRequestId, trans
 	h.TransactionRequestId = transactionRequestId
 	h.TransactionIds = transactionIds
 }
+
+func (h *Order) UpdateReconciliationMismatch(mismatch string) {
+	h.ReconciliationMismatch = mismatch
+}
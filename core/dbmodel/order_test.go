@@ -0,0 +1,62 @@
+package dbmodel
+
+import (
+	"hotel-engine/core/common"
+	"testing"
+)
+
+func TestOrder_CurrentState(t *testing.T) {
+	tests := []struct {
+		name  string
+		order Order
+		want  string
+	}{
+		{
+			name:  "stored state",
+			order: Order{State: common.OrderState_Failed, TransactionRequestId: "1", Confirmed: true},
+			want:  common.OrderState_Failed,
+		},
+		{
+			name:  "legacy refunded order",
+			order: Order{RefundStatus: common.RefundStatus_PaymentFinalized, RefundRequestId: 1, TransactionRequestId: "1"},
+			want:  common.OrderState_Refunded,
+		},
+		{
+			name:  "legacy refund request",
+			order: Order{RefundRequestId: 1, TransactionRequestId: "1", Status: common.OrderState_Issued},
+			want:  common.OrderState_RefundRequested,
+		},
+		{
+			name:  "legacy issued order",
+			order: Order{TransactionRequestId: "1", Status: common.OrderState_Issued, Confirmed: true},
+			want:  common.OrderState_Issued,
+		},
+		{
+			name:  "legacy pending payment",
+			order: Order{TransactionRequestId: "1", TransactionStatus: common.TransactionStatus_Pending, Confirmed: true},
+			want:  common.OrderState_PaymentPending,
+		},
+		{
+			name:  "legacy paid order",
+			order: Order{TransactionRequestId: "1", Confirmed: true},
+			want:  common.OrderState_Paid,
+		},
+		{
+			name:  "legacy confirmed order",
+			order: Order{Confirmed: true},
+			want:  common.OrderState_Confirmed,
+		},
+		{
+			name:  "legacy held order",
+			order: Order{},
+			want:  common.OrderState_Held,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.order.CurrentState(); got != tt.want {
+				t.Errorf("CurrentState() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package dbmodel
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+//OrderCancellationRule is a parsed rule of the cancellation policy of an order, the deadline is null for the rules
+//that apply from the reservation
+type OrderCancellationRule struct {
+	gorm.Model
+	OrderID            uint       `gorm:"column:OrderID;not null"`
+	FromBooking        bool       `gorm:"column:FromBooking;not null;default:0"`
+	HoursBeforeCheckIn int        `gorm:"column:HoursBeforeCheckIn;not null;default:0"`
+	Deadline           *time.Time `gorm:"column:Deadline;null"`
+	PenaltyPercent     float64    `gorm:"column:PenaltyPercent;not null;type:decimal(5,2);default:0.0"`
+	PenaltyAmount      int64      `gorm:"column:PenaltyAmount;not null;default:0"`
+	PenaltyNights      int        `gorm:"column:PenaltyNights;not null;default:0"`
+}
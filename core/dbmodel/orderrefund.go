@@ -0,0 +1,55 @@
+package dbmodel
+
+import (
+	"hotel-engine/core/common"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+//OrderRefund is a refund request of an order, the reference codes are the refunded rooms or passengers of the
+//enquiry and are empty when the whole order is refunded
+type OrderRefund struct {
+	gorm.Model
+	OrderID                  uint    `gorm:"column:OrderID;not null"`
+	Order                    *Order  `gorm:"foreignKey:OrderID;association_autoupdate:false;association_autocreate:false"`
+	RefundRequestId          int64   `gorm:"column:RefundRequestId;not null"`
+	ApplicantRefundRequestId int64   `gorm:"column:ApplicantRefundRequestId;not null;default:0"`
+	ApplicantOrderId         int64   `gorm:"column:ApplicantOrderId;not null;default:0;index:idx_order_refund_applicant_order_id"`
+	ReferenceCodes           string  `gorm:"column:ReferenceCodes;type:nvarchar(2500);not null"`
+	RefundStatus             string  `gorm:"column:RefundStatus;type:nvarchar(50);not null"`
+	PaidAmount               float32 `gorm:"column:PaidAmount;not null;type:decimal(10,2);default:0.0"`
+	RefundableAmount         float32 `gorm:"column:RefundableAmount;not null;type:decimal(10,2);default:0.0"`
+	TotalPenaltyAmount       float32 `gorm:"column:TotalPenaltyAmount;not null;type:decimal(10,2);default:0.0"`
+}
+
+//Codes returns the refunded reference codes, nil is the whole order
+func (r *OrderRefund) Codes() []string {
+	if r.ReferenceCodes == "" {
+		return nil
+	}
+	return strings.Split(r.ReferenceCodes, ",")
+}
+
+//Closed reports whether the supplier has finished the refund request
+func (r *OrderRefund) Closed() bool {
+	for _, status := range common.RefundClosedStatuses {
+		if r.RefundStatus == status {
+			return true
+		}
+	}
+	return false
+}
+
+//Rejected reports whether the supplier has finished the refund request without refunding it
+func (r *OrderRefund) Rejected() bool {
+	return r.Closed() && r.RefundStatus != common.RefundStatus_PaymentFinalized
+}
+
+func (r *OrderRefund) UpdateRefundResult(paidAmount float32, refundStatus string,
+	refundableAmount float32, totalPenaltyAmount float32) {
+	r.PaidAmount = paidAmount
+	r.RefundStatus = refundStatus
+	r.RefundableAmount = refundableAmount
+	r.TotalPenaltyAmount = totalPenaltyAmount
+}
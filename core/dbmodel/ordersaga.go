@@ -0,0 +1,67 @@
+package dbmodel
+
+import (
+	"hotel-engine/core/common"
+
+	"github.com/jinzhu/gorm"
+)
+
+//OrderSaga is the finalization of an order, every step is recorded so a finalization that is stopped is resumed from
+//the last completed step
+type OrderSaga struct {
+	gorm.Model
+	OrderID      uint            `gorm:"column:OrderID;not null;unique_index"`
+	IndraOrderId int64           `gorm:"column:IndraOrderId;not null"`
+	Status       string          `gorm:"column:Status;type:nvarchar(50);not null"`
+	Step         string          `gorm:"column:Step;type:nvarchar(50);not null;default:''"`
+	Attempts     int             `gorm:"column:Attempts;not null;default:0"`
+	LastError    string          `gorm:"column:LastError;type:nvarchar(max)"`
+	Steps        []OrderSagaStep `gorm:"foreignKey:OrderSagaID"`
+}
+
+//OrderSagaStep is a run of a step of the saga, a failed step is recorded with its error
+type OrderSagaStep struct {
+	gorm.Model
+	OrderSagaID uint   `gorm:"column:OrderSagaID;not null"`
+	Step        string `gorm:"column:Step;type:nvarchar(50);not null"`
+	Succeeded   bool   `gorm:"column:Succeeded;not null"`
+	Error       string `gorm:"column:Error;type:nvarchar(max)"`
+}
+
+//Passed reports whether the step is already completed
+func (s *OrderSaga) Passed(step string) bool {
+	for _, item := range s.Steps {
+		if item.Step == step && item.Succeeded {
+			return true
+		}
+	}
+	return false
+}
+
+//Tried reports whether the step has run, even when it has failed
+func (s *OrderSaga) Tried(step string) bool {
+	for _, item := range s.Steps {
+		if item.Step == step {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *OrderSaga) CompleteStep(step string) {
+	s.Steps = append(s.Steps, OrderSagaStep{OrderSagaID: s.ID, Step: step, Succeeded: true})
+	s.Step = step
+}
+
+func (s *OrderSaga) FailStep(step string, err error) {
+	s.Steps = append(s.Steps, OrderSagaStep{OrderSagaID: s.ID, Step: step, Error: err.Error()})
+	s.LastError = err.Error()
+}
+
+func (s *OrderSaga) UpdateStatus(status string) {
+	s.Status = status
+}
+
+func (s *OrderSaga) Finished() bool {
+	return s.Status == common.OrderSagaStatus_Completed || s.Status == common.OrderSagaStatus_Compensated
+}
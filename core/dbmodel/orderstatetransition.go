@@ -0,0 +1,14 @@
+package dbmodel
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+//OrderStateTransition is a change of the state of an order, the event is the action that changed it
+type OrderStateTransition struct {
+	gorm.Model
+	OrderID   uint   `gorm:"column:OrderID;not null"`
+	FromState string `gorm:"column:FromState;type:nvarchar(50);not null"`
+	ToState   string `gorm:"column:ToState;type:nvarchar(50);not null"`
+	Event     string `gorm:"column:Event;type:nvarchar(100);not null"`
+}
@@ -8,6 +8,7 @@ type SetHotelFaqRequestDto struct {
 	HotelId  string        `json:"hotelId"`
 	FAQTitle string        `json:"faqTitle"`
 	FAQList  []HotelFAQDto `json:"faqList"`
+	Supplier string        `json:"supplier,omitempty"`
 }
 
 type HotelFAQDto struct {
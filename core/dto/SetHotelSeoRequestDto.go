@@ -7,6 +7,7 @@ import (
 type SetHotelSeoRequestDto struct {
 	HotelId    string      `json:"hotelId"`
 	SeoDetails HotelSeoDto `json:"seoDetails"`
+	Supplier   string      `json:"supplier,omitempty"`
 }
 
 func (a SetHotelSeoRequestDto) Validate() error {
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"hotel-engine/utils/indraframework"
+)
+
+const (
+	AutocompleteCity     = "city"
+	AutocompleteProvince = "province"
+	AutocompleteHotel    = "hotel"
+)
+
+type AutocompleteItemDto struct {
+	Id       string `json:"id"`
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	NameEn   string `json:"nameEn,omitempty"`
+	City     string `json:"city,omitempty"`
+	Province string `json:"province,omitempty"`
+}
+
+type AutocompleteResponseDto struct {
+	Items []AutocompleteItemDto          `json:"items"`
+	Error *indraframework.IndraException `json:"error"`
+}
+
+func (a *AutocompleteResponseDto) SetError(exc *indraframework.IndraException) {
+	a.Error = exc
+}
+
+func NewAutocompleteResponseDto(items []AutocompleteItemDto) AutocompleteResponseDto {
+	return AutocompleteResponseDto{Items: items}
+}
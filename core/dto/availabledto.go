@@ -15,6 +15,7 @@ type AvailableDto struct {
 	CheckIn     string             `json:"checkIn"`
 	CheckOut    string             `json:"checkOut"`
 	Rooms       []AvailableRoomDto `json:"rooms"`
+	Supplier    string             `json:"supplier,omitempty"`
 }
 
 type AvailableRoomDto struct {
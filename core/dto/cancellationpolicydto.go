@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"hotel-engine/utils/indraframework"
+	"time"
+)
+
+//CancellationRuleDto is a penalty of cancelling a reservation. the rule applies from HoursBeforeCheckIn hours before
+//the check in, or from the reservation when FromBooking is set, zero hours is a no show. the penalty is the largest of
+//the percent of the total price, the amount and the price of the nights. the deadline is only known for an order
+type CancellationRuleDto struct {
+	FromBooking        bool       `json:"fromBooking"`
+	HoursBeforeCheckIn int        `json:"hoursBeforeCheckIn"`
+	Deadline           *time.Time `json:"deadline,omitempty"`
+	PenaltyPercent     float64    `json:"penaltyPercent"`
+	PenaltyAmount      int64      `json:"penaltyAmount"`
+	PenaltyNights      int        `json:"penaltyNights"`
+}
+
+type CancellationPenaltyDto struct {
+	OrderId          string                         `json:"orderId"`
+	At               time.Time                      `json:"at"`
+	TotalPrice       int64                          `json:"totalPrice"`
+	Penalty          int64                          `json:"penalty"`
+	RefundableAmount int64                          `json:"refundableAmount"`
+	NonRefundable    bool                           `json:"nonRefundable"`
+	Rule             *CancellationRuleDto           `json:"rule"`
+	Rules            []CancellationRuleDto          `json:"rules"`
+	Error            *indraframework.IndraException `json:"error"`
+}
+
+func (a *CancellationPenaltyDto) SetError(exc *indraframework.IndraException) {
+	a.Error = exc
+}
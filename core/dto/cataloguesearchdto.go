@@ -0,0 +1,21 @@
+package dto
+
+//CatalogueSearchDto is a search over the synced hotels stored in the database, a hotel should have all of the
+//amenities and at least one amenity of each category
+type CatalogueSearchDto struct {
+	City       string
+	Keyword    string
+	MinPrice   int64
+	MaxPrice   int64
+	MinScore   float64
+	MaxScore   float64
+	Stars      []int
+	HotelTypes []string
+	Regions    []string
+	Amenities  []int
+	Categories []uint
+	SortField  string
+	Ascending  bool
+	Limit      int64
+	Skip       int64
+}
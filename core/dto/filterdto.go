@@ -3,16 +3,20 @@ package dto
 import "hotel-engine/core/common"
 
 type FiltersDto struct {
-	PriceFilter    FilterOutput `json:"price_filters"`
-	StarFilters    FilterOutput `json:"star_filters"`
-	ScoreFilters   FilterOutput `json:"score_filters"`
-	AmenityFilters FilterOutput `json:"amenity_filters"`
-	HotelTypes     FilterOutput `json:"hotel_types"`
+	PriceFilter            FilterOutput `json:"price_filters"`
+	StarFilters            FilterOutput `json:"star_filters"`
+	ScoreFilters           FilterOutput `json:"score_filters"`
+	AmenityFilters         FilterOutput `json:"amenity_filters"`
+	AmenityCategoryFilters FilterOutput `json:"amenity_category_filters"`
+	HotelTypes             FilterOutput `json:"hotel_types"`
 }
 
+//FilterDto is a filter option, Count is the number of the search results matching the option and is only set in
+//search responses
 type FilterDto struct {
 	Key   string `json:"key"`
 	Value int64  `json:"value"`
+	Count *int   `json:"count,omitempty"`
 }
 
 type FilterOutput struct {
@@ -133,6 +137,11 @@ func CreateDefaultFiltersDto(amenities []HotelAmenityDto) *FiltersDto {
 			Field:   "facility",
 			Filters: amenityFilters,
 		},
+		AmenityCategoryFilters: FilterOutput{
+			Name:    "دسته بندی امکانات",
+			Field:   "amenity-category",
+			Filters: make([]FilterDto, 0),
+		},
 		HotelTypes: FilterOutput{
 			Name:  "براساس نوع هتل",
 			Field: "types",
@@ -3,4 +3,5 @@ package dto
 //HotelInput for extracting hetol data
 type HotelInput struct {
 	HotelIDs *[]string `json:"hotel-Ids"`
+	Supplier string    `json:"supplier,omitempty"`
 }
@@ -12,6 +12,7 @@ type HotelDetailsDto struct {
 	CheckOut         string           `json:"checkOut"`
 	Rooms            []RequestRoomDto `json:"rooms"`
 	SkipLoadingRooms bool             `json:"skipLoadingRooms,omitempty"`
+	Supplier         string           `json:"supplier,omitempty"`
 }
 
 type RequestRoomDto struct {
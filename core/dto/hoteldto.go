@@ -56,6 +56,7 @@ type HotelDto struct {
 	CountryCode          string                         `json:"countryCode"`
 	Sort                 float64                        `json:"sort"`
 	Seo                  HotelSeoDto                    `json:"seo"`
+	Supplier             string                         `json:"supplier"`
 }
 
 type HotelSeoDto struct {
@@ -83,3 +84,7 @@ func (a *HotelDto) SetProvince(province string) {
 func (a *HotelDto) SetType(hotelType string) {
 	a.Type = hotelType
 }
+
+func (a *HotelDto) SetSupplier(supplier string) {
+	a.Supplier = supplier
+}
@@ -7,10 +7,12 @@ type HotelRoomsDto struct {
 	CheckIn  string           `json:"checkIn"`
 	CheckOut string           `json:"checkOut"`
 	Rooms    []RequestRoomDto `json:"rooms"`
+	Supplier string           `json:"supplier,omitempty"`
 }
 type HotelRoomsWithSessionDto struct {
 	HotelId   string `json:"hotelId"`
 	SessionId string `json:"sessionId"`
+	Supplier  string `json:"supplier,omitempty"`
 }
 
 func (a HotelRoomsDto) Validate() error {
@@ -6,6 +6,7 @@ type OptionInfoRequestDto struct {
 	OptionId  string `json:"optionId"`
 	HotelId   string `json:"hotelId"`
 	SessionId string `json:"sessionId"`
+	Supplier  string `json:"supplier,omitempty"`
 }
 
 func (a OptionInfoRequestDto) Validate() error {
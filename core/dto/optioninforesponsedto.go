@@ -34,6 +34,7 @@ type OptionInfoDetailRestrictedMarkupDto struct {
 }
 
 type OptionCancellationDto struct {
-	NonRefundable   bool     `json:"nonRefundable"`
-	GeneralPolicies []string `json:"GeneralPolicies"`
+	NonRefundable   bool                  `json:"nonRefundable"`
+	GeneralPolicies []string              `json:"GeneralPolicies"`
+	Rules           []CancellationRuleDto `json:"rules"`
 }
@@ -1,6 +1,9 @@
 package dto
 
-import "hotel-engine/utils/indraframework"
+import (
+	"hotel-engine/utils/indraframework"
+	"time"
+)
 
 type OrderDetailDto struct {
 	OrderId                  uint                           `json:"OrderId"`
@@ -10,6 +13,9 @@ type OrderDetailDto struct {
 	ProviderHotelId          string                         `json:"ProviderHotelId"`
 	NonRefundable            bool                           `json:"NonRefundable"`
 	GeneralPolicies          []string                       `json:"GeneralPolicies"`
+	CancellationRules        []CancellationRuleDto          `json:"CancellationRules"`
+	CheckIn                  string                         `json:"CheckIn"`
+	CheckOut                 string                         `json:"CheckOut"`
 	TotalPrice               int64                          `json:"TotalPrice"`
 	Provider                 string                         `json:"Provider"`
 	ProviderName             string                         `json:"ProviderName"`
@@ -18,6 +24,10 @@ type OrderDetailDto struct {
 	RestrictedMarkupAmount   int64                          `json:"RestrictedMarkupAmount"`
 	RestrictedMarkupType     string                         `json:"RestrictedMarkupType"`
 	Status                   string                         `json:"Status"`
+	State                    string                         `json:"State"`
+	StateHistory             []OrderStateTransitionDto      `json:"StateHistory"`
+	ReconciliationMismatch   string                         `json:"ReconciliationMismatch"`
+	PhoneNumber              string                         `json:"PhoneNumber"`
 	Rooms                    []OrderDetailRoomDto           `json:"Rooms"`
 	Hotel                    HotelDto                       `json:"Hotel"`
 	ApplicantRefundRequestId int64                          `json:"ApplicantRefundRequestId"`
@@ -29,6 +39,8 @@ type OrderDetailDto struct {
 	TotalPenaltyAmount       float32                        `json:"TotalPenaltyAmount"`
 	RefundRequestId          int64                          `json:"RefundRequestId"`
 	Confirmed                bool                           `json:"Confirmed"`
+	Refunds                  []OrderRefundDto               `json:"Refunds"`
+	Supplier                 string                         `json:"Supplier"`
 	Error                    *indraframework.IndraException `json:"error"`
 }
 
@@ -44,3 +56,21 @@ type OrderDetailRoomDto struct {
 	Name          string `json:"Name"`
 	NameEn        string `json:"NameEn"`
 }
+
+type OrderRefundDto struct {
+	RefundRequestId          int64     `json:"RefundRequestId"`
+	ApplicantRefundRequestId int64     `json:"ApplicantRefundRequestId"`
+	ReferenceCodes           []string  `json:"ReferenceCodes"`
+	RefundStatus             string    `json:"RefundStatus"`
+	PaidAmount               float32   `json:"PaidAmount"`
+	RefundableAmount         float32   `json:"RefundableAmount"`
+	TotalPenaltyAmount       float32   `json:"TotalPenaltyAmount"`
+	CreatedAt                time.Time `json:"CreatedAt"`
+}
+
+type OrderStateTransitionDto struct {
+	FromState string    `json:"FromState"`
+	ToState   string    `json:"ToState"`
+	Event     string    `json:"Event"`
+	CreatedAt time.Time `json:"CreatedAt"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+type OrderReconciledDto struct {
+	OrderId        string    `json:"OrderId"`
+	PreviousStatus string    `json:"PreviousStatus"`
+	Status         string    `json:"Status"`
+	PreviousState  string    `json:"PreviousState"`
+	State          string    `json:"State"`
+	Mismatches     []string  `json:"Mismatches"`
+	ReconciledAt   time.Time `json:"ReconciledAt"`
+}
@@ -5,9 +5,11 @@ import (
 )
 
 type OrderRefundRequestDto struct {
-	OrderId         string `json:"orderId"`
-	JabamaOrderID   int64  `json:"jabamaOrderId"`
-	RefundRequestID int64  `json:"refundRequestId"`
+	OrderId         string   `json:"orderId"`
+	JabamaOrderID   int64    `json:"jabamaOrderId"`
+	RefundRequestID int64    `json:"refundRequestId"`
+	QuoteToken      string   `json:"quoteToken"`
+	ReferenceCodes  []string `json:"referenceCodes"`
 }
 
 func (a OrderRefundRequestDto) Validate() error {
@@ -15,5 +17,7 @@ func (a OrderRefundRequestDto) Validate() error {
 		validation.Field(&a.OrderId, validation.Required),
 		validation.Field(&a.JabamaOrderID, validation.Required),
 		validation.Field(&a.RefundRequestID, validation.Required),
+		validation.Field(&a.QuoteToken, validation.Required),
+		validation.Field(&a.ReferenceCodes, validation.Each(validation.Required)),
 	)
 }
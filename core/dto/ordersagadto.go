@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"hotel-engine/utils/indraframework"
+	"time"
+)
+
+type OrderSagaDto struct {
+	OrderId   string             `json:"OrderId"`
+	Status    string             `json:"Status"`
+	Step      string             `json:"Step"`
+	Attempts  int                `json:"Attempts"`
+	LastError string             `json:"LastError"`
+	CreatedAt time.Time          `json:"CreatedAt"`
+	UpdatedAt time.Time          `json:"UpdatedAt"`
+	Steps     []OrderSagaStepDto `json:"Steps"`
+}
+
+type OrderSagaStepDto struct {
+	Step      string    `json:"Step"`
+	Succeeded bool      `json:"Succeeded"`
+	Error     string    `json:"Error"`
+	CreatedAt time.Time `json:"CreatedAt"`
+}
+
+type StuckOrderSagasDto struct {
+	Sagas []OrderSagaDto                 `json:"Sagas"`
+	Error *indraframework.IndraException `json:"error"`
+}
+
+func (a *StuckOrderSagasDto) SetError(exc *indraframework.IndraException) {
+	a.Error = exc
+}
@@ -0,0 +1,83 @@
+package dto
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"hotel-engine/utils/indraframework"
+	"time"
+)
+
+const MaxOrdersPageSize = 100
+
+//OrdersPageRequestDto is a page of the orders for the support, the date ranges are inclusive days. the applicant order
+//id is matched with the order and with its refund requests. the orders that were stored before the phone numbers were
+//kept have no phone number and are not found by one
+type OrdersPageRequestDto struct {
+	PageNumber       int    `json:"pageNumber" form:"pageNumber"`
+	PageSize         int    `json:"pageSize" form:"pageSize"`
+	HotelId          string `json:"hotelId" form:"hotelId"`
+	PhoneNumber      string `json:"phoneNumber" form:"phoneNumber"`
+	Status           string `json:"status" form:"status"`
+	State            string `json:"state" form:"state"`
+	ApplicantOrderId int64  `json:"applicantOrderId" form:"applicantOrderId"`
+	CheckInFrom      string `json:"checkInFrom" form:"checkInFrom"`
+	CheckInTo        string `json:"checkInTo" form:"checkInTo"`
+	CreatedFrom      string `json:"createdFrom" form:"createdFrom"`
+	CreatedTo        string `json:"createdTo" form:"createdTo"`
+	SortField        string `json:"sortField" form:"sortField"`
+	Ascending        bool   `json:"ascending" form:"ascending"`
+}
+
+func (a OrdersPageRequestDto) Validate() error {
+	for _, day := range []string{a.CheckInFrom, a.CheckInTo, a.CreatedFrom, a.CreatedTo} {
+		if day == "" {
+			continue
+		}
+		if err := CheckForDate(day); err != nil {
+			return err
+		}
+	}
+	return validation.ValidateStruct(&a,
+		validation.Field(&a.PageNumber, validation.Required, validation.Min(1)),
+		validation.Field(&a.PageSize, validation.Required, validation.Min(1), validation.Max(MaxOrdersPageSize)),
+		validation.Field(&a.SortField, validation.In("createdAt", "checkIn", "totalPrice")),
+	)
+}
+
+type OrdersPageResponseDto struct {
+	PageNumber  int                            `json:"pageNumber"`
+	PageSize    int                            `json:"pageSize"`
+	Total       int                            `json:"total"`
+	TotalPrice  int64                          `json:"totalPrice"`
+	StateTotals map[string]int                 `json:"stateTotals"`
+	Orders      []OrderSummaryDto              `json:"orders"`
+	Error       *indraframework.IndraException `json:"error"`
+}
+
+func (a *OrdersPageResponseDto) SetError(exc *indraframework.IndraException) {
+	a.Error = exc
+}
+
+type OrderSummaryDto struct {
+	IndraOrderId     int64                `json:"IndraOrderId"`
+	ProviderOrderId  string               `json:"ProviderOrderId"`
+	ApplicantOrderId int64                `json:"ApplicantOrderId"`
+	PhoneNumber      string               `json:"PhoneNumber"`
+	Supplier         string               `json:"Supplier"`
+	Status           string               `json:"Status"`
+	State            string               `json:"State"`
+	TotalPrice       int64                `json:"TotalPrice"`
+	Currency         string               `json:"Currency"`
+	CheckIn          string               `json:"CheckIn"`
+	CheckOut         string               `json:"CheckOut"`
+	CreatedAt        time.Time            `json:"CreatedAt"`
+	Hotel            OrderHotelSummaryDto `json:"Hotel"`
+	Rooms            []OrderDetailRoomDto `json:"Rooms"`
+}
+
+type OrderHotelSummaryDto struct {
+	PlaceId string `json:"PlaceId"`
+	Name    string `json:"Name"`
+	NameEn  string `json:"NameEn"`
+	City    string `json:"City"`
+	Star    int    `json:"Star"`
+}
@@ -0,0 +1,66 @@
+package dto
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"hotel-engine/utils/date"
+	"hotel-engine/utils/indraframework"
+	"time"
+)
+
+const DefaultPriceCalendarDays = 30
+
+//PriceCalendarRequestDto prices the one night stays of a hotel, from and to are the first and the last check-in days
+type PriceCalendarRequestDto struct {
+	HotelId  string           `json:"hotelId"`
+	From     string           `json:"from"`
+	To       string           `json:"to,omitempty"`
+	Rooms    []RequestRoomDto `json:"rooms,omitempty"`
+	Supplier string           `json:"supplier,omitempty"`
+}
+
+func (a PriceCalendarRequestDto) Validate() error {
+	if err := CheckForDate(a.From); err != nil {
+		return err
+	}
+	if a.To != "" {
+		if err := CheckForDate(a.To); err != nil {
+			return err
+		}
+	}
+	for _, room := range a.Rooms {
+		if err := room.Validate(); err != nil {
+			return err
+		}
+	}
+	return validation.ValidateStruct(&a,
+		validation.Field(&a.HotelId, validation.Required),
+		validation.Field(&a.From, validation.Required),
+	)
+}
+
+func (a *PriceCalendarRequestDto) SetDefaults() {
+	if a.To == "" {
+		if from, err := date.StringToDate(a.From); err == nil {
+			a.To = from.Add(time.Hour * 24 * (DefaultPriceCalendarDays - 1)).Format(date.LayoutISO)
+		}
+	}
+}
+
+//PriceCalendarDayDto is the price of a night, an indicative price is the synced price of the night that is used when
+//the supplier does not answer and may be hours old
+type PriceCalendarDayDto struct {
+	Date          string `json:"date"`
+	PricePerNight int64  `json:"pricePerNight"`
+	Available     bool   `json:"available"`
+	Indicative    bool   `json:"indicative"`
+}
+
+type PriceCalendarResponseDto struct {
+	HotelId string                         `json:"hotelId"`
+	Days    []PriceCalendarDayDto          `json:"days"`
+	Error   *indraframework.IndraException `json:"error"`
+}
+
+func (a *PriceCalendarResponseDto) SetError(exc *indraframework.IndraException) {
+	a.Error = exc
+}
@@ -5,4 +5,5 @@ type RateReviewEventDto struct {
 	Rating       float64 `json:"Rating"`
 	ReviewsCount int     `json:"ReviewsCount"`
 	ProductType  int     `json:"ProductType"`
+	Supplier     string  `json:"Supplier"`
 }
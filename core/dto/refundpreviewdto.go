@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"hotel-engine/utils/indraframework"
+	"time"
+)
+
+//RefundPreviewDto is what the guest gets back if the order is refunded now. the amounts are the ones of the supplier,
+//the policy penalty is the one of the stored cancellation policy and is null when the policy is not structured or
+//only some of the rooms or passengers are refunded
+type RefundPreviewDto struct {
+	OrderId            string                         `json:"orderId"`
+	ReferenceCodes     []string                       `json:"referenceCodes"`
+	PaidAmount         int64                          `json:"paidAmount"`
+	TotalPenaltyAmount int64                          `json:"totalPenaltyAmount"`
+	RefundableAmount   int64                          `json:"refundableAmount"`
+	PolicyPenalty      *int64                         `json:"policyPenalty"`
+	PolicyRule         *CancellationRuleDto           `json:"policyRule"`
+	Rooms              []RefundPreviewRoomDto         `json:"rooms"`
+	Passengers         []RefundPreviewPassengerDto    `json:"passengers"`
+	QuoteToken         string                         `json:"quoteToken"`
+	QuoteExpiresAt     time.Time                      `json:"quoteExpiresAt"`
+	Error              *indraframework.IndraException `json:"error"`
+}
+
+func (a *RefundPreviewDto) SetError(exc *indraframework.IndraException) {
+	a.Error = exc
+}
+
+//RefundPreviewRoomDto is the share of a room from the penalty, the supplier penalizes the passengers so the penalty
+//is divided between the rooms by their price
+type RefundPreviewRoomDto struct {
+	OrderRoomId      uint   `json:"orderRoomId"`
+	Name             string `json:"name"`
+	NameEn           string `json:"nameEn"`
+	Price            int64  `json:"price"`
+	PenaltyAmount    int64  `json:"penaltyAmount"`
+	RefundableAmount int64  `json:"refundableAmount"`
+}
+
+type RefundPreviewPassengerDto struct {
+	ReferenceCode    string `json:"referenceCode"`
+	Name             string `json:"name"`
+	LastName         string `json:"lastName"`
+	IsRefundable     bool   `json:"isRefundable"`
+	PaidAmount       int64  `json:"paidAmount"`
+	PenaltyAmount    int64  `json:"penaltyAmount"`
+	RefundableAmount int64  `json:"refundableAmount"`
+	RefundStatus     string `json:"refundStatus"`
+}
@@ -5,6 +5,7 @@ import "hotel-engine/utils/indraframework"
 type RoomCancellationPolicyDto struct {
 	NonRefundable   bool                           `json:"nonRefundable"`
 	GeneralPolicies []string                       `json:"GeneralPolicies"`
+	Rules           []CancellationRuleDto          `json:"rules"`
 	Error           *indraframework.IndraException `json:"error"`
 }
 
@@ -1,22 +1,53 @@
 package dto
 
-import validation "github.com/go-ozzo/ozzo-validation/v4"
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"hotel-engine/core/common"
+)
 
 type SearchDto struct {
-	SessionId     string           `json:"sessionId,omitempty"`
-	Rooms         []RequestRoomDto `json:"rooms"`
-	Date          SearchDateDto    `json:"date"`
-	Keyword       string           `json:"keyword,omitempty"`
-	City          string           `json:"city"`
-	PageNumber    int64            `json:"page-number"`
-	PageSize      int64            `json:"page-size"`
-	Price         SearchPriceDto   `json:"price,omitempty"`
-	Region        []string         `json:"region,omitempty"`
-	Score         SearchScoreDto   `json:"score,omitempty"`
-	Sort          string           `json:"sort,omitempty"`
-	Stars         []int            `json:"stars,omitempty"`
-	SortDirection bool             `json:"sort-direction,omitempty"`
-	HotelTypes    []string         `json:"hotel-types,omitempty"`
+	SessionId     string                `json:"sessionId,omitempty"`
+	Rooms         []RequestRoomDto      `json:"rooms"`
+	Date          SearchDateDto         `json:"date"`
+	Keyword       string                `json:"keyword,omitempty"`
+	City          string                `json:"city"`
+	PageNumber    int64                 `json:"page-number"`
+	PageSize      int64                 `json:"page-size"`
+	Price         SearchPriceDto        `json:"price,omitempty"`
+	Region        []string              `json:"region,omitempty"`
+	Amenities     []int                 `json:"amenities,omitempty"`
+	Categories    []uint                `json:"amenity-categories,omitempty"`
+	MealPlans     []string              `json:"meal-plans,omitempty"`
+	Score         SearchScoreDto        `json:"score,omitempty"`
+	Sort          string                `json:"sort,omitempty"`
+	Stars         []int                 `json:"stars,omitempty"`
+	SortDirection bool                  `json:"sort-direction,omitempty"`
+	HotelTypes    []string              `json:"hotel-types,omitempty"`
+	Supplier      string                `json:"supplier,omitempty"`
+	Geo           *SearchGeoDto         `json:"geo,omitempty"`
+	BoundingBox   *SearchBoundingBoxDto `json:"bounding-box,omitempty"`
+	NearPlace     *SearchNearPlaceDto   `json:"near-place,omitempty"`
+}
+
+//SearchGeoDto searches the hotels around a location, the radius is in kilometers
+type SearchGeoDto struct {
+	Lat    float64 `json:"lat"`
+	Lon    float64 `json:"lon"`
+	Radius float64 `json:"radius"`
+}
+
+//SearchBoundingBoxDto searches the hotels of a map area
+type SearchBoundingBoxDto struct {
+	MinLat float64 `json:"min-lat"`
+	MinLon float64 `json:"min-lon"`
+	MaxLat float64 `json:"max-lat"`
+	MaxLon float64 `json:"max-lon"`
+}
+
+//SearchNearPlaceDto searches the hotels around a place, the radius is in kilometers and has a default
+type SearchNearPlaceDto struct {
+	Id     string  `json:"id"`
+	Radius float64 `json:"radius,omitempty"`
 }
 
 type SearchDateDto struct {
@@ -51,8 +82,69 @@ func (a SearchDto) Validate() error {
 		}
 	}
 
+	if err := a.validateGeo(); err != nil {
+		return err
+	}
+
+	for _, mealPlan := range a.MealPlans {
+		if _, found := MealPlans[mealPlan]; !found {
+			return common.InvalidMealPlan
+		}
+	}
+
 	return validation.ValidateStruct(&a,
-		validation.Field(&a.City, validation.Required),
+		validation.Field(&a.City, validation.When(!a.HasGeo(), validation.Required)),
 		validation.Field(&a.Date, validation.Required),
 	)
 }
+
+//HasGeo reports whether the search is limited to a location, a map area or a place
+func (a SearchDto) HasGeo() bool {
+	return a.Geo != nil || a.BoundingBox != nil || a.NearPlace != nil
+}
+
+func (a SearchDto) validateGeo() error {
+	criteria := 0
+	if a.Geo != nil {
+		criteria++
+		if err := validateLocation(a.Geo.Lat, a.Geo.Lon); err != nil {
+			return err
+		}
+		if a.Geo.Radius <= 0 || a.Geo.Radius > common.MaxGeoSearchRadius {
+			return common.InvalidGeoSearchRadius
+		}
+	}
+	if a.BoundingBox != nil {
+		criteria++
+		box := a.BoundingBox
+		if err := validateLocation(box.MinLat, box.MinLon); err != nil {
+			return err
+		}
+		if err := validateLocation(box.MaxLat, box.MaxLon); err != nil {
+			return err
+		}
+		if box.MinLat >= box.MaxLat || box.MinLon >= box.MaxLon {
+			return common.InvalidGeoLocation
+		}
+	}
+	if a.NearPlace != nil {
+		criteria++
+		if a.NearPlace.Id == "" {
+			return common.PlaceNotFound
+		}
+		if a.NearPlace.Radius < 0 || a.NearPlace.Radius > common.MaxGeoSearchRadius {
+			return common.InvalidGeoSearchRadius
+		}
+	}
+	if criteria > 1 {
+		return common.MultipleGeoSearchCriteria
+	}
+	return nil
+}
+
+func validateLocation(lat, lon float64) error {
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return common.InvalidGeoLocation
+	}
+	return nil
+}
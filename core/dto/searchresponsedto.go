@@ -4,12 +4,18 @@ import (
 	"hotel-engine/utils/indraframework"
 )
 
+//SearchResponseDto is the search result page. when the supplier is not available the results come from the synced
+//hotels and IndicativePrices is set, the prices are the last synced ones and must be checked with the hotel rooms
 type SearchResponseDto struct {
-	Result    []SearchResponseHotelDto       `json:"result"`
-	Filters   []FilterOutput                 `json:"filters"`
-	Sorts     []SortDto                      `json:"sorts"`
-	TotalHits int                            `json:"totalHits"`
-	Error     *indraframework.IndraException `json:"error"`
+	SessionId        string                         `json:"sessionId"`
+	IndicativePrices bool                           `json:"indicativePrices"`
+	Result           []SearchResponseHotelDto       `json:"result"`
+	Filters          []FilterOutput                 `json:"filters"`
+	Sorts            []SortDto                      `json:"sorts"`
+	TotalHits        int                            `json:"totalHits"`
+	MinPrice         float64                        `json:"minPrice"`
+	MaxPrice         float64                        `json:"maxPrice"`
+	Error            *indraframework.IndraException `json:"error"`
 }
 
 type SearchResponseHotelDto struct {
@@ -38,6 +44,8 @@ type SearchResponseHotelDto struct {
 	Discount        int                         `json:"discount,omitempty"`
 	DiscountPrice   int                         `json:"discountPrice,omitempty"`
 	Badges          []SearchResponseBadgeDto    `json:"badges"`
+	Distance        *float64                    `json:"distance,omitempty"`
+	Supplier        string                      `json:"supplier"`
 }
 
 type SearchResponseRateReviewDto struct {
@@ -5,7 +5,8 @@ import (
 )
 
 type SyncSomeHotelsDto struct {
-	HotelIds []string                       `json:"hotel_ids"`
+	HotelIds []string `json:"hotel_ids"`
+	Supplier string   `json:"supplier,omitempty"`
 }
 
 func (a SyncSomeHotelsDto) Validate() error {
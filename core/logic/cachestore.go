@@ -14,6 +14,8 @@ type cacheStore struct {
 	unitOfWork   core.UnitOfWork
 	cityStore    core.CityCacheStore
 	amenityStore core.AmenityCacheStore
+	geoStore     core.GeoCacheStore
+	autocomplete core.AutocompleteCacheStore
 	mapper       core.Mapper
 }
 
@@ -25,9 +27,17 @@ func (s *cacheStore) CityStore() core.CityCacheStore {
 	return s.cityStore
 }
 
+func (s *cacheStore) GeoStore() core.GeoCacheStore {
+	return s.geoStore
+}
+
+func (s *cacheStore) AutocompleteStore() core.AutocompleteCacheStore {
+	return s.autocomplete
+}
+
 func (s *cacheStore) UpdateStores() error {
 	wg := sync.WaitGroup{}
-	wg.Add(2)
+	wg.Add(3)
 	go func() {
 		s.UpdateCityStore()
 		wg.Done()
@@ -36,7 +46,12 @@ func (s *cacheStore) UpdateStores() error {
 		s.UpdateAmenityStore()
 		wg.Done()
 	}()
+	go func() {
+		s.UpdateGeoStore()
+		wg.Done()
+	}()
 	wg.Wait()
+	s.UpdateAutocompleteStore()
 	return nil
 }
 
@@ -50,6 +65,23 @@ func (s *cacheStore) UpdateAmenityStore() {
 	s.amenityStore = stores.NewAmenityCacheStore(s.mapper.ToAmenitiesDto(amenities))
 }
 
+func (s *cacheStore) UpdateGeoStore() {
+	hotels, err := s.unitOfWork.Hotel().GetAllLocations()
+	if err != nil {
+		logger.WithName(logtags.InitializingCacheStoresError).ErrorException(err, "cannot load the hotel locations")
+	}
+	s.geoStore = stores.NewGeoCacheStore(hotels, s.unitOfWork.Place().GetAll())
+}
+
+//UpdateAutocompleteStore rebuilds the autocomplete index, it uses the city store so it should be updated after it
+func (s *cacheStore) UpdateAutocompleteStore() {
+	hotels, err := s.unitOfWork.Hotel().GetAllNames()
+	if err != nil {
+		logger.WithName(logtags.InitializingCacheStoresError).ErrorException(err, "cannot load the hotel names")
+	}
+	s.autocomplete = stores.NewAutocompleteCacheStore(s.cityStore.GetAll(), hotels)
+}
+
 func NewCacheStore(unit core.UnitOfWork, mapper core.Mapper) core.CacheStore {
 	store = &cacheStore{
 		unitOfWork: unit,
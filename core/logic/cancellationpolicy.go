@@ -0,0 +1,259 @@
+package logic
+
+import (
+	"context"
+	"hotel-engine/core/common"
+	"hotel-engine/core/common/logtags"
+	"hotel-engine/core/dbmodel"
+	"hotel-engine/core/dto"
+	"hotel-engine/infrastructure/logger"
+	"hotel-engine/utils/persian"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//the check in time of the hotels is local, iran has no daylight saving time since 2022
+var iranTime = time.FixedZone("IRST", 3*60*60+30*60)
+
+var (
+	thousandsSeparator = regexp.MustCompile(`([0-9۰-۹])[,٬]([0-9۰-۹])`)
+	digitThenLetter    = regexp.MustCompile(`(\d)(\pL)`)
+	letterThenDigit    = regexp.MustCompile(`(\pL)(\d)`)
+)
+
+var numberWords = map[string]int{
+	"یک": 1, "دو": 2, "سه": 3, "چهار": 4, "پنج": 5,
+	"one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+}
+
+var (
+	freePhrases          = []string{"بدون جریمه", "بدون کسر", "رایگان", "free", "no penalty", "without penalty"}
+	noShowPhrases        = []string{"عدم حضور", "no show"}
+	nonRefundablePhrases = []string{"غیر قابل استرداد", "غیرقابل استرداد", "امکان لغو رزرو وجود ندارد",
+		"non refundable", "nonrefundable"}
+	fullPenaltyPhrases = []string{"کل مبلغ", "تمام مبلغ", "full amount", "full price"}
+)
+
+func containsAny(text string, phrases []string) bool {
+	for _, phrase := range phrases {
+		if strings.Contains(text, persian.Normalize(phrase)) {
+			return true
+		}
+	}
+	return false
+}
+
+func policyNumber(word string) (int, bool) {
+	if n, found := numberWords[word]; found {
+		return n, true
+	}
+	n, err := strconv.Atoi(word)
+	return n, err == nil
+}
+
+//parseCancellationPolicy reads a line of the supplier policy like "لغو کمتر از ۷۲ ساعت قبل از ورود ۵۰ درصد جریمه دارد",
+//a line without a penalty is a free cancellation from the reservation. false is returned when the line is not understood
+func parseCancellationPolicy(policy string) (dto.CancellationRuleDto, bool) {
+	rule := dto.CancellationRuleDto{}
+	text := persian.Normalize(thousandsSeparator.ReplaceAllString(
+		strings.NewReplacer("%", " درصد ", "٪", " درصد ").Replace(policy), "$1$2"))
+	text = letterThenDigit.ReplaceAllString(digitThenLetter.ReplaceAllString(text, "$1 $2"), "$1 $2")
+	if text == "" {
+		return rule, false
+	}
+	if containsAny(text, nonRefundablePhrases) {
+		rule.FromBooking = true
+		rule.PenaltyPercent = 100
+		return rule, true
+	}
+
+	hours := -1
+	words := strings.Fields(text)
+	for i, word := range words {
+		if i+1 < len(words) && (word == "شب" && words[i+1] == "اول" || word == "first" && words[i+1] == "night") {
+			rule.PenaltyNights = 1
+			continue
+		}
+		n, ok := policyNumber(word)
+		if !ok || i+1 >= len(words) {
+			continue
+		}
+		switch words[i+1] {
+		case "ساعت", "hour", "hours":
+			if n > hours {
+				hours = n
+			}
+		case "روز", "day", "days":
+			if n*24 > hours {
+				hours = n * 24
+			}
+		case "درصد", "percent":
+			rule.PenaltyPercent = float64(n)
+		case "شب", "night", "nights":
+			rule.PenaltyNights = n
+		case "ریال", "rial", "rials", "irr":
+			rule.PenaltyAmount = int64(n)
+		case "تومان", "toman", "tomans":
+			rule.PenaltyAmount = int64(n) * 10
+		}
+	}
+	if containsAny(text, fullPenaltyPhrases) {
+		rule.PenaltyPercent = 100
+	}
+
+	penalty := rule.PenaltyPercent > 0 || rule.PenaltyAmount > 0 || rule.PenaltyNights > 0
+	switch {
+	case penalty && hours >= 0:
+		rule.HoursBeforeCheckIn = hours
+	case penalty && containsAny(text, noShowPhrases):
+		rule.HoursBeforeCheckIn = 0
+	case penalty:
+		rule.FromBooking = true
+	case containsAny(text, freePhrases):
+		rule = dto.CancellationRuleDto{FromBooking: true}
+	default:
+		return rule, false
+	}
+	return rule, true
+}
+
+//parseCancellationRules turns the policy of a rate into rules, the rules that apply earlier come first. the rules are
+//only returned when every line of the policy is understood so a partial policy is never shown as the whole policy
+func parseCancellationRules(nonRefundable bool, policies []string) ([]dto.CancellationRuleDto, bool) {
+	if nonRefundable {
+		return []dto.CancellationRuleDto{{FromBooking: true, PenaltyPercent: 100}}, true
+	}
+	rules := make([]dto.CancellationRuleDto, 0, len(policies))
+	for _, policy := range policies {
+		if strings.TrimSpace(policy) == "" {
+			continue
+		}
+		rule, ok := parseCancellationPolicy(policy)
+		if !ok {
+			return nil, false
+		}
+		rules = append(rules, rule)
+	}
+	if len(rules) == 0 {
+		return nil, false
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].FromBooking != rules[j].FromBooking {
+			return rules[i].FromBooking
+		}
+		return rules[i].HoursBeforeCheckIn > rules[j].HoursBeforeCheckIn
+	})
+	return rules, true
+}
+
+//policyRules is the parsed policy of a rate for the responses, the rules are empty when the policy is not understood
+func policyRules(nonRefundable bool, policies []string) []dto.CancellationRuleDto {
+	rules, _ := parseCancellationRules(nonRefundable, policies)
+	return rules
+}
+
+//orderCancellationRules parses the policy of a reserved option and sets the deadline of the rules from the check in
+//date and the check in time of the hotel
+func (g *hotelService) orderCancellationRules(detail *dto.OrderDetailDto, hotelId, checkIn string) []dto.CancellationRuleDto {
+	rules, ok := parseCancellationRules(detail.NonRefundable, detail.GeneralPolicies)
+	if !ok {
+		logger.WithName(logtags.CancellationPolicyNotParsed).WithData(map[string]interface{}{
+			"hotelId":  hotelId,
+			"policies": detail.GeneralPolicies,
+		}).Warn("cannot parse the cancellation policy of the order")
+		return nil
+	}
+	checkInTime := common.DefaultCheckInTime
+	if hotel, err := g.unitOfWork.Hotel().GetHotel(hotelId, detail.Supplier); err == nil && hotel.CheckInTime != "" {
+		checkInTime = hotel.CheckInTime
+	}
+	arrival, err := time.ParseInLocation("2006-01-02 15:04", checkIn+" "+checkInTime, iranTime)
+	if err != nil {
+		if arrival, err = time.ParseInLocation("2006-01-02 15:04", checkIn+" "+common.DefaultCheckInTime, iranTime); err != nil {
+			return rules
+		}
+	}
+	for i := range rules {
+		if !rules[i].FromBooking {
+			deadline := arrival.Add(-time.Hour * time.Duration(rules[i].HoursBeforeCheckIn))
+			rules[i].Deadline = &deadline
+		}
+	}
+	return rules
+}
+
+//ruleApplies reports whether a cancellation at the time is charged by the rule
+func ruleApplies(rule dto.CancellationRuleDto, at time.Time) bool {
+	return rule.FromBooking || (rule.Deadline != nil && !at.Before(*rule.Deadline))
+}
+
+//rulePenalty is the largest penalty of the rule, the penalty is never more than the total price
+func rulePenalty(rule dto.CancellationRuleDto, totalPrice, pricePerNight int64) int64 {
+	penalty := int64(math.Round(float64(totalPrice) * rule.PenaltyPercent / 100))
+	if rule.PenaltyAmount > penalty {
+		penalty = rule.PenaltyAmount
+	}
+	if nights := int64(rule.PenaltyNights) * pricePerNight; nights > penalty {
+		penalty = nights
+	}
+	if penalty > totalPrice {
+		penalty = totalPrice
+	}
+	return penalty
+}
+
+//orderPricePerNight is the price of a night of all the rooms of the order
+func orderPricePerNight(order *dbmodel.Order) int64 {
+	var price int64
+	for _, room := range order.Rooms {
+		price += room.PricePerNight
+	}
+	if price > 0 {
+		return price
+	}
+	if order.CheckIn != nil && order.CheckOut != nil {
+		if nights := int64(order.CheckOut.Sub(*order.CheckIn).Hours() / 24); nights > 0 {
+			return order.TotalPrice / nights
+		}
+	}
+	return order.TotalPrice
+}
+
+//GetCancellationPenalty returns the penalty of cancelling the order at the time, the highest penalty of the rules
+//that apply at the time is charged
+func (g *hotelService) GetCancellationPenalty(ctx context.Context, orderId string, at time.Time) (*dto.CancellationPenaltyDto, error) {
+	order, err := g.unitOfWork.Order().GetOneByIndraId(orderId)
+	if err != nil {
+		return nil, err
+	}
+	rules := g.mapper.ToOrderDto(*order).CancellationRules
+	if order.NonRefundable {
+		rules, _ = parseCancellationRules(true, nil)
+	}
+	if len(rules) == 0 {
+		return nil, common.CancellationPolicyUnknown
+	}
+	result := &dto.CancellationPenaltyDto{
+		OrderId:       orderId,
+		At:            at,
+		TotalPrice:    order.TotalPrice,
+		NonRefundable: order.NonRefundable,
+		Rules:         rules,
+	}
+	pricePerNight := orderPricePerNight(order)
+	for i := range rules {
+		if !ruleApplies(rules[i], at) {
+			continue
+		}
+		if penalty := rulePenalty(rules[i], order.TotalPrice, pricePerNight); result.Rule == nil || penalty > result.Penalty {
+			result.Penalty = penalty
+			result.Rule = &rules[i]
+		}
+	}
+	result.RefundableAmount = order.TotalPrice - result.Penalty
+	return result, nil
+}
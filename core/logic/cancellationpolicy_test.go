@@ -0,0 +1,216 @@
+package logic
+
+import (
+	"hotel-engine/core/dto"
+	"reflect"
+	"testing"
+)
+
+func Test_parseCancellationPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+		want   dto.CancellationRuleDto
+		wantOk bool
+	}{
+		{
+			name:   "persian hours and percent",
+			policy: "لغو کمتر از ۷۲ ساعت قبل از ورود ۵۰ درصد جریمه دارد",
+			want:   dto.CancellationRuleDto{HoursBeforeCheckIn: 72, PenaltyPercent: 50},
+			wantOk: true,
+		},
+		{
+			name:   "persian days and percent sign",
+			policy: "لغو از ۳ روز مانده به ورود ۳۰٪ جریمه",
+			want:   dto.CancellationRuleDto{HoursBeforeCheckIn: 72, PenaltyPercent: 30},
+			wantOk: true,
+		},
+		{
+			name:   "english hours and percent sign",
+			policy: "Cancellation within 48 hours of check in: 100% penalty",
+			want:   dto.CancellationRuleDto{HoursBeforeCheckIn: 48, PenaltyPercent: 100},
+			wantOk: true,
+		},
+		{
+			name:   "number words",
+			policy: "cancellation two days before arrival costs one night",
+			want:   dto.CancellationRuleDto{HoursBeforeCheckIn: 48, PenaltyNights: 1},
+			wantOk: true,
+		},
+		{
+			name:   "first night",
+			policy: "لغو کمتر از ۲۴ ساعت مانده به ورود جریمه شب اول",
+			want:   dto.CancellationRuleDto{HoursBeforeCheckIn: 24, PenaltyNights: 1},
+			wantOk: true,
+		},
+		{
+			name:   "english first night",
+			policy: "Cancellation within 24 hours: first night is charged",
+			want:   dto.CancellationRuleDto{HoursBeforeCheckIn: 24, PenaltyNights: 1},
+			wantOk: true,
+		},
+		{
+			name:   "rial with thousands separator",
+			policy: "لغو کمتر از ۴۸ ساعت قبل از ورود ۱,۵۰۰,۰۰۰ ریال جریمه",
+			want:   dto.CancellationRuleDto{HoursBeforeCheckIn: 48, PenaltyAmount: 1500000},
+			wantOk: true,
+		},
+		{
+			name:   "toman with arabic thousands separator",
+			policy: "لغو کمتر از ۴۸ ساعت قبل از ورود ۲۵۰٬۰۰۰ تومان جریمه",
+			want:   dto.CancellationRuleDto{HoursBeforeCheckIn: 48, PenaltyAmount: 2500000},
+			wantOk: true,
+		},
+		{
+			name:   "toman attached to the number",
+			policy: "cancellation within 24 hours 100000toman",
+			want:   dto.CancellationRuleDto{HoursBeforeCheckIn: 24, PenaltyAmount: 1000000},
+			wantOk: true,
+		},
+		{
+			name:   "full amount",
+			policy: "لغو کمتر از ۲۴ ساعت قبل از ورود کل مبلغ جریمه دارد",
+			want:   dto.CancellationRuleDto{HoursBeforeCheckIn: 24, PenaltyPercent: 100},
+			wantOk: true,
+		},
+		{
+			name:   "no show",
+			policy: "در صورت عدم حضور ۱۰۰ درصد جریمه",
+			want:   dto.CancellationRuleDto{PenaltyPercent: 100},
+			wantOk: true,
+		},
+		{
+			name:   "english no show",
+			policy: "No show: 100 percent",
+			want:   dto.CancellationRuleDto{PenaltyPercent: 100},
+			wantOk: true,
+		},
+		{
+			name:   "penalty from the reservation",
+			policy: "لغو رزرو ۱۰ درصد جریمه دارد",
+			want:   dto.CancellationRuleDto{FromBooking: true, PenaltyPercent: 10},
+			wantOk: true,
+		},
+		{
+			name:   "non refundable",
+			policy: "این رزرو غیر قابل استرداد است",
+			want:   dto.CancellationRuleDto{FromBooking: true, PenaltyPercent: 100},
+			wantOk: true,
+		},
+		{
+			name:   "english non refundable",
+			policy: "Non refundable rate",
+			want:   dto.CancellationRuleDto{FromBooking: true, PenaltyPercent: 100},
+			wantOk: true,
+		},
+		{
+			name:   "free cancellation",
+			policy: "لغو رزرو تا ۷ روز قبل از ورود بدون جریمه است",
+			want:   dto.CancellationRuleDto{FromBooking: true},
+			wantOk: true,
+		},
+		{
+			name:   "unparseable",
+			policy: "لطفا برای لغو رزرو با پشتیبانی تماس بگیرید",
+			wantOk: false,
+		},
+		{
+			name:   "hours without a penalty",
+			policy: "cancellation within 24 hours of arrival",
+			wantOk: false,
+		},
+		{
+			name:   "empty",
+			policy: "   ",
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseCancellationPolicy(tt.policy)
+			if ok != tt.wantOk {
+				t.Fatalf("parseCancellationPolicy() ok = %v, want %v, rule %+v", ok, tt.wantOk, got)
+			}
+			if ok && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCancellationPolicy() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseCancellationRules(t *testing.T) {
+	tests := []struct {
+		name          string
+		nonRefundable bool
+		policies      []string
+		want          []dto.CancellationRuleDto
+		wantOk        bool
+	}{
+		{
+			name:          "non refundable rate",
+			nonRefundable: true,
+			policies:      []string{"لغو کمتر از ۷۲ ساعت قبل از ورود ۵۰ درصد جریمه دارد"},
+			want:          []dto.CancellationRuleDto{{FromBooking: true, PenaltyPercent: 100}},
+			wantOk:        true,
+		},
+		{
+			name: "sorted by deadline",
+			policies: []string{
+				"لغو کمتر از ۲۴ ساعت قبل از ورود ۱۰۰ درصد جریمه",
+				"",
+				"لغو کمتر از ۷۲ ساعت قبل از ورود ۵۰ درصد جریمه",
+				"لغو رزرو ۱۰ درصد جریمه دارد",
+			},
+			want: []dto.CancellationRuleDto{
+				{FromBooking: true, PenaltyPercent: 10},
+				{HoursBeforeCheckIn: 72, PenaltyPercent: 50},
+				{HoursBeforeCheckIn: 24, PenaltyPercent: 100},
+			},
+			wantOk: true,
+		},
+		{
+			name: "a line is not understood",
+			policies: []string{
+				"لغو کمتر از ۷۲ ساعت قبل از ورود ۵۰ درصد جریمه",
+				"لطفا برای لغو رزرو با پشتیبانی تماس بگیرید",
+			},
+			wantOk: false,
+		},
+		{
+			name:     "no policy",
+			policies: []string{"", " "},
+			wantOk:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseCancellationRules(tt.nonRefundable, tt.policies)
+			if ok != tt.wantOk {
+				t.Fatalf("parseCancellationRules() ok = %v, want %v, rules %+v", ok, tt.wantOk, got)
+			}
+			if ok && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCancellationRules() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_rulePenalty(t *testing.T) {
+	tests := []struct {
+		name string
+		rule dto.CancellationRuleDto
+		want int64
+	}{
+		{name: "percent", rule: dto.CancellationRuleDto{PenaltyPercent: 50}, want: 3000000},
+		{name: "nights", rule: dto.CancellationRuleDto{PenaltyNights: 1}, want: 2000000},
+		{name: "largest penalty", rule: dto.CancellationRuleDto{PenaltyPercent: 10, PenaltyNights: 1}, want: 2000000},
+		{name: "capped by the total price", rule: dto.CancellationRuleDto{PenaltyAmount: 9000000}, want: 6000000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rulePenalty(tt.rule, 6000000, 2000000); got != tt.want {
+				t.Errorf("rulePenalty() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package logic
+
+import (
+	"hotel-engine/core/common"
+	"hotel-engine/core/dto"
+	"hotel-engine/utils/geo"
+	"sort"
+)
+
+//geoSearch is the area of a search and the origin that the distances are measured from. the supplier searches a
+//whole city so the area is applied on a window of the city results and the page is taken from the matches
+type geoSearch struct {
+	origin     geo.Point
+	radius     float64
+	box        *geo.BoundingBox
+	byDistance bool
+}
+
+type geoMatch struct {
+	index    int
+	distance float64
+}
+
+//resolveGeoSearch returns the area of the request, the city is resolved from the nearest hotel when it is not set.
+//distance sort is not known by the supplier so it is removed from the request and always returns the nearest first
+func (g *hotelService) resolveGeoSearch(request *dto.SearchDto) (*geoSearch, error) {
+	if !request.HasGeo() {
+		return nil, nil
+	}
+	search := &geoSearch{byDistance: request.Sort == common.SortByDistance}
+	switch {
+	case request.Geo != nil:
+		search.origin = geo.Point{Lat: request.Geo.Lat, Lon: request.Geo.Lon}
+		search.radius = request.Geo.Radius
+	case request.BoundingBox != nil:
+		search.box = &geo.BoundingBox{
+			MinLat: request.BoundingBox.MinLat,
+			MinLon: request.BoundingBox.MinLon,
+			MaxLat: request.BoundingBox.MaxLat,
+			MaxLon: request.BoundingBox.MaxLon,
+		}
+		search.origin = search.box.Center()
+	case request.NearPlace != nil:
+		point, found := g.cacheStore.GeoStore().Place(request.NearPlace.Id)
+		if !found {
+			return nil, common.PlaceNotFound
+		}
+		search.origin = point
+		search.radius = request.NearPlace.Radius
+		if search.radius == 0 {
+			search.radius = common.DefaultNearPlaceRadius
+		}
+	}
+	if request.City == "" {
+		city, found := g.cacheStore.GeoStore().NearestHotelCity(search.origin)
+		if !found {
+			return nil, common.CityNotFound
+		}
+		request.City = city
+	}
+	if search.byDistance {
+		request.Sort = ""
+	}
+	return search, nil
+}
+
+func (s *geoSearch) contains(point geo.Point) bool {
+	if s.box != nil {
+		return s.box.Contains(point)
+	}
+	return geo.Distance(s.origin, point) <= s.radius
+}
+
+//match returns the items inside the area, nearest first when the search is sorted by distance
+func (s *geoSearch) match(count int, location func(i int) (geo.Point, bool)) []geoMatch {
+	matches := make([]geoMatch, 0)
+	for i := 0; i < count; i++ {
+		point, ok := location(i)
+		if !ok || !s.contains(point) {
+			continue
+		}
+		matches = append(matches, geoMatch{index: i, distance: geo.Distance(s.origin, point)})
+	}
+	if s.byDistance {
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].distance < matches[j].distance
+		})
+	}
+	return matches
+}
+
+//allMatches matches all of the items in their order, it is used when only the local filters are applied
+func allMatches(count int) []geoMatch {
+	matches := make([]geoMatch, count)
+	for i := range matches {
+		matches[i].index = i
+	}
+	return matches
+}
+
+func pageOf(matches []geoMatch, pageNumber, pageSize int64) []geoMatch {
+	if pageNumber <= 0 {
+		pageNumber = 1
+	}
+	if pageSize <= 0 {
+		pageSize = common.DefaultSearchPageSize
+	}
+	start := (pageNumber - 1) * pageSize
+	if start >= int64(len(matches)) {
+		return []geoMatch{}
+	}
+	end := start + pageSize
+	if end > int64(len(matches)) {
+		end = int64(len(matches))
+	}
+	return matches[start:end]
+}
+
+func setDistances(res *dto.SearchResponseDto, distances map[string]float64) {
+	for i := range res.Result {
+		if distance, found := distances[res.Result[i].PlaceID]; found {
+			distance = float64(int64(distance*100+0.5)) / 100
+			res.Result[i].Distance = &distance
+		}
+	}
+}
+
+func distanceSort() dto.SortDto {
+	return dto.SortDto{
+		Field:     common.SortByDistance,
+		Direction: true,
+		Name:      "نزدیک‌ترین",
+	}
+}
@@ -0,0 +1,71 @@
+package logic
+
+import (
+	"hotel-engine/core"
+	"hotel-engine/core/common"
+	"strings"
+	"sync"
+)
+
+type hotelProviderRegistry struct {
+	lock            sync.RWMutex
+	providers       map[string]core.HotelProvider
+	suppliers       []string
+	defaultSupplier string
+}
+
+func (r *hotelProviderRegistry) Register(supplier string, provider core.HotelProvider) error {
+	supplier = normalizeSupplier(supplier)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, found := r.providers[supplier]; found {
+		return common.SupplierAlreadyRegistered
+	}
+	r.providers[supplier] = provider
+	r.suppliers = append(r.suppliers, supplier)
+	if r.defaultSupplier == "" {
+		r.defaultSupplier = supplier
+	}
+	return nil
+}
+
+//Get returns the provider of the given supplier. an empty supplier name resolves to the default supplier
+func (r *hotelProviderRegistry) Get(supplier string) (core.HotelProvider, error) {
+	supplier = normalizeSupplier(supplier)
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if supplier == "" {
+		supplier = r.defaultSupplier
+	}
+	provider, found := r.providers[supplier]
+	if !found {
+		return nil, common.SupplierNotFound
+	}
+	return provider, nil
+}
+
+func (r *hotelProviderRegistry) DefaultSupplier() string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.defaultSupplier
+}
+
+func (r *hotelProviderRegistry) Suppliers() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	suppliers := make([]string, len(r.suppliers))
+	copy(suppliers, r.suppliers)
+	return suppliers
+}
+
+func normalizeSupplier(supplier string) string {
+	return strings.ToLower(strings.TrimSpace(supplier))
+}
+
+func NewHotelProviderRegistry(defaultSupplier string) core.HotelProviderRegistry {
+	return &hotelProviderRegistry{
+		providers:       make(map[string]core.HotelProvider),
+		suppliers:       make([]string, 0),
+		defaultSupplier: normalizeSupplier(defaultSupplier),
+	}
+}
@@ -1,6 +1,7 @@
 package logic
 
 import (
+	"context"
 	"fmt"
 	"hotel-engine/core"
 	"hotel-engine/core/common"
@@ -13,6 +14,8 @@ import (
 	"hotel-engine/utils/array"
 	"hotel-engine/utils/atomicflag"
 	"hotel-engine/utils/date"
+	"hotel-engine/utils/geo"
+	"hotel-engine/utils/ratelimit"
 	"strconv"
 	"strings"
 	"time"
@@ -25,7 +28,7 @@ var (
 
 type hotelService struct {
 	mapper               core.Mapper
-	provider             core.HotelProvider
+	providers            core.HotelProviderRegistry
 	unitOfWork           core.UnitOfWork
 	searchDtoAdopter     core.SearchDtoAdopter
 	publicService        core.PublicService
@@ -33,18 +36,39 @@ type hotelService struct {
 	balanceChecker       core.ProviderBalanceChecker
 	syncChunkSize        int
 	orderEventDispatcher core.OrderEventDispatcher
+	responseCache        core.TtlCache
+	roomsCacheTtl        time.Duration
+	optionInfoCacheTtl   time.Duration
+	searchSessionTtl     time.Duration
+	searchPageTtl        time.Duration
+	searchFallback       bool
+	geoSearchWindow      int
+	facetWindow          int
+	calendarWorkers      int
+	calendarMaxDays      int
+	calendarTtl          time.Duration
+	calendarTimeout      time.Duration
+	syncCalendarDays     int
+	syncCalendarWorkers  int
+	syncCalendarLimiter  *ratelimit.Limiter
+	refundQuoteSecret    string
+	refundQuoteTtl       time.Duration
+	sagaMaxAttempts      int
+	sagaStaleAfter       time.Duration
+	sagaLockKey          string
+	locker               core.DistributedLocker
 }
 
-func (g *hotelService) FindHotelById(id string) (*dto.HotelDto, error) {
-	hotel, err := g.unitOfWork.Hotel().FindByID(id)
+func (g *hotelService) FindHotelById(ctx context.Context, id, supplier string) (*dto.HotelDto, error) {
+	hotel, err := g.unitOfWork.Hotel().FindByID(id, g.hotelSupplier(supplier))
 	if err != nil {
 		return nil, err
 	}
 	return g.mapper.ToHotelDto(*hotel), nil
 }
 
-func (g *hotelService) GetHotelDetails(request dto.HotelDetailsDto) (*dto.HotelPDPDto, error) {
-	hotel, err := g.FindHotelById(request.HotelId)
+func (g *hotelService) GetHotelDetails(ctx context.Context, request dto.HotelDetailsDto) (*dto.HotelPDPDto, error) {
+	hotel, err := g.FindHotelById(ctx, request.HotelId, request.Supplier)
 	if err != nil {
 		return nil, err
 	}
@@ -55,11 +79,12 @@ func (g *hotelService) GetHotelDetails(request dto.HotelDetailsDto) (*dto.HotelP
 	if request.CanSkipOptions() && hotel.Price != 0 {
 		return g.mapper.ToHotelPDPDto(*hotel, request.Rooms), nil
 	}
-	hotelRooms, err := g.GetHotelRooms(dto.HotelRoomsDto{
+	hotelRooms, err := g.GetHotelRooms(ctx, dto.HotelRoomsDto{
 		HotelId:  hotel.PlaceID,
 		CheckIn:  request.CheckIn,
 		CheckOut: request.CheckOut,
 		Rooms:    request.Rooms,
+		Supplier: hotel.Supplier,
 	})
 	if err != nil {
 		return nil, err
@@ -110,18 +135,29 @@ func (g *hotelService) getUnavailableAmenities(amenities []dto.HotelAmenityDto)
 	return unavailableAmenities
 }
 
-func (g *hotelService) GetRoomCancellationPolicy(hotelId, roomId, sessionId string) (*dto.RoomCancellationPolicyDto, error) {
-	return g.provider.GetRoomCancellationPolicy(hotelId, roomId, sessionId)
+func (g *hotelService) GetRoomCancellationPolicy(ctx context.Context, hotelId, roomId, sessionId,
+	supplier string) (*dto.RoomCancellationPolicyDto, error) {
+	provider, err := g.providers.Get(supplier)
+	if err != nil {
+		return nil, err
+	}
+	res, err := provider.GetRoomCancellationPolicy(ctx, hotelId, roomId, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	res.Rules = policyRules(res.NonRefundable, res.GeneralPolicies)
+	return res, nil
 }
 
-func (g *hotelService) UpdateSomeHotels(hotelsDto dto.SyncSomeHotelsDto, date time.Time) (*dto.UpdateResultDto, error) {
+func (g *hotelService) UpdateSomeHotels(ctx context.Context, hotelsDto dto.SyncSomeHotelsDto, date time.Time) (*dto.UpdateResultDto, error) {
 
 	start := time.Now()
 
 	length := len(hotelsDto.HotelIds)
+	supplier := g.hotelSupplier(hotelsDto.Supplier)
 	hotelChannel := make(chan *dbmodel.Hotel, length)
 	for i := 0; i < length; i++ {
-		go g.updateHotel(hotelsDto.HotelIds[i], "", date, hotelChannel)
+		go g.updateHotel(ctx, hotelsDto.HotelIds[i], "", supplier, date, hotelChannel)
 	}
 	for i := 0; i < length; i++ {
 		hotel := <-hotelChannel
@@ -143,14 +179,15 @@ func (g *hotelService) UpdateSomeHotels(hotelsDto dto.SyncSomeHotelsDto, date ti
 	}, nil
 }
 
-func (g *hotelService) UpdateAll(date time.Time) (*dto.TaskRunningResult, error) {
+func (g *hotelService) UpdateAll(ctx context.Context, date time.Time) (*dto.TaskRunningResult, error) {
 	if inUpdating.Get() {
 		return nil, common.AlreadyUpdatingHotels
 	}
 	inUpdating.Set(true)
+	//the task outlives the request, so it must not be cancelled with the request context
 	go func() {
 		defer inUpdating.Set(false)
-		_, err := g.UpdateAllSync(date)
+		_, err := g.UpdateAllSync(context.Background(), date)
 		if err != nil {
 			logger.WithName(logtags.UpdatingHotelsError).ErrorException(err, "error wile updating hotels")
 			return
@@ -164,19 +201,22 @@ func (g *hotelService) UpdateAll(date time.Time) (*dto.TaskRunningResult, error)
 	}, nil
 }
 
-func (g *hotelService) UpdateAllSync(date time.Time) (*dto.UpdateResultDto, error) {
+func (g *hotelService) UpdateAllSync(ctx context.Context, date time.Time) (*dto.UpdateResultDto, error) {
 	start := time.Now()
 
-	ids, err := g.unitOfWork.Hotel().GetAllHotelIds()
-	if err != nil {
-		return nil, err
-	}
-	length := len(ids)
-
-	for _, hotels := range array.Chunks(ids, g.syncChunkSize) {
-		err := g.updateSync(date, hotels)
+	length := 0
+	for _, supplier := range g.providers.Suppliers() {
+		ids, err := g.unitOfWork.Hotel().GetAllHotelIds(supplier)
 		if err != nil {
-			logger.WithName(logtags.UpdatingHotelsError).ErrorException(err, "error while updating chunk of hotel ids")
+			return nil, err
+		}
+		length += len(ids)
+
+		for _, hotels := range array.Chunks(ids, g.syncChunkSize) {
+			err := g.updateSync(ctx, date, supplier, hotels)
+			if err != nil {
+				logger.WithName(logtags.UpdatingHotelsError).ErrorException(err, "error while updating chunk of hotel ids")
+			}
 		}
 	}
 
@@ -192,11 +232,11 @@ func (g *hotelService) UpdateAllSync(date time.Time) (*dto.UpdateResultDto, erro
 	}, nil
 }
 
-func (g *hotelService) updateSync(date time.Time, ids []string) error {
+func (g *hotelService) updateSync(ctx context.Context, date time.Time, supplier string, ids []string) error {
 	length := len(ids)
 	hotelChannel := make(chan *dbmodel.Hotel, length)
 	for i := 0; i < length; i++ {
-		go g.updateHotel(ids[i], "", date, hotelChannel)
+		go g.updateHotel(ctx, ids[i], "", supplier, date, hotelChannel)
 	}
 	for i := 0; i < length; i++ {
 		hotel := <-hotelChannel
@@ -213,11 +253,11 @@ func (g *hotelService) updateSync(date time.Time, ids []string) error {
 	return nil
 }
 
-func (g *hotelService) SyncSomeHotels(hotelsDto dto.SyncSomeHotelsDto) (*dto.UpdateResultDto, error) {
-	return g.UpdateSomeHotels(hotelsDto, time.Now())
+func (g *hotelService) SyncSomeHotels(ctx context.Context, hotelsDto dto.SyncSomeHotelsDto) (*dto.UpdateResultDto, error) {
+	return g.UpdateSomeHotels(ctx, hotelsDto, time.Now())
 }
 
-func (g *hotelService) SyncedHotels() (*dto.SyncedHotelsDetail, error) {
+func (g *hotelService) SyncedHotels(ctx context.Context) (*dto.SyncedHotelsDetail, error) {
 	hotels, err := g.unitOfWork.Hotel().GetAllHotels()
 	if err != nil {
 		return nil, err
@@ -225,7 +265,7 @@ func (g *hotelService) SyncedHotels() (*dto.SyncedHotelsDetail, error) {
 	return g.mapper.ToHotelsDetail(hotels), nil
 }
 
-func (g *hotelService) SyncAllHotels() (*dto.TaskRunningResult, error) {
+func (g *hotelService) SyncAllHotels(ctx context.Context) (*dto.TaskRunningResult, error) {
 	if inSyncing.Get() {
 		return nil, common.AlreadyInSyncing
 	}
@@ -234,34 +274,14 @@ func (g *hotelService) SyncAllHotels() (*dto.TaskRunningResult, error) {
 		defer inSyncing.Set(false)
 		start := time.Now()
 		cities, _ := g.publicService.SyncAllCities()
-		he := g.provider.CreateHotelEnumerable(cities)
-		for he.MoveNext() {
-			res, err := he.Current()
-			if err != nil {
-				logger.WithName(logtags.SyncHotelsError).WithException(err).
-					Error("problem in getting list of hotels")
-				continue
-			}
-			length := len(res)
-			hotelChannel := make(chan *dbmodel.Hotel, length)
-			for i := 0; i < length; i++ {
-				go g.updateHotel(res[i].Id, res[i].HotelType, start, hotelChannel)
-			}
-			for i := 0; i < length; i++ {
-				hotel := <-hotelChannel
-				if hotel == nil {
-					continue
-				}
-				err := g.unitOfWork.Hotel().StoreOrUpdate(hotel)
-				if err != nil {
-					logger.WithName(logtags.SyncHotelsError).WithException(err).
-						Error("problem wile updating hotel information")
-				}
-			}
+		for _, supplier := range g.providers.Suppliers() {
+			g.syncSupplierHotels(context.Background(), supplier, cities, start)
 		}
 		logger.WithName(logtags.SyncingHotelsCompleted).WithData(fmt.Sprintf("syncing hotels completed in %d nanoseconds", time.Since(start))).
 			Info("syncing hotels completed")
 		g.cacheStore.UpdateAmenityStore()
+		g.cacheStore.UpdateGeoStore()
+		g.cacheStore.UpdateAutocompleteStore()
 	}()
 
 	return &dto.TaskRunningResult{
@@ -271,8 +291,51 @@ func (g *hotelService) SyncAllHotels() (*dto.TaskRunningResult, error) {
 	}, nil
 }
 
-func (g *hotelService) updateHotel(hotelId, hotelType string, date time.Time, hotelChannel chan<- *dbmodel.Hotel) {
-	hotelData, err := g.provider.GetHotelData(hotelId, date, date.Add(time.Hour*24))
+func (g *hotelService) syncSupplierHotels(ctx context.Context, supplier string, cities []dto.CityDto, start time.Time) {
+	provider, err := g.providers.Get(supplier)
+	if err != nil {
+		logger.WithName(logtags.SyncHotelsError).WithData(supplier).WithException(err).
+			Error("problem in getting supplier provider")
+		return
+	}
+	he := provider.CreateHotelEnumerable(ctx, cities)
+	for he.MoveNext() {
+		res, err := he.Current()
+		if err != nil {
+			logger.WithName(logtags.SyncHotelsError).WithData(supplier).WithException(err).
+				Error("problem in getting list of hotels")
+			continue
+		}
+		length := len(res)
+		hotelChannel := make(chan *dbmodel.Hotel, length)
+		for i := 0; i < length; i++ {
+			go g.updateHotel(ctx, res[i].Id, res[i].HotelType, supplier, start, hotelChannel)
+		}
+		for i := 0; i < length; i++ {
+			hotel := <-hotelChannel
+			if hotel == nil {
+				continue
+			}
+			err := g.unitOfWork.Hotel().StoreOrUpdate(hotel)
+			if err != nil {
+				logger.WithName(logtags.SyncHotelsError).WithException(err).
+					Error("problem wile updating hotel information")
+			}
+		}
+	}
+}
+
+func (g *hotelService) updateHotel(ctx context.Context, hotelId, hotelType, supplier string, date time.Time, hotelChannel chan<- *dbmodel.Hotel) {
+	provider, err := g.providers.Get(supplier)
+	if err != nil {
+		logger.WithException(err).
+			WithName(logtags.GettingHotelDetailError).
+			WithData(hotelId).
+			Error("problem while getting hotel supplier")
+		hotelChannel <- nil
+		return
+	}
+	hotelData, err := provider.GetHotelData(ctx, hotelId, date, date.Add(time.Hour*24))
 	if err != nil {
 		logger.WithException(err).
 			WithName(logtags.GettingHotelDetailError).
@@ -283,7 +346,7 @@ func (g *hotelService) updateHotel(hotelId, hotelType string, date time.Time, ho
 	}
 	city, _ := g.cacheStore.CityStore().FindOne(hotelData.City)
 	if hotelType == "" {
-		t, err := g.provider.GetHotelType(hotelId)
+		t, err := provider.GetHotelType(ctx, hotelId)
 		if err != nil {
 			logger.WithName(logtags.GettingHotelTypeError).WithException(err).
 				Error("problem while getting hotel type for update")
@@ -292,8 +355,9 @@ func (g *hotelService) updateHotel(hotelId, hotelType string, date time.Time, ho
 	}
 	hotelData.SetProvince(city.State)
 	hotelData.SetType(hotelType)
+	hotelData.SetSupplier(supplier)
 	hotelModel := g.mapper.ToHotelModel(*hotelData)
-	hotel, err := g.unitOfWork.Hotel().FindByIDForSync(hotelModel.PlaceID)
+	hotel, err := g.unitOfWork.Hotel().FindByIDForSync(hotelModel.PlaceID, supplier)
 	if err == nil {
 		hotelModel = hotel.UpdateWith(*hotelModel)
 	}
@@ -308,29 +372,35 @@ func (g *hotelService) updateHotel(hotelId, hotelType string, date time.Time, ho
 	return
 }
 
-func (g *hotelService) GetHotelRooms(dto dto.HotelRoomsDto) (*dto.RateRoomResponseDto, error) {
-	res, err := g.provider.GetHotelRooms(dto)
+func (g *hotelService) GetHotelRooms(ctx context.Context, dto dto.HotelRoomsDto) (*dto.RateRoomResponseDto, error) {
+	res, err := g.cachedHotelRooms(ctx, dto)
 	if err != nil {
 		return nil, err
 	}
 	res.RequestedRooms = dto.Rooms
 	return res, nil
 }
-func (g *hotelService) GetHotelRoomsWithSession(dto dto.HotelRoomsWithSessionDto) (*dto.RateRoomResponseDto, error) {
-	return g.provider.GetHotelRoomsWithSession(dto)
+func (g *hotelService) GetHotelRoomsWithSession(ctx context.Context, dto dto.HotelRoomsWithSessionDto) (*dto.RateRoomResponseDto, error) {
+	return g.cachedHotelRoomsWithSession(ctx, dto)
 }
-func (g *hotelService) HotelAvailable(body dto.AvailableDto) (*dto.AvailableResponseDto, error) {
+func (g *hotelService) HotelAvailable(ctx context.Context, body dto.AvailableDto) (*dto.AvailableResponseDto, error) {
 	if err := hotelAvailableGuard(body.HotelId, body.PhoneNumber); err != nil {
 		return nil, err
 	}
-	detail, err := g.provider.GetOrderDetail(body.HotelId, body.SessionId,
+	supplier := g.hotelSupplier(body.Supplier)
+	provider, err := g.providers.Get(supplier)
+	if err != nil {
+		return nil, err
+	}
+	detail, err := g.cachedOrderDetail(ctx, provider, supplier, body.HotelId, body.SessionId,
 		body.OptionId)
 	if err != nil {
 		return nil, err
 	}
-	available, err := g.provider.HotelAvailable(body)
+	available, err := provider.HotelAvailable(ctx, body)
 	if err != nil {
 		if strings.Contains(err.Error(), "Room is not available") {
+			g.invalidateHotelRooms(supplier, body.HotelId, "room is not available")
 			return nil, common.RoomIsNotAvailable
 		}
 		return nil, err
@@ -345,7 +415,17 @@ func (g *hotelService) HotelAvailable(body dto.AvailableDto) (*dto.AvailableResp
 	detail.Status = available.Status
 	detail.TotalPrice = available.TotalPrice
 	detail.IndraOrderId = available.IndraOrderId
-	_, err = g.unitOfWork.Order().Insert(g.mapper.ToOrderModel(*detail))
+	detail.Supplier = supplier
+	detail.CheckIn = available.CheckIn
+	detail.CheckOut = available.CheckOut
+	detail.PhoneNumber = body.PhoneNumber
+	detail.CancellationRules = g.orderCancellationRules(detail, body.HotelId, available.CheckIn)
+	order := g.mapper.ToOrderModel(*detail)
+	order.State = common.OrderState_Draft
+	if err := transitOrder(&order, common.OrderState_Held, "hotel available"); err != nil {
+		return nil, err
+	}
+	_, err = g.unitOfWork.Order().Insert(order)
 	if err != nil {
 		return nil, err
 	}
@@ -375,57 +455,90 @@ func CompareProviderAndResellerDates(pCheckIn, pCheckOut, rCheckIn, rCheckOut st
 	return common.DatesNotMatchError
 }
 
-func (g *hotelService) FinalizeHotelOrder(request dto.FinalizeOrderDto) (*dto.FinalizeOrderResponseDto, error) {
-	_, err := g.ConfirmOrder(request.OrderId)
+func (g *hotelService) GetAnOrderDetail(ctx context.Context, orderId string) (*dto.OrderDetailDto, error) {
+	order, err := g.unitOfWork.Order().GetOneByIndraId(orderId)
 	if err != nil {
 		return nil, err
 	}
-	payResult, err := g.PayByAccount(request.OrderId)
+	hotel, err := g.unitOfWork.Hotel().GetHotel(order.ProviderHotelId, order.Supplier)
 	if err != nil {
 		return nil, err
 	}
-	statusResult, err := g.GetOrderStatus(request.OrderId)
+	order.StateHistory, err = g.unitOfWork.Order().GetStateHistory(order.ID)
 	if err != nil {
 		return nil, err
 	}
-	successRes := &dto.FinalizeOrderResponseDto{
-		PaymentResult: &payResult,
-		StatusResult:  &statusResult,
-		Error:         nil,
-	}
-	logger.WithName(logtags.OrderFinalizedCompleted).WithData(successRes).
-		Info("order finalized successfully")
-	return successRes, nil
+	orderDto := g.mapper.ToOrderDto(*order)
+	orderDto.Hotel = *g.mapper.ToHotelDto(*hotel)
+	return &orderDto, nil
 }
 
-func (g *hotelService) GetAnOrderDetail(orderId string) (*dto.OrderDetailDto, error) {
-	order, err := g.unitOfWork.Order().GetOneByIndraId(orderId)
+func (g *hotelService) SearchResult(ctx context.Context, request dto.SearchDto) (*dto.SearchResponseDto, error) {
+	daysDiff, err := date.DaysDiff(request.Date.Start, request.Date.End)
 	if err != nil {
 		return nil, err
 	}
-	hotel, err := g.unitOfWork.Hotel().GetHotel(order.ProviderHotelId)
+	area, err := g.resolveGeoSearch(&request)
 	if err != nil {
 		return nil, err
 	}
-	orderDto := g.mapper.ToOrderDto(*order)
-	orderDto.Hotel = *g.mapper.ToHotelDto(*hotel)
-	return &orderDto, nil
-}
-
-func (g *hotelService) SearchResult(request dto.SearchDto) (*dto.SearchResponseDto, error) {
-	daysDiff, err := date.DaysDiff(request.Date.Start, request.Date.End)
+	searchDto, err := g.searchDtoAdopter.CreateProviderDto(request)
 	if err != nil {
 		return nil, err
 	}
-	searchDto, err := g.searchDtoAdopter.CreateProviderDto(request)
+	filter := g.localSearchFilter(request)
+	if area != nil {
+		searchDto.RequestSearchHotels.Limit = int64(g.geoSearchWindow)
+		searchDto.RequestSearchHotels.Skip = 0
+	} else if filter != nil {
+		searchDto.RequestSearchHotels.Limit = int64(g.facetWindow)
+		searchDto.RequestSearchHotels.Skip = 0
+	}
+	provider, err := g.providers.Get(request.Supplier)
 	if err != nil {
 		return nil, err
 	}
-	results, totalHits, err := g.provider.SearchResult(*searchDto)
+	page, sessionId, err := g.searchResults(ctx, provider, request.Supplier, *searchDto)
 	if err != nil {
+		if g.searchFallback && isSupplierUnavailableError(err) {
+			return g.catalogueSearch(request, area, daysDiff, err)
+		}
 		return nil, err
 	}
-	hotels, err := g.getHotelsFromResults(results)
+	results, totalHits := page.Results, page.TotalHits
+	distances := make(map[string]float64)
+	var facets []dtos.Result
+	if area != nil || filter != nil {
+		window, err := g.filterResults(page.Results, filter)
+		if err != nil {
+			return nil, err
+		}
+		matches := allMatches(len(window))
+		if area != nil {
+			matches = area.match(len(window), func(i int) (geo.Point, bool) {
+				coordinates := window[i].Location.Coordinates
+				if len(coordinates) != 2 {
+					return geo.Point{}, false
+				}
+				return geo.Point{Lat: coordinates[1], Lon: coordinates[0]}, true
+			})
+		}
+		facets = make([]dtos.Result, 0, len(matches))
+		for _, m := range matches {
+			facets = append(facets, window[m.index])
+		}
+		totalHits = len(matches)
+		results = make([]dtos.Result, 0)
+		for _, m := range pageOf(matches, request.PageNumber, request.PageSize) {
+			results = append(results, window[m.index])
+			if area != nil {
+				distances[window[m.index].ID] = m.distance
+			}
+		}
+	} else {
+		facets = g.facetResults(ctx, provider, request.Supplier, sessionId, *searchDto, page)
+	}
+	hotels, err := g.getHotelsFromResults(results, g.hotelSupplier(request.Supplier))
 	if err != nil {
 		return nil, err
 	}
@@ -434,21 +547,34 @@ func (g *hotelService) SearchResult(request dto.SearchDto) (*dto.SearchResponseD
 	if err != nil {
 		return nil, err
 	}
-	filters := g.publicService.GetAllFilters()
+	g.setSearchOptions(res, area != nil, supplierFacetItems(facets))
+	setDistances(res, distances)
+	res.TotalHits = totalHits
+	res.SessionId = sessionId
+	logger.WithName(logtags.SearchCompleted).Info("Search hotels completed successfully")
+	return res, nil
+}
+
+//setSearchOptions sets the sorts and the filter options of the search, the options are counted on the facet items
+func (g *hotelService) setSearchOptions(res *dto.SearchResponseDto, geoSearch bool, facets []facetItem) {
+	filters, minPrice, maxPrice := g.searchFilters(facets)
 	res.Sorts = g.publicService.GetAllSorts()
+	if geoSearch {
+		res.Sorts = append(res.Sorts, distanceSort())
+	}
 	res.Filters = []dto.FilterOutput{
 		filters.PriceFilter,
 		filters.AmenityFilters,
+		filters.AmenityCategoryFilters,
 		filters.ScoreFilters,
 		filters.StarFilters,
 		filters.HotelTypes,
 	}
-	res.TotalHits = totalHits
-	logger.WithName(logtags.SearchCompleted).Info("Search hotels completed successfully")
-	return res, nil
+	res.MinPrice = minPrice
+	res.MaxPrice = maxPrice
 }
 
-func (g *hotelService) SetAmenityIcon(request dto.SetAmenityIconDto) (dto.HotelAmenityDto, error) {
+func (g *hotelService) SetAmenityIcon(ctx context.Context, request dto.SetAmenityIconDto) (dto.HotelAmenityDto, error) {
 	amenity, err := g.unitOfWork.Amenity().UpdateIcon(request.AmenityId, request.IconUrl)
 	if err != nil {
 		return dto.HotelAmenityDto{}, err
@@ -457,7 +583,7 @@ func (g *hotelService) SetAmenityIcon(request dto.SetAmenityIconDto) (dto.HotelA
 	return *g.mapper.ToAmenityDto(*amenity), nil
 }
 
-func (g *hotelService) SetAmenityCategory(request dto.SetAmenityCategoryDto) (dto.HotelAmenityDto, error) {
+func (g *hotelService) SetAmenityCategory(ctx context.Context, request dto.SetAmenityCategoryDto) (dto.HotelAmenityDto, error) {
 	_, err := g.unitOfWork.AmenityCategory().GetOneById(request.AmenityCategoryId)
 	if err != nil {
 		return dto.HotelAmenityDto{}, err
@@ -470,20 +596,25 @@ func (g *hotelService) SetAmenityCategory(request dto.SetAmenityCategoryDto) (dt
 	return *g.mapper.ToAmenityDto(*amenity), nil
 }
 
-func (g *hotelService) getHotelsFromResults(results []dtos.Result) ([]dbmodel.Hotel, error) {
+func (g *hotelService) getHotelsFromResults(results []dtos.Result, supplier string) ([]dbmodel.Hotel, error) {
 	ids := make([]string, 0)
 	for _, result := range results {
 		ids = append(ids, result.ID)
 	}
-	return g.unitOfWork.Hotel().GetHotels(ids)
+	return g.unitOfWork.Hotel().GetHotels(ids, supplier)
 }
 
-func (g *hotelService) GetHotelOptionInfo(infoDto dto.OptionInfoRequestDto) (*dto.OptionInfoResponseDto, error) {
-	return g.provider.GetHotelOptionInfo(infoDto)
+func (g *hotelService) GetHotelOptionInfo(ctx context.Context, infoDto dto.OptionInfoRequestDto) (*dto.OptionInfoResponseDto, error) {
+	res, err := g.cachedHotelOptionInfo(ctx, infoDto)
+	if err != nil {
+		return nil, err
+	}
+	res.Policy.Rules = policyRules(res.Policy.NonRefundable, res.Policy.GeneralPolicies)
+	return res, nil
 }
 
-func (g *hotelService) GetHotels(ids []string) ([]dto.HotelDto, error) {
-	hotels, _ := g.unitOfWork.Hotel().GetHotels(ids)
+func (g *hotelService) GetHotels(ctx context.Context, ids []string, supplier string) ([]dto.HotelDto, error) {
+	hotels, _ := g.unitOfWork.Hotel().GetHotels(ids, g.hotelSupplier(supplier))
 	result := make([]dto.HotelDto, 0)
 	for _, hotel := range hotels {
 		result = append(result, *g.mapper.ToHotelDto(hotel))
@@ -491,7 +622,7 @@ func (g *hotelService) GetHotels(ids []string) ([]dto.HotelDto, error) {
 	return result, nil
 }
 
-func (g *hotelService) ConfirmOrder(orderId string) (dto.ConfirmResponseDto, error) {
+func (g *hotelService) ConfirmOrder(ctx context.Context, orderId string) (dto.ConfirmResponseDto, error) {
 	order, err := g.unitOfWork.Order().GetOneByIndraId(orderId)
 	if err != nil {
 		return dto.ConfirmResponseDto{}, err
@@ -502,8 +633,15 @@ func (g *hotelService) ConfirmOrder(orderId string) (dto.ConfirmResponseDto, err
 			Error:   nil,
 		}, nil
 	}
+	provider, err := g.providers.Get(order.Supplier)
+	if err != nil {
+		return dto.ConfirmResponseDto{}, err
+	}
+	if err := transitOrder(order, common.OrderState_Confirmed, "confirm order"); err != nil {
+		return dto.ConfirmResponseDto{}, err
+	}
 	order.UpdateConfirmed(true)
-	res, err := g.provider.ConfirmOrder(orderId)
+	res, err := provider.ConfirmOrder(ctx, orderId)
 	if err != nil {
 		return dto.ConfirmResponseDto{}, err
 	}
@@ -513,7 +651,7 @@ func (g *hotelService) ConfirmOrder(orderId string) (dto.ConfirmResponseDto, err
 	return res, err
 }
 
-func (g *hotelService) PayByAccount(orderId string) (dto.OrderPayByAccountResponseDto, error) {
+func (g *hotelService) PayByAccount(ctx context.Context, orderId string) (dto.OrderPayByAccountResponseDto, error) {
 	order, err := g.unitOfWork.Order().GetOneByIndraId(orderId)
 	if err != nil {
 		return dto.OrderPayByAccountResponseDto{}, err
@@ -527,16 +665,27 @@ func (g *hotelService) PayByAccount(orderId string) (dto.OrderPayByAccountRespon
 			Error:             nil,
 		}, nil
 	}
-	res, err := g.provider.PayByAccount(orderId)
+	provider, err := g.providers.Get(order.Supplier)
+	if err != nil {
+		return dto.OrderPayByAccountResponseDto{}, err
+	}
+	if err := checkTransition(order, common.OrderState_Paid); err != nil {
+		return dto.OrderPayByAccountResponseDto{}, err
+	}
+	res, err := provider.PayByAccount(ctx, orderId)
 	go g.balanceChecker.CheckAdequateBalance()
 	if err != nil {
 		return res, err
 	}
-	if res.TransactionStatus == "Pending" {
+	state := common.OrderState_Paid
+	if res.TransactionStatus == common.TransactionStatus_Pending {
+		state = common.OrderState_PaymentPending
 		logger.WithName(logtags.CannotCompleteOrderPayment).Error("hotel payment status is Pending. please check if the alibaba hotel client has adequate balance for payment")
 	}
-	order.UpdateTransaction(res.TransactionStatus, strings.Join(res.TransactionIds, ","),
-		res.RequestId)
+	if err := transitOrder(order, state, "pay by account"); err != nil {
+		return res, err
+	}
+	order.UpdateTransaction(res.TransactionStatus, res.RequestId, strings.Join(res.TransactionIds, ","))
 	err = g.unitOfWork.Order().StoreOrUpdate(*order)
 
 	logger.WithName(logtags.PayByAccountCompleted).WithData(res).
@@ -544,30 +693,41 @@ func (g *hotelService) PayByAccount(orderId string) (dto.OrderPayByAccountRespon
 	return res, err
 }
 
-func (g *hotelService) GetOrderStatus(orderId string) (dto.OrderStatusResponseDto, error) {
+func (g *hotelService) GetOrderStatus(ctx context.Context, orderId string) (dto.OrderStatusResponseDto, error) {
 	order, err := g.unitOfWork.Order().GetOneByIndraId(orderId)
 	if err != nil {
 		return dto.OrderStatusResponseDto{}, err
 	}
-	res, err := g.provider.GetOrderStatus(orderId)
+	provider, err := g.providers.Get(order.Supplier)
+	if err != nil {
+		return dto.OrderStatusResponseDto{}, err
+	}
+	res, err := provider.GetOrderStatus(ctx, orderId)
 	if err != nil {
 		return res, err
 	}
 	order.UpdateStatus(res.Status)
+	followSupplierStatus(order, res.Status)
 	err = g.unitOfWork.Order().StoreOrUpdate(*order)
 	return res, err
 }
 
-func (g *hotelService) GetOrderEnquiry(orderId string) (dto.OrderEnquiryResponseDto, error) {
+func (g *hotelService) GetOrderEnquiry(ctx context.Context, orderId string) (dto.OrderEnquiryResponseDto, error) {
 	order, err := g.unitOfWork.Order().GetOneByIndraId(orderId)
 	if err != nil {
 		return dto.OrderEnquiryResponseDto{}, err
 	}
-	return g.provider.GetOrderEnquiry(orderId, order.ProviderOrderId)
+	provider, err := g.providers.Get(order.Supplier)
+	if err != nil {
+		return dto.OrderEnquiryResponseDto{}, err
+	}
+	return provider.GetOrderEnquiry(ctx, orderId, order.ProviderOrderId)
 }
 
-func (g *hotelService) RefundOrder(refundRequest dto.OrderRefundRequestDto) (dto.OrderRefundResponseDto, error) {
-	order, err := g.unitOfWork.Order().GetOneByIndraId(refundRequest.OrderId)
+//RefundOrder requests the refund of the whole order or of some of its rooms or passengers, an order can have several
+//refund requests as long as they do not refund the same reference code twice
+func (g *hotelService) RefundOrder(ctx context.Context, refundRequest dto.OrderRefundRequestDto) (dto.OrderRefundResponseDto, error) {
+	order, provider, err := g.orderForRefund(refundRequest.OrderId)
 	if err != nil {
 		return dto.OrderRefundResponseDto{}, err
 	}
@@ -578,19 +738,47 @@ func (g *hotelService) RefundOrder(refundRequest dto.OrderRefundRequestDto) (dto
 			Error:           nil,
 		}, nil
 	}
-	res, err := g.provider.RefundOrder(refundRequest.OrderId, order.ProviderOrderId)
+	for _, refund := range order.Refunds {
+		if refund.ApplicantRefundRequestId == refundRequest.RefundRequestID {
+			return dto.OrderRefundResponseDto{
+				OrderId:         refundRequest.OrderId,
+				RefundRequestId: refund.RefundRequestId,
+				Error:           nil,
+			}, nil
+		}
+	}
+	if err := checkRefundable(order, refundRequest.ReferenceCodes); err != nil {
+		return dto.OrderRefundResponseDto{}, err
+	}
+	if err := transitOrder(order, common.OrderState_RefundRequested, "refund order"); err != nil {
+		return dto.OrderRefundResponseDto{}, err
+	}
+	if err := g.checkRefundQuote(ctx, provider, order, refundRequest); err != nil {
+		return dto.OrderRefundResponseDto{}, err
+	}
+	referenceCodes := refundRequest.ReferenceCodes
+	if len(referenceCodes) == 0 {
+		referenceCodes = []string{order.ProviderOrderId}
+	}
+	res, err := provider.RefundOrder(ctx, refundRequest.OrderId, referenceCodes)
 	if err != nil {
 		return res, err
 	}
-	order.UpdateRefundRequestId(res.RefundRequestId, refundRequest.RefundRequestID, refundRequest.JabamaOrderID)
+	order.Refunds = append(order.Refunds, dbmodel.OrderRefund{
+		OrderID:                  order.ID,
+		RefundRequestId:          res.RefundRequestId,
+		ApplicantRefundRequestId: refundRequest.RefundRequestID,
+		ApplicantOrderId:         refundRequest.JabamaOrderID,
+		ReferenceCodes:           strings.Join(refundRequest.ReferenceCodes, ","),
+	})
 	err = g.unitOfWork.Order().StoreOrUpdate(*order)
 	logger.WithName(logtags.NewRefundRequest).WithData(refundRequest).
 		Info(fmt.Sprintf("order with id %s commited a refund request", refundRequest.OrderId))
 	return res, err
 }
 
-func (g *hotelService) UpdateHotelRateReview(rateDto dto.RateReviewEventDto) error {
-	hotel, err := g.unitOfWork.Hotel().GetHotel(rateDto.PlaceId)
+func (g *hotelService) UpdateHotelRateReview(ctx context.Context, rateDto dto.RateReviewEventDto) error {
+	hotel, err := g.unitOfWork.Hotel().GetHotel(rateDto.PlaceId, g.hotelSupplier(rateDto.Supplier))
 	if err != nil {
 		logger.WithName(logtags.GettingHotelDetailError).ErrorException(err, "error while trying to find hotel to update rate and review details")
 		return err
@@ -604,8 +792,22 @@ func (g *hotelService) UpdateHotelRateReview(rateDto dto.RateReviewEventDto) err
 	return nil
 }
 
-func (g *hotelService) UpdateRefundedOrdersPaymentStatus(fromDate time.Time) {
-	ids, err := g.unitOfWork.Order().GetProperOrderIdsForRefundUpdateStatus(fromDate)
+func (g *hotelService) UpdateRefundedOrdersPaymentStatus(ctx context.Context, fromDate time.Time) {
+	for _, supplier := range g.providers.Suppliers() {
+		g.updateSupplierRefundedOrdersPaymentStatus(ctx, fromDate, supplier)
+	}
+}
+
+func (g *hotelService) updateSupplierRefundedOrdersPaymentStatus(ctx context.Context, fromDate time.Time, supplier string) {
+	provider, err := g.providers.Get(supplier)
+	if err != nil {
+		logger.WithName(logtags.GettingListOfRefundableOrdersError).WithData(supplier).
+			ErrorException(err, "error while trying to get supplier provider for updating refund status")
+		return
+	}
+	g.updateSupplierRefundsStatus(ctx, provider, fromDate, supplier)
+	//the orders that were refunded before the refund requests were kept apart have the refund on the order
+	ids, err := g.unitOfWork.Order().GetProperOrderIdsForRefundUpdateStatus(fromDate, supplier)
 	if err != nil {
 		logger.WithName(logtags.GettingListOfRefundableOrdersError).
 			ErrorException(err, "error while trying to get list of proper order ids for updating refund status")
@@ -614,7 +816,7 @@ func (g *hotelService) UpdateRefundedOrdersPaymentStatus(fromDate time.Time) {
 	for _, orderIds := range array.Chunks(ids, g.syncChunkSize) {
 		length := len(orderIds)
 		orderChannel := make(chan *dbmodel.Order, length)
-		g.tryUpdatingOrdersRefundStatus(orderIds, orderChannel)
+		g.tryUpdatingOrdersRefundStatus(ctx, provider, orderIds, orderChannel)
 		for i := 0; i < length; i++ {
 			order := <-orderChannel
 			if order == nil {
@@ -639,9 +841,10 @@ func (g *hotelService) UpdateRefundedOrdersPaymentStatus(fromDate time.Time) {
 	}
 }
 
-func (g *hotelService) tryUpdatingOrdersRefundStatus(orderIds []string, orderChannel chan<- *dbmodel.Order) {
+func (g *hotelService) tryUpdatingOrdersRefundStatus(ctx context.Context, provider core.HotelProvider, orderIds []string,
+	orderChannel chan<- *dbmodel.Order) {
 	ordersLength := len(orderIds)
-	ordersRefundStatus, err := g.provider.GetOrdersRefundStatus(orderIds, len(orderIds), 1)
+	ordersRefundStatus, err := provider.GetOrdersRefundStatus(ctx, orderIds, len(orderIds), 1)
 	if err != nil {
 		for i := 0; i < ordersLength; i++ {
 			orderChannel <- nil
@@ -671,12 +874,16 @@ func (g *hotelService) tryUpdatingOrdersRefundStatus(orderIds []string, orderCha
 			}
 			order.UpdateRefundResult(item.PaidAmount, item.ReferenceCode, details.RefundStatus,
 				item.RefundableAmount, item.TotalPenaltyAmount)
+			if err := transitOrder(order, common.OrderState_Refunded, "refund finalized"); err != nil {
+				logger.WithName(logtags.OrderTransitionRejected).WithException(err).WithData(orderId).
+					Warn("finalized refund is not allowed by the state of the order")
+			}
 			orderChannel <- order
 		}(details, id)
 	}
 }
 
-func (g *hotelService) GetHotelsList(body dto.HotelsPageRequestDto) (dto.HotelsPageResponseDto, error) {
+func (g *hotelService) GetHotelsList(ctx context.Context, body dto.HotelsPageRequestDto) (dto.HotelsPageResponseDto, error) {
 	hotels, total, err := g.unitOfWork.Hotel().GetHotelsList(body.PageNumber, body.PageSize, body.Search)
 	if err != nil {
 		logger.WithName(logtags.GettingHotelsListError).ErrorException(err, err.Error())
@@ -691,8 +898,54 @@ func (g *hotelService) GetHotelsList(body dto.HotelsPageRequestDto) (dto.HotelsP
 	}, nil
 }
 
-func (g *hotelService) SetHotelSeoDetails(body dto.SetHotelSeoRequestDto) (dto.HotelDto, error) {
-	hotel, err := g.unitOfWork.Hotel().FindByID(body.HotelId)
+//GetOrdersList returns a page of the orders of the filter with the summary of their hotel, the totals are of all the
+//orders of the filter
+func (g *hotelService) GetOrdersList(ctx context.Context, body dto.OrdersPageRequestDto) (dto.OrdersPageResponseDto, error) {
+	orders, total, err := g.unitOfWork.Order().GetOrdersList(body)
+	if err != nil {
+		logger.WithName(logtags.GettingOrdersListError).ErrorException(err, err.Error())
+		return dto.OrdersPageResponseDto{}, err
+	}
+	totals, err := g.unitOfWork.Order().GetOrdersTotals(body)
+	if err != nil {
+		logger.WithName(logtags.GettingOrdersListError).ErrorException(err, err.Error())
+		return dto.OrdersPageResponseDto{}, err
+	}
+	res := dto.OrdersPageResponseDto{
+		PageNumber:  body.PageNumber,
+		PageSize:    body.PageSize,
+		Total:       total,
+		StateTotals: make(map[string]int, len(totals)),
+		Orders:      make([]dto.OrderSummaryDto, 0, len(orders)),
+	}
+	for _, item := range totals {
+		res.TotalPrice += item.TotalPrice
+		res.StateTotals[item.State] += item.Count
+	}
+
+	//a place id is only unique between the hotels of a supplier so the hotels are found per supplier
+	ids := make(map[string][]string)
+	for _, order := range orders {
+		ids[order.Supplier] = append(ids[order.Supplier], order.ProviderHotelId)
+	}
+	hotels := make(map[string]*dbmodel.Hotel, len(orders))
+	for supplier, supplierIds := range ids {
+		items, err := g.unitOfWork.Hotel().GetHotels(supplierIds, supplier)
+		if err != nil {
+			return dto.OrdersPageResponseDto{}, err
+		}
+		for i := range items {
+			hotels[supplier+":"+items[i].PlaceID] = &items[i]
+		}
+	}
+	for _, order := range orders {
+		res.Orders = append(res.Orders, g.mapper.ToOrderSummaryDto(order, hotels[order.Supplier+":"+order.ProviderHotelId]))
+	}
+	return res, nil
+}
+
+func (g *hotelService) SetHotelSeoDetails(ctx context.Context, body dto.SetHotelSeoRequestDto) (dto.HotelDto, error) {
+	hotel, err := g.unitOfWork.Hotel().FindByID(body.HotelId, g.hotelSupplier(body.Supplier))
 	if err != nil {
 		logger.WithName(logtags.SearchHotelsRequest).ErrorException(err, err.Error())
 		return dto.HotelDto{}, err
@@ -707,8 +960,8 @@ func (g *hotelService) SetHotelSeoDetails(body dto.SetHotelSeoRequestDto) (dto.H
 	return *g.mapper.ToHotelDto(*hotel), nil
 }
 
-func (g *hotelService) SetHotelFaq(body dto.SetHotelFaqRequestDto) (dto.HotelDto, error) {
-	hotel, err := g.unitOfWork.Hotel().FindByID(body.HotelId)
+func (g *hotelService) SetHotelFaq(ctx context.Context, body dto.SetHotelFaqRequestDto) (dto.HotelDto, error) {
+	hotel, err := g.unitOfWork.Hotel().FindByID(body.HotelId, g.hotelSupplier(body.Supplier))
 	if err != nil {
 		logger.WithName(logtags.SearchHotelsRequest).ErrorException(err, err.Error())
 		return dto.HotelDto{}, err
@@ -722,9 +975,9 @@ func (g *hotelService) SetHotelFaq(body dto.SetHotelFaqRequestDto) (dto.HotelDto
 	return *g.mapper.ToHotelDto(*hotel), nil
 }
 
-func (g *hotelService) RemoveHotelFaq(hotelId string, faqId uint) (dto.HotelDto, error) {
-
-	hotel, err := g.unitOfWork.Hotel().FindByID(hotelId)
+func (g *hotelService) RemoveHotelFaq(ctx context.Context, hotelId, supplier string, faqId uint) (dto.HotelDto, error) {
+	supplier = g.hotelSupplier(supplier)
+	hotel, err := g.unitOfWork.Hotel().FindByID(hotelId, supplier)
 	if err != nil {
 		logger.WithName(logtags.SearchHotelsRequest).ErrorException(err, err.Error())
 		return dto.HotelDto{}, err
@@ -733,7 +986,7 @@ func (g *hotelService) RemoveHotelFaq(hotelId string, faqId uint) (dto.HotelDto,
 	if err != nil {
 		return dto.HotelDto{}, err
 	}
-	hotel, err = g.unitOfWork.Hotel().RemoveFAQ(hotelId, &faq)
+	hotel, err = g.unitOfWork.Hotel().RemoveFAQ(hotelId, supplier, &faq)
 	if err != nil {
 		logger.WithName(logtags.CannotRemoveHotelFAQ).ErrorException(err, err.Error())
 		return dto.HotelDto{}, err
@@ -755,6 +1008,16 @@ func getOrderStatusDetails(items []dto.OrdersRefundStatusResponseDtoResultItem,
 	return dto.OrdersRefundStatusResponseDtoResultItem{}, false
 }
 
+//hotelSupplier returns the supplier of a hotel request. a place id is only unique between the hotels of a supplier so
+//the requests carry the supplier of the hotel, a request without a supplier is for the default supplier
+func (g *hotelService) hotelSupplier(supplier string) string {
+	supplier = normalizeSupplier(supplier)
+	if supplier == "" {
+		return g.providers.DefaultSupplier()
+	}
+	return supplier
+}
+
 func hotelAvailableGuard(hotelId, phone string) error {
 	c := config.Get()
 
@@ -771,14 +1034,15 @@ func hotelAvailableGuard(hotelId, phone string) error {
 	return common.HotelReserveForbidden
 }
 
-func NewHotelService(unit core.UnitOfWork, mapper core.Mapper, provider core.HotelProvider,
+func NewHotelService(unit core.UnitOfWork, mapper core.Mapper, providers core.HotelProviderRegistry,
 	searchDtoAdopter core.SearchDtoAdopter, publicService core.PublicService,
 	cacheStore core.CacheStore, balanceChecker core.ProviderBalanceChecker,
-	orderEventDispatcher core.OrderEventDispatcher) core.HotelService {
+	orderEventDispatcher core.OrderEventDispatcher, responseCache core.TtlCache,
+	locker core.DistributedLocker) core.HotelService {
 	con := config.Get()
 	return &hotelService{
 		mapper:               mapper,
-		provider:             provider,
+		providers:            providers,
 		unitOfWork:           unit,
 		searchDtoAdopter:     searchDtoAdopter,
 		publicService:        publicService,
@@ -786,5 +1050,26 @@ func NewHotelService(unit core.UnitOfWork, mapper core.Mapper, provider core.Hot
 		balanceChecker:       balanceChecker,
 		syncChunkSize:        con.SyncChunkSize,
 		orderEventDispatcher: orderEventDispatcher,
+		responseCache:        responseCache,
+		roomsCacheTtl:        con.RoomCache.RoomsTtl,
+		optionInfoCacheTtl:   con.RoomCache.OptionInfoTtl,
+		searchSessionTtl:     con.SearchCache.SessionTtl,
+		searchPageTtl:        con.SearchCache.PageTtl,
+		searchFallback:       con.SearchFallbackEnabled,
+		geoSearchWindow:      con.GeoSearchWindow,
+		facetWindow:          con.SearchFacetWindow,
+		calendarWorkers:      con.PriceCalendar.Concurrency,
+		calendarMaxDays:      con.PriceCalendar.MaxDays,
+		calendarTtl:          con.PriceCalendar.Ttl,
+		calendarTimeout:      con.ProviderTimeouts.Rooms,
+		syncCalendarDays:     con.SyncCalendar.Days,
+		syncCalendarWorkers:  con.SyncCalendar.Concurrency,
+		syncCalendarLimiter:  ratelimit.NewLimiter(con.SyncCalendar.RatePerSecond, con.SyncCalendar.Concurrency),
+		refundQuoteSecret:    con.RefundQuote.Secret,
+		refundQuoteTtl:       con.RefundQuote.Ttl,
+		sagaMaxAttempts:      con.OrderSaga.MaxAttempts,
+		sagaStaleAfter:       con.OrderSaga.StaleAfter,
+		sagaLockKey:          con.SyncLockKey + "-order-saga-",
+		locker:               locker,
 	}
 }
@@ -13,6 +13,7 @@ import (
 type orderEventDispatcher struct {
 	client       messaging.Bus
 	messageTopic string
+	orderTopic   string
 }
 
 type OrderRefundRequestFinalizedEvent struct {
@@ -58,9 +59,23 @@ func (d *orderEventDispatcher) OrderRefundRequestFinalized(event dto.OrderRefund
 		Info(fmt.Sprintf("Refund request for order with id %s completed automatically", event.ProviderOrderId))
 }
 
-func NewOrderEventDispatcher(client messaging.Bus, topic string) core.OrderEventDispatcher {
+//OrderReconciled publishes a change of the order that the reconciliation with the supplier has found
+func (d *orderEventDispatcher) OrderReconciled(event dto.OrderReconciledDto) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		logger.WithName(logtags.CannotCreateOrderEventError).ErrorException(err, "Cannot create order reconciled event object")
+		return
+	}
+	err = d.client.Publish(body, d.orderTopic, "topic", d.orderTopic)
+	if err != nil {
+		logger.WithName(logtags.CannotPublishOrderEventError).ErrorException(err, "Cannot publish order reconciled event object")
+	}
+}
+
+func NewOrderEventDispatcher(client messaging.Bus, topic, orderTopic string) core.OrderEventDispatcher {
 	return &orderEventDispatcher{
 		client:       client,
 		messageTopic: topic,
+		orderTopic:   orderTopic,
 	}
 }
@@ -0,0 +1,138 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"hotel-engine/core"
+	"hotel-engine/core/common"
+	"hotel-engine/core/common/logtags"
+	"hotel-engine/core/dbmodel"
+	"hotel-engine/core/dto"
+	"hotel-engine/infrastructure/logger"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//ReconcileOrders compares the orders that are not in a final state with their supplier, an order that is changed or
+//does not match the supplier is published
+func (g *hotelService) ReconcileOrders(ctx context.Context, fromDate time.Time) {
+	for _, supplier := range g.providers.Suppliers() {
+		provider, err := g.providers.Get(supplier)
+		if err != nil {
+			logger.WithName(logtags.OrderReconciliationError).WithData(supplier).
+				ErrorException(err, "error while trying to get supplier provider for reconciling orders")
+			continue
+		}
+		orders, err := g.unitOfWork.Order().GetOrdersToReconcile(fromDate, supplier)
+		if err != nil {
+			logger.WithName(logtags.OrderReconciliationError).WithData(supplier).
+				ErrorException(err, "error while trying to get list of orders for reconciling")
+			continue
+		}
+		for i := range orders {
+			g.lockAndReconcileOrder(ctx, provider, strconv.FormatInt(orders[i].IndraOrderId, 10))
+		}
+	}
+}
+
+//lockAndReconcileOrder reconciles the order under the lock of its finalization and reads it again once locked, an order
+//whose finalization is running is left to the next reconciliation
+func (g *hotelService) lockAndReconcileOrder(ctx context.Context, provider core.HotelProvider, orderId string) {
+	err := g.locker.Lock(g.sagaLockKey+orderId, g.sagaStaleAfter, func() {
+		order, err := g.unitOfWork.Order().GetOneByIndraId(orderId)
+		if err != nil {
+			logger.WithName(logtags.OrderReconciliationError).WithData(orderId).
+				ErrorException(err, "error while trying to get the order for reconciling")
+			return
+		}
+		g.reconcileOrder(ctx, provider, order)
+	})
+	if err != nil {
+		logger.WithName(logtags.OrderReconciliationError).WithData(orderId).
+			ErrorException(err, "error while trying to lock the order for reconciling")
+	}
+}
+
+//reconcileOrder moves the order to the state of its supplier status, a supplier status that the state does not allow
+//and a payment or refund that the supplier does not agree with are kept as the mismatch of the order for the support
+func (g *hotelService) reconcileOrder(ctx context.Context, provider core.HotelProvider, order *dbmodel.Order) {
+	orderId := strconv.FormatInt(order.IndraOrderId, 10)
+	status, err := provider.GetOrderStatus(ctx, orderId)
+	if err != nil {
+		logger.WithName(logtags.OrderReconciliationError).WithData(orderId).
+			ErrorException(err, "error while trying to get status of the order for reconciling")
+		return
+	}
+	event := dto.OrderReconciledDto{
+		OrderId:        orderId,
+		PreviousStatus: order.Status,
+		Status:         status.Status,
+		PreviousState:  order.CurrentState(),
+		Mismatches:     make([]string, 0),
+		ReconciledAt:   time.Now(),
+	}
+	supplierState, found := supplierOrderStates[status.Status]
+	if found {
+		if err := transitOrder(order, supplierState, "reconciliation"); err != nil {
+			event.Mismatches = append(event.Mismatches, err.Error())
+		}
+	}
+	if order.TransactionRequestId != "" {
+		if supplierState == common.OrderState_Failed || supplierState == common.OrderState_Expired {
+			event.Mismatches = append(event.Mismatches,
+				fmt.Sprintf("order is paid but the supplier status is %s", status.Status))
+		}
+		enquiry, err := provider.GetOrderEnquiry(ctx, orderId, order.ProviderOrderId)
+		if err != nil {
+			logger.WithName(logtags.OrderReconciliationError).WithData(orderId).
+				ErrorException(err, "error while trying to get enquiry of the order for reconciling")
+		} else {
+			event.Mismatches = append(event.Mismatches, paymentMismatches(order, enquiry)...)
+		}
+	}
+	event.State = order.CurrentState()
+
+	mismatch := strings.Join(event.Mismatches, "; ")
+	if event.Status == event.PreviousStatus && event.State == event.PreviousState &&
+		mismatch == order.ReconciliationMismatch {
+		return
+	}
+	order.UpdateStatus(status.Status)
+	order.UpdateReconciliationMismatch(mismatch)
+	if err := g.unitOfWork.Order().UpdateReconciliation(*order); err != nil {
+		logger.WithName(logtags.CannotCreateOrUpdateHotel).WithException(err).WithData(orderId).
+			Error("error wile updating a reconciled order")
+		return
+	}
+	if mismatch != "" {
+		logger.WithName(logtags.OrderReconciliationMismatch).WithData(event).
+			Warn("order does not match its supplier")
+	}
+	logger.WithName(logtags.OrderReconciled).WithData(event).Info("order is changed by the reconciliation")
+	g.orderEventDispatcher.OrderReconciled(event)
+}
+
+//paymentMismatches compares the payment and the refunds of the order with the enquiry of the supplier
+func paymentMismatches(order *dbmodel.Order, enquiry dto.OrderEnquiryResponseDto) []string {
+	mismatches := make([]string, 0)
+	var paid int64
+	refunded := false
+	for _, item := range enquiry.Items {
+		for _, option := range item.Items {
+			paid += option.PaidAmount
+			refunded = refunded || option.RefundStatus == common.RefundStatus_PaymentFinalized
+		}
+	}
+	if paid == 0 {
+		mismatches = append(mismatches, "order is paid but the supplier has no payment")
+	}
+	if order.TransactionStatus == common.TransactionStatus_Pending {
+		mismatches = append(mismatches, "payment of the order is pending")
+	}
+	if state := order.CurrentState(); refunded && state != common.OrderState_RefundRequested &&
+		state != common.OrderState_Refunded {
+		mismatches = append(mismatches, "order is refunded by the supplier but no refund is requested")
+	}
+	return mismatches
+}
@@ -0,0 +1,167 @@
+package logic
+
+import (
+	"context"
+	"hotel-engine/core"
+	"hotel-engine/core/common"
+	"hotel-engine/core/common/logtags"
+	"hotel-engine/core/dbmodel"
+	"hotel-engine/core/dto"
+	"hotel-engine/infrastructure/logger"
+	"hotel-engine/utils/array"
+	"strconv"
+	"time"
+)
+
+//updateSupplierRefundsStatus pulls the status of the pending refund requests of the supplier, the supplier returns
+//every refund request of the queried orders so each request is matched and finalized on its own
+func (g *hotelService) updateSupplierRefundsStatus(ctx context.Context, provider core.HotelProvider, fromDate time.Time,
+	supplier string) {
+	refunds, err := g.unitOfWork.Order().GetPendingRefunds(fromDate, supplier)
+	if err != nil {
+		logger.WithName(logtags.GettingListOfRefundableOrdersError).
+			ErrorException(err, "error while trying to get list of pending refund requests for updating refund status")
+		return
+	}
+	ids := make([]string, 0)
+	refundsOfOrder := make(map[string][]*dbmodel.OrderRefund)
+	for i := range refunds {
+		if refunds[i].Order == nil {
+			continue
+		}
+		id := strconv.FormatInt(refunds[i].Order.IndraOrderId, 10)
+		if _, found := refundsOfOrder[id]; !found {
+			ids = append(ids, id)
+		}
+		refundsOfOrder[id] = append(refundsOfOrder[id], &refunds[i])
+	}
+	for _, orderIds := range array.Chunks(ids, g.syncChunkSize) {
+		size := 0
+		for _, id := range orderIds {
+			size += len(refundsOfOrder[id])
+		}
+		statuses, err := provider.GetOrdersRefundStatus(ctx, orderIds, size, 1)
+		if err != nil {
+			logger.WithName(logtags.GettingListOfRefundableOrdersError).WithData(orderIds).
+				ErrorException(err, "error while trying to get refund status of orders")
+			continue
+		}
+		for _, status := range statuses.Result.Items {
+			for _, refund := range refundsOfOrder[strconv.FormatInt(status.OrderId, 10)] {
+				if refund.RefundRequestId == status.RefundRequestId {
+					g.updateRefundStatus(ctx, provider, refund, status)
+				}
+			}
+		}
+	}
+}
+
+//updateRefundStatus stores the new status of a refund request, the applicant is notified when its refund is finalized.
+//the refunds of the compensated finalizations are not requested by the applicant
+func (g *hotelService) updateRefundStatus(ctx context.Context, provider core.HotelProvider, refund *dbmodel.OrderRefund,
+	status dto.OrdersRefundStatusResponseDtoResultItem) {
+	if status.RefundStatus == refund.RefundStatus {
+		return
+	}
+	var paid, refundable, penalty float32
+	for _, item := range status.Items {
+		paid += item.PaidAmount
+		refundable += item.RefundableAmount
+		penalty += item.TotalPenaltyAmount
+	}
+	refund.UpdateRefundResult(paid, status.RefundStatus, refundable, penalty)
+	if err := g.unitOfWork.Order().StoreRefund(*refund); err != nil {
+		logger.WithName(logtags.CannotCreateOrUpdateHotel).WithException(err).
+			Error("error wile updating a refund request status")
+		return
+	}
+	if !refund.Closed() {
+		return
+	}
+	g.finishOrderRefund(ctx, provider, refund)
+	if refund.Rejected() || refund.ApplicantRefundRequestId == 0 {
+		return
+	}
+	g.orderEventDispatcher.OrderRefundRequestFinalized(dto.OrderRefundRequestFinalizedDto{
+		ApplicantRefundRequestId: refund.ApplicantRefundRequestId,
+		ApplicantOrderId:         refund.ApplicantOrderId,
+		PaidAmount:               refund.PaidAmount,
+		RefundStatus:             refund.RefundStatus,
+		RefundableAmount:         refund.RefundableAmount,
+		TotalPenaltyAmount:       refund.TotalPenaltyAmount,
+		ProviderOrderId:          strconv.FormatInt(refund.Order.IndraOrderId, 10),
+	})
+}
+
+//finishOrderRefund moves the order out of refund requested once none of its refund requests is pending. the order is
+//refunded when its finalized refunds cover the whole order and is issued again otherwise, a rejected refund refunds
+//nothing
+func (g *hotelService) finishOrderRefund(ctx context.Context, provider core.HotelProvider, refund *dbmodel.OrderRefund) {
+	orderId := strconv.FormatInt(refund.Order.IndraOrderId, 10)
+	order, err := g.unitOfWork.Order().GetOneByIndraId(orderId)
+	if err != nil {
+		logger.WithName(logtags.GettingHotelDetailError).ErrorException(err, err.Error())
+		return
+	}
+	whole := false
+	refunded := make(map[string]bool)
+	for _, other := range order.Refunds {
+		if !other.Closed() {
+			return
+		}
+		if other.Rejected() {
+			continue
+		}
+		codes := other.Codes()
+		whole = whole || len(codes) == 0
+		for _, code := range codes {
+			refunded[code] = true
+		}
+	}
+	state := common.OrderState_Issued
+	if whole {
+		state = common.OrderState_Refunded
+	} else if len(refunded) > 0 {
+		covered, err := g.refundsCoverOrder(ctx, provider, order, refunded)
+		if err != nil {
+			logger.WithName(logtags.FinishingOrderRefundError).WithException(err).WithData(orderId).
+				Error("cannot check whether the refunds cover the whole order, it is left to the reconciliation")
+			return
+		}
+		if covered {
+			state = common.OrderState_Refunded
+		}
+	}
+	if err := transitOrder(order, state, "refund finalized"); err != nil {
+		logger.WithName(logtags.OrderTransitionRejected).WithException(err).WithData(orderId).
+			Warn("finalized refund is not allowed by the state of the order")
+		return
+	}
+	if err := g.unitOfWork.Order().StoreOrUpdate(*order); err != nil {
+		logger.WithName(logtags.CannotCreateOrUpdateHotel).WithException(err).
+			Error("error wile updating an order state")
+	}
+}
+
+//refundsCoverOrder reports whether every reference code of the order is refunded, the enquiry of the supplier lists
+//the reference codes of the order
+func (g *hotelService) refundsCoverOrder(ctx context.Context, provider core.HotelProvider, order *dbmodel.Order,
+	refunded map[string]bool) (bool, error) {
+	enquiry, err := provider.GetOrderEnquiry(ctx, strconv.FormatInt(order.IndraOrderId, 10), order.ProviderOrderId)
+	if err != nil {
+		return false, err
+	}
+	options, err := enquiryOptions(enquiry, nil)
+	if err != nil {
+		return false, err
+	}
+	if len(options) == 0 {
+		return false, nil
+	}
+	for _, option := range options {
+		if !refunded[option.ReferenceCode] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,338 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"hotel-engine/core/common"
+	"hotel-engine/core/common/logtags"
+	"hotel-engine/core/dbmodel"
+	"hotel-engine/core/dto"
+	"hotel-engine/infrastructure/logger"
+	"strconv"
+	"time"
+)
+
+type orderSagaStep struct {
+	name string
+	run  func() error
+}
+
+//sagaAbort is the error of a step that cannot complete by retrying it, the saga is compensated at once
+type sagaAbort struct {
+	err error
+}
+
+func (e sagaAbort) Error() string {
+	return e.err.Error()
+}
+
+//sagaStepError aborts the saga when the state of the order does not allow the step
+func sagaStepError(err error) error {
+	if _, ok := err.(common.OrderTransitionError); ok {
+		return sagaAbort{err: err}
+	}
+	return err
+}
+
+//FinalizeHotelOrder confirms, pays and gets the status of the order as a saga. a finalization that is stopped by an
+//error or a crash is resumed from the last completed step and a finalization that cannot complete is compensated
+func (g *hotelService) FinalizeHotelOrder(ctx context.Context, request dto.FinalizeOrderDto) (*dto.FinalizeOrderResponseDto, error) {
+	order, err := g.unitOfWork.Order().GetOneByIndraId(request.OrderId)
+	if err != nil {
+		return nil, err
+	}
+	return g.lockOrderSaga(request.OrderId, func() (*dto.FinalizeOrderResponseDto, error) {
+		saga, err := g.unitOfWork.OrderSaga().GetByOrderId(order.ID)
+		if err == common.OrderSagaNotFound {
+			saga = &dbmodel.OrderSaga{
+				OrderID:      order.ID,
+				IndraOrderId: order.IndraOrderId,
+				Status:       common.OrderSagaStatus_Running,
+			}
+			err = g.unitOfWork.OrderSaga().StoreOrUpdate(saga)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return g.runOrderSaga(ctx, request.OrderId, saga)
+	})
+}
+
+//lockOrderSaga runs the finalization of the order on one instance at a time so the supplier is never asked to confirm
+//or pay an order twice at once, a finalization that is running elsewhere is not run
+func (g *hotelService) lockOrderSaga(orderId string,
+	run func() (*dto.FinalizeOrderResponseDto, error)) (*dto.FinalizeOrderResponseDto, error) {
+	var res *dto.FinalizeOrderResponseDto
+	var err error
+	locked := false
+	lockErr := g.locker.Lock(g.sagaLockKey+orderId, g.sagaStaleAfter, func() {
+		locked = true
+		res, err = run()
+	})
+	if lockErr != nil {
+		return nil, lockErr
+	}
+	if !locked {
+		return nil, common.OrderSagaInProgress
+	}
+	return res, err
+}
+
+func (g *hotelService) runOrderSaga(ctx context.Context, orderId string, saga *dbmodel.OrderSaga) (*dto.FinalizeOrderResponseDto, error) {
+	switch saga.Status {
+	case common.OrderSagaStatus_Compensated:
+		return nil, common.OrderSagaCompensated
+	case common.OrderSagaStatus_CompensationFailed, common.OrderSagaStatus_NeedsSupport:
+		return nil, common.OrderSagaNeedsSupport
+	}
+	var payResult dto.OrderPayByAccountResponseDto
+	var statusResult dto.OrderStatusResponseDto
+	steps := []orderSagaStep{
+		{name: common.OrderSagaStep_Confirm, run: func() error {
+			_, err := g.ConfirmOrder(ctx, orderId)
+			return sagaStepError(err)
+		}},
+		{name: common.OrderSagaStep_Pay, run: func() (err error) {
+			if payResult, err = g.PayByAccount(ctx, orderId); err != nil {
+				return sagaStepError(err)
+			}
+			if payResult.TransactionStatus != common.TransactionStatus_Pending {
+				return nil
+			}
+			return g.checkPendingPayment(ctx, orderId)
+		}},
+		{name: common.OrderSagaStep_Status, run: func() (err error) {
+			statusResult, err = g.GetOrderStatus(ctx, orderId)
+			if err != nil {
+				return sagaStepError(err)
+			}
+			if state := supplierOrderStates[statusResult.Status]; state == common.OrderState_Failed ||
+				state == common.OrderState_Expired {
+				return sagaAbort{err: fmt.Errorf("supplier status of the order is %s", statusResult.Status)}
+			}
+			return nil
+		}},
+	}
+	for _, step := range steps {
+		if saga.Passed(step.name) {
+			continue
+		}
+		if err := step.run(); err != nil {
+			return nil, g.failOrderSaga(ctx, orderId, saga, step.name, err)
+		}
+		saga.CompleteStep(step.name)
+		if err := g.unitOfWork.OrderSaga().StoreOrUpdate(saga); err != nil {
+			return nil, err
+		}
+	}
+	if saga.Status != common.OrderSagaStatus_Completed {
+		saga.UpdateStatus(common.OrderSagaStatus_Completed)
+		if err := g.unitOfWork.OrderSaga().StoreOrUpdate(saga); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	if payResult.RequestId == "" {
+		if payResult, err = g.PayByAccount(ctx, orderId); err != nil {
+			return nil, err
+		}
+	}
+	if statusResult.Status == "" {
+		if statusResult, err = g.GetOrderStatus(ctx, orderId); err != nil {
+			return nil, err
+		}
+	}
+	successRes := &dto.FinalizeOrderResponseDto{
+		PaymentResult: &payResult,
+		StatusResult:  &statusResult,
+		Error:         nil,
+	}
+	logger.WithName(logtags.OrderFinalizedCompleted).WithData(successRes).
+		Info("order finalized successfully")
+	return successRes, nil
+}
+
+//checkPendingPayment follows the supplier status of an order whose payment is pending, the payment is completed once
+//the supplier issues the order
+func (g *hotelService) checkPendingPayment(ctx context.Context, orderId string) error {
+	status, err := g.GetOrderStatus(ctx, orderId)
+	if err != nil {
+		return err
+	}
+	order, err := g.unitOfWork.Order().GetOneByIndraId(orderId)
+	if err != nil {
+		return err
+	}
+	switch order.CurrentState() {
+	case common.OrderState_PaymentPending:
+		return common.OrderPaymentPending
+	case common.OrderState_Failed, common.OrderState_Expired:
+		return sagaAbort{err: fmt.Errorf("payment is pending and the supplier status of the order is %s", status.Status)}
+	}
+	return nil
+}
+
+//failOrderSaga records the failed step, the saga is compensated when the step is aborted or has no attempt left. a
+//paid order is only compensated when the supplier has failed it so a paid reservation is never refunded for an error
+//of reading its status, a paid saga that has no attempt left is left for the support
+func (g *hotelService) failOrderSaga(ctx context.Context, orderId string, saga *dbmodel.OrderSaga, step string,
+	err error) error {
+	saga.FailStep(step, err)
+	saga.Attempts++
+	logger.WithName(logtags.OrderSagaStepFailed).WithException(err).WithData(map[string]interface{}{
+		"orderId":  orderId,
+		"step":     step,
+		"attempts": saga.Attempts,
+	}).Warn("step of the order finalization failed")
+
+	_, abort := err.(sagaAbort)
+	if abort {
+		return g.compensateOrderSaga(ctx, orderId, saga)
+	}
+	if saga.Attempts < g.sagaMaxAttempts {
+		if storeErr := g.unitOfWork.OrderSaga().StoreOrUpdate(saga); storeErr != nil {
+			return storeErr
+		}
+		return err
+	}
+	if !saga.Passed(common.OrderSagaStep_Pay) {
+		return g.compensateOrderSaga(ctx, orderId, saga)
+	}
+	saga.UpdateStatus(common.OrderSagaStatus_NeedsSupport)
+	logger.WithName(logtags.OrderSagaNeedsSupport).WithException(err).WithData(orderId).
+		Error("paid order finalization has no attempt left")
+	if storeErr := g.unitOfWork.OrderSaga().StoreOrUpdate(saga); storeErr != nil {
+		return storeErr
+	}
+	return common.OrderSagaNeedsSupport
+}
+
+func (g *hotelService) compensateOrderSaga(ctx context.Context, orderId string, saga *dbmodel.OrderSaga) error {
+	step, err := g.compensateOrder(ctx, orderId, saga.Passed(common.OrderSagaStep_Pay),
+		saga.Tried(common.OrderSagaStep_Pay))
+	if err != nil {
+		saga.FailStep(step, err)
+		saga.UpdateStatus(common.OrderSagaStatus_CompensationFailed)
+		logger.WithName(logtags.OrderSagaCompensationFailed).WithException(err).WithData(orderId).
+			Error("order finalization cannot be compensated")
+		if err := g.unitOfWork.OrderSaga().StoreOrUpdate(saga); err != nil {
+			return err
+		}
+		return common.OrderSagaNeedsSupport
+	}
+	saga.CompleteStep(step)
+	saga.UpdateStatus(common.OrderSagaStatus_Compensated)
+	logger.WithName(logtags.OrderSagaCompensated).WithData(map[string]interface{}{
+		"orderId": orderId,
+		"step":    step,
+	}).Warn("order finalization is compensated")
+	if err := g.unitOfWork.OrderSaga().StoreOrUpdate(saga); err != nil {
+		return err
+	}
+	return common.OrderSagaCompensated
+}
+
+//compensateOrder refunds a paid order and releases the reservation of an unpaid order. the supplier has no cancel so a
+//released reservation fails here and expires at the supplier. an order is only released when it is known to be unpaid,
+//an order that the supplier has issued or paid and an order whose payment has no result are left to the support
+func (g *hotelService) compensateOrder(ctx context.Context, orderId string, paid, payTried bool) (string, error) {
+	order, err := g.unitOfWork.Order().GetOneByIndraId(orderId)
+	if err != nil {
+		return common.OrderSagaStep_Release, err
+	}
+	provider, err := g.providers.Get(order.Supplier)
+	if err != nil {
+		return common.OrderSagaStep_Release, err
+	}
+	if !paid {
+		if order.TransactionRequestId != "" {
+			return common.OrderSagaStep_Release, common.OrderPaymentUnknown
+		}
+		status, err := provider.GetOrderStatus(ctx, orderId)
+		if err != nil {
+			return common.OrderSagaStep_Release, err
+		}
+		if supplierOrderStates[status.Status] == common.OrderState_Issued {
+			return common.OrderSagaStep_Release, common.OrderIssuedWithoutPayment
+		}
+		if payTried {
+			enquiry, err := provider.GetOrderEnquiry(ctx, orderId, order.ProviderOrderId)
+			if err != nil {
+				return common.OrderSagaStep_Release, err
+			}
+			if options, _ := enquiryOptions(enquiry, nil); enquiryTotals(options).paid > 0 {
+				return common.OrderSagaStep_Release, common.OrderPaymentUnknown
+			}
+		}
+		if state := order.CurrentState(); state == common.OrderState_Failed || state == common.OrderState_Expired {
+			return common.OrderSagaStep_Release, nil
+		}
+		if err := transitOrder(order, common.OrderState_Failed, "finalization compensated"); err != nil {
+			return common.OrderSagaStep_Release, err
+		}
+		return common.OrderSagaStep_Release, g.unitOfWork.Order().StoreOrUpdate(*order)
+	}
+
+	if order.RefundRequestId != 0 || len(order.Refunds) > 0 {
+		return common.OrderSagaStep_Refund, nil
+	}
+	res, err := provider.RefundOrder(ctx, orderId, []string{order.ProviderOrderId})
+	if err != nil {
+		return common.OrderSagaStep_Refund, err
+	}
+	order.Refunds = append(order.Refunds, dbmodel.OrderRefund{
+		OrderID:         order.ID,
+		RefundRequestId: res.RefundRequestId,
+	})
+	if err := transitOrder(order, common.OrderState_RefundRequested, "finalization compensated"); err != nil {
+		logger.WithName(logtags.OrderTransitionRejected).WithException(err).WithData(orderId).
+			Warn("refund of the finalization is not allowed by the state of the order")
+	}
+	return common.OrderSagaStep_Refund, g.unitOfWork.Order().StoreOrUpdate(*order)
+}
+
+//ResumeOrderSagas runs again the finalizations that are stopped by a crash or an error, a finalization is only resumed
+//when it is not changed for a while so a running finalization is not run twice
+func (g *hotelService) ResumeOrderSagas(ctx context.Context) {
+	sagas, err := g.unitOfWork.OrderSaga().GetStuck(time.Now().Add(-g.sagaStaleAfter))
+	if err != nil {
+		logger.WithName(logtags.OrderSagaResumeError).
+			ErrorException(err, "error while trying to get list of stuck order finalizations")
+		return
+	}
+	for i := range sagas {
+		if sagas[i].Status != common.OrderSagaStatus_Running {
+			continue
+		}
+		orderId := strconv.FormatInt(sagas[i].IndraOrderId, 10)
+		_, err := g.lockOrderSaga(orderId, func() (*dto.FinalizeOrderResponseDto, error) {
+			saga, err := g.unitOfWork.OrderSaga().GetByOrderId(sagas[i].OrderID)
+			if err != nil {
+				return nil, err
+			}
+			if saga.Status != common.OrderSagaStatus_Running || saga.UpdatedAt.After(sagas[i].UpdatedAt) {
+				return nil, nil
+			}
+			return g.runOrderSaga(ctx, orderId, saga)
+		})
+		if err != nil {
+			logger.WithName(logtags.OrderSagaResumeError).WithException(err).WithData(orderId).
+				Warn("resumed order finalization is not completed")
+		}
+	}
+}
+
+//GetStuckOrderSagas returns the finalizations that are not changed for a while and the ones that could not be
+//compensated
+func (g *hotelService) GetStuckOrderSagas(ctx context.Context) ([]dto.OrderSagaDto, error) {
+	sagas, err := g.unitOfWork.OrderSaga().GetStuck(time.Now().Add(-g.sagaStaleAfter))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dto.OrderSagaDto, 0, len(sagas))
+	for _, saga := range sagas {
+		result = append(result, g.mapper.ToOrderSagaDto(saga))
+	}
+	return result, nil
+}
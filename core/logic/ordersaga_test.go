@@ -0,0 +1,273 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"hotel-engine/core"
+	"hotel-engine/core/common"
+	"hotel-engine/core/dbmodel"
+	"hotel-engine/core/dto"
+	"testing"
+	"time"
+)
+
+type sagaUnitOfWork struct {
+	core.UnitOfWork
+	orders *sagaOrders
+	sagas  *sagaRepository
+}
+
+func (u sagaUnitOfWork) Order() core.OrderRepository {
+	return u.orders
+}
+
+func (u sagaUnitOfWork) OrderSaga() core.OrderSagaRepository {
+	return u.sagas
+}
+
+type sagaOrders struct {
+	core.OrderRepository
+	order dbmodel.Order
+}
+
+func (r *sagaOrders) GetOneByIndraId(string) (*dbmodel.Order, error) {
+	order := r.order
+	return &order, nil
+}
+
+func (r *sagaOrders) StoreOrUpdate(order dbmodel.Order) error {
+	r.order = order
+	return nil
+}
+
+type sagaRepository struct {
+	saga *dbmodel.OrderSaga
+}
+
+func (r *sagaRepository) GetByOrderId(uint) (*dbmodel.OrderSaga, error) {
+	if r.saga == nil {
+		return nil, common.OrderSagaNotFound
+	}
+	saga := *r.saga
+	saga.Steps = append([]dbmodel.OrderSagaStep{}, r.saga.Steps...)
+	return &saga, nil
+}
+
+func (r *sagaRepository) StoreOrUpdate(saga *dbmodel.OrderSaga) error {
+	stored := *saga
+	stored.Steps = append([]dbmodel.OrderSagaStep{}, saga.Steps...)
+	r.saga = &stored
+	return nil
+}
+
+func (r *sagaRepository) GetStuck(time.Time) ([]dbmodel.OrderSaga, error) {
+	if r.saga == nil {
+		return nil, nil
+	}
+	saga, _ := r.GetByOrderId(r.saga.OrderID)
+	return []dbmodel.OrderSaga{*saga}, nil
+}
+
+type sagaProvider struct {
+	core.HotelProvider
+	payErr error
+	//statusErrs is the number of status requests that fail before the supplier returns the status
+	statusErrs int
+	status     string
+	paidAmount int64
+	payments   int
+	refunds    int
+}
+
+func (p *sagaProvider) ConfirmOrder(_ context.Context, orderId string) (dto.ConfirmResponseDto, error) {
+	return dto.ConfirmResponseDto{OrderId: orderId}, nil
+}
+
+func (p *sagaProvider) PayByAccount(context.Context, string) (dto.OrderPayByAccountResponseDto, error) {
+	p.payments++
+	if p.payErr != nil {
+		return dto.OrderPayByAccountResponseDto{}, p.payErr
+	}
+	return dto.OrderPayByAccountResponseDto{RequestId: "1", TransactionStatus: "Succeeded"}, nil
+}
+
+func (p *sagaProvider) GetOrderStatus(context.Context, string) (dto.OrderStatusResponseDto, error) {
+	if p.statusErrs > 0 {
+		p.statusErrs--
+		return dto.OrderStatusResponseDto{}, errors.New("status timeout")
+	}
+	return dto.OrderStatusResponseDto{Status: p.status}, nil
+}
+
+func (p *sagaProvider) GetOrderEnquiry(context.Context, string, string) (dto.OrderEnquiryResponseDto, error) {
+	return dto.OrderEnquiryResponseDto{Items: []dto.OrderEnquiryItemDto{{
+		Items: []dto.OrderEnquiryItemOptionDto{{ReferenceCode: "A", PaidAmount: p.paidAmount}},
+	}}}, nil
+}
+
+func (p *sagaProvider) RefundOrder(_ context.Context, orderId string, _ []string) (dto.OrderRefundResponseDto, error) {
+	p.refunds++
+	return dto.OrderRefundResponseDto{OrderId: orderId, RefundRequestId: 1}, nil
+}
+
+type runningLocker struct{}
+
+func (runningLocker) Lock(_ string, _ time.Duration, toDo func()) error {
+	toDo()
+	return nil
+}
+
+type idleBalanceChecker struct{}
+
+func (idleBalanceChecker) GetBalance() (float64, error) {
+	return 0, nil
+}
+
+func (idleBalanceChecker) CheckAdequateBalance() {}
+
+func newSagaService(provider *sagaProvider) (*hotelService, *sagaOrders, *sagaRepository) {
+	orders := &sagaOrders{order: dbmodel.Order{IndraOrderId: 1, Supplier: "supplier", State: common.OrderState_Held}}
+	orders.order.ID = 1
+	sagas := &sagaRepository{}
+	providers := NewHotelProviderRegistry("supplier")
+	providers.Register("supplier", provider)
+	return &hotelService{
+		unitOfWork:      sagaUnitOfWork{orders: orders, sagas: sagas},
+		providers:       providers,
+		balanceChecker:  idleBalanceChecker{},
+		locker:          runningLocker{},
+		sagaMaxAttempts: 2,
+	}, orders, sagas
+}
+
+func TestFinalizeHotelOrder(t *testing.T) {
+	tests := []struct {
+		name         string
+		provider     sagaProvider
+		runs         int
+		wantErr      error
+		wantStatus   string
+		wantState    string
+		wantPayments int
+		wantRefunds  int
+	}{
+		{
+			name:         "issued order",
+			provider:     sagaProvider{status: "Issued"},
+			runs:         1,
+			wantStatus:   common.OrderSagaStatus_Completed,
+			wantState:    common.OrderState_Issued,
+			wantPayments: 1,
+		},
+		{
+			name:         "resumed from the last completed step",
+			provider:     sagaProvider{status: "Issued", statusErrs: 1},
+			runs:         2,
+			wantStatus:   common.OrderSagaStatus_Completed,
+			wantState:    common.OrderState_Issued,
+			wantPayments: 1,
+		},
+		{
+			name:         "completed saga is not run again",
+			provider:     sagaProvider{status: "Issued"},
+			runs:         3,
+			wantStatus:   common.OrderSagaStatus_Completed,
+			wantState:    common.OrderState_Issued,
+			wantPayments: 1,
+		},
+		{
+			name:         "unpaid order is released",
+			provider:     sagaProvider{status: "Confirmed", payErr: errors.New("payment timeout")},
+			runs:         2,
+			wantErr:      common.OrderSagaCompensated,
+			wantStatus:   common.OrderSagaStatus_Compensated,
+			wantState:    common.OrderState_Failed,
+			wantPayments: 2,
+		},
+		{
+			name:         "order of an unknown payment needs the support",
+			provider:     sagaProvider{status: "Confirmed", payErr: errors.New("payment timeout"), paidAmount: 1000},
+			runs:         2,
+			wantErr:      common.OrderSagaNeedsSupport,
+			wantStatus:   common.OrderSagaStatus_CompensationFailed,
+			wantState:    common.OrderState_Confirmed,
+			wantPayments: 2,
+		},
+		{
+			name:         "paid order failed by the supplier is refunded",
+			provider:     sagaProvider{status: "Failed"},
+			runs:         1,
+			wantErr:      common.OrderSagaCompensated,
+			wantStatus:   common.OrderSagaStatus_Compensated,
+			wantState:    common.OrderState_Failed,
+			wantPayments: 1,
+			wantRefunds:  1,
+		},
+		{
+			name:         "paid order without attempts needs the support",
+			provider:     sagaProvider{status: "Issued", statusErrs: 10},
+			runs:         2,
+			wantErr:      common.OrderSagaNeedsSupport,
+			wantStatus:   common.OrderSagaStatus_NeedsSupport,
+			wantState:    common.OrderState_Paid,
+			wantPayments: 1,
+		},
+		{
+			name:         "saga left for the support is not run again",
+			provider:     sagaProvider{status: "Issued", statusErrs: 10},
+			runs:         3,
+			wantErr:      common.OrderSagaNeedsSupport,
+			wantStatus:   common.OrderSagaStatus_NeedsSupport,
+			wantState:    common.OrderState_Paid,
+			wantPayments: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := tt.provider
+			service, orders, sagas := newSagaService(&provider)
+			var err error
+			for i := 0; i < tt.runs; i++ {
+				_, err = service.FinalizeHotelOrder(context.Background(), dto.FinalizeOrderDto{OrderId: "1"})
+			}
+			if err != tt.wantErr {
+				t.Errorf("FinalizeHotelOrder() error = %v, want %v", err, tt.wantErr)
+			}
+			if sagas.saga.Status != tt.wantStatus {
+				t.Errorf("saga status = %v, want %v", sagas.saga.Status, tt.wantStatus)
+			}
+			if state := orders.order.CurrentState(); state != tt.wantState {
+				t.Errorf("order state = %v, want %v", state, tt.wantState)
+			}
+			if provider.payments != tt.wantPayments {
+				t.Errorf("payments = %v, want %v", provider.payments, tt.wantPayments)
+			}
+			if provider.refunds != tt.wantRefunds {
+				t.Errorf("refunds = %v, want %v", provider.refunds, tt.wantRefunds)
+			}
+		})
+	}
+}
+
+func TestResumeOrderSagas(t *testing.T) {
+	provider := &sagaProvider{status: "Issued", statusErrs: 1}
+	service, orders, sagas := newSagaService(provider)
+	if _, err := service.FinalizeHotelOrder(context.Background(), dto.FinalizeOrderDto{OrderId: "1"}); err == nil {
+		t.Fatal("FinalizeHotelOrder() error = nil, want the status error")
+	}
+	if sagas.saga.Status != common.OrderSagaStatus_Running || !sagas.saga.Passed(common.OrderSagaStep_Pay) {
+		t.Fatalf("saga = %+v, want a running saga after the payment", sagas.saga)
+	}
+
+	service.ResumeOrderSagas(context.Background())
+
+	if sagas.saga.Status != common.OrderSagaStatus_Completed {
+		t.Errorf("saga status = %v, want %v", sagas.saga.Status, common.OrderSagaStatus_Completed)
+	}
+	if state := orders.order.CurrentState(); state != common.OrderState_Issued {
+		t.Errorf("order state = %v, want %v", state, common.OrderState_Issued)
+	}
+	if provider.payments != 1 {
+		t.Errorf("payments = %v, want 1", provider.payments)
+	}
+}
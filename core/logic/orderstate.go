@@ -0,0 +1,74 @@
+package logic
+
+import (
+	"hotel-engine/core/common"
+	"hotel-engine/core/common/logtags"
+	"hotel-engine/core/dbmodel"
+	"hotel-engine/infrastructure/logger"
+)
+
+//orderTransitions are the states that an order can go to from each state, refunded, failed and expired orders are
+//final. a payment that the supplier has not completed keeps the order payment pending until it is paid or issued, a
+//partially refunded order goes back to issued when its refund is finalized
+var orderTransitions = map[string][]string{
+	common.OrderState_Draft:           {common.OrderState_Held, common.OrderState_Failed},
+	common.OrderState_Held:            {common.OrderState_Confirmed, common.OrderState_Failed, common.OrderState_Expired},
+	common.OrderState_Confirmed:       {common.OrderState_Paid, common.OrderState_PaymentPending, common.OrderState_Failed, common.OrderState_Expired},
+	common.OrderState_PaymentPending:  {common.OrderState_Paid, common.OrderState_Issued, common.OrderState_Failed, common.OrderState_Expired},
+	common.OrderState_Paid:            {common.OrderState_Issued, common.OrderState_RefundRequested, common.OrderState_Failed},
+	common.OrderState_Issued:          {common.OrderState_RefundRequested},
+	common.OrderState_RefundRequested: {common.OrderState_Refunded, common.OrderState_Issued},
+}
+
+//supplierOrderStates maps the order statuses of the supplier to the states they move the order to
+var supplierOrderStates = map[string]string{
+	"Confirmed":       common.OrderState_Confirmed,
+	"Issued":          common.OrderState_Issued,
+	"RefundRequested": common.OrderState_RefundRequested,
+	"Refunded":        common.OrderState_Refunded,
+	"Expired":         common.OrderState_Expired,
+	"Failed":          common.OrderState_Failed,
+	"Canceled":        common.OrderState_Failed,
+	"Cancelled":       common.OrderState_Failed,
+}
+
+//checkTransition returns the error of moving the order to the state without moving it
+func checkTransition(order *dbmodel.Order, state string) error {
+	from := order.CurrentState()
+	if from == state {
+		return nil
+	}
+	for _, to := range orderTransitions[from] {
+		if to == state {
+			return nil
+		}
+	}
+	return common.OrderTransitionError{From: from, To: state}
+}
+
+//transitOrder moves the order to the state when its current state allows it, staying in the same state is allowed.
+//the order is not stored so a failed action after the transition leaves the stored order as it was
+func transitOrder(order *dbmodel.Order, state, event string) error {
+	if err := checkTransition(order, state); err != nil {
+		return err
+	}
+	if order.CurrentState() != state {
+		order.UpdateState(state, event)
+	}
+	return nil
+}
+
+//followSupplierStatus moves the order to the state of its supplier status, a status that the state of the order does
+//not allow is logged and the order keeps its state
+func followSupplierStatus(order *dbmodel.Order, status string) {
+	state, found := supplierOrderStates[status]
+	if !found {
+		return
+	}
+	if err := transitOrder(order, state, "supplier status "+status); err != nil {
+		logger.WithName(logtags.OrderTransitionRejected).WithException(err).WithData(map[string]interface{}{
+			"orderId": order.IndraOrderId,
+			"status":  status,
+		}).Warn("supplier status of the order is not allowed by its state")
+	}
+}
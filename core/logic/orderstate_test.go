@@ -0,0 +1,108 @@
+package logic
+
+import (
+	"hotel-engine/core/common"
+	"hotel-engine/core/dbmodel"
+	"testing"
+)
+
+func Test_transitOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		order   dbmodel.Order
+		state   string
+		wantErr error
+	}{
+		{
+			name:  "held to confirmed",
+			order: dbmodel.Order{State: common.OrderState_Held},
+			state: common.OrderState_Confirmed,
+		},
+		{
+			name:  "confirmed to payment pending",
+			order: dbmodel.Order{State: common.OrderState_Confirmed},
+			state: common.OrderState_PaymentPending,
+		},
+		{
+			name:  "payment pending to issued",
+			order: dbmodel.Order{State: common.OrderState_PaymentPending},
+			state: common.OrderState_Issued,
+		},
+		{
+			name:  "paid to refund requested",
+			order: dbmodel.Order{State: common.OrderState_Paid},
+			state: common.OrderState_RefundRequested,
+		},
+		{
+			name:  "partially refunded back to issued",
+			order: dbmodel.Order{State: common.OrderState_RefundRequested},
+			state: common.OrderState_Issued,
+		},
+		{
+			name:  "same state",
+			order: dbmodel.Order{State: common.OrderState_Issued},
+			state: common.OrderState_Issued,
+		},
+		{
+			name:  "legacy confirmed order to paid",
+			order: dbmodel.Order{Confirmed: true},
+			state: common.OrderState_Paid,
+		},
+		{
+			name:    "held to paid",
+			order:   dbmodel.Order{State: common.OrderState_Held},
+			state:   common.OrderState_Paid,
+			wantErr: common.OrderTransitionError{From: common.OrderState_Held, To: common.OrderState_Paid},
+		},
+		{
+			name:    "issued to failed",
+			order:   dbmodel.Order{State: common.OrderState_Issued},
+			state:   common.OrderState_Failed,
+			wantErr: common.OrderTransitionError{From: common.OrderState_Issued, To: common.OrderState_Failed},
+		},
+		{
+			name:    "refunded is final",
+			order:   dbmodel.Order{State: common.OrderState_Refunded},
+			state:   common.OrderState_Issued,
+			wantErr: common.OrderTransitionError{From: common.OrderState_Refunded, To: common.OrderState_Issued},
+		},
+		{
+			name:    "expired is final",
+			order:   dbmodel.Order{State: common.OrderState_Expired},
+			state:   common.OrderState_Confirmed,
+			wantErr: common.OrderTransitionError{From: common.OrderState_Expired, To: common.OrderState_Confirmed},
+		},
+		{
+			name:    "legacy issued order to failed",
+			order:   dbmodel.Order{TransactionRequestId: "1", Status: common.OrderState_Issued},
+			state:   common.OrderState_Failed,
+			wantErr: common.OrderTransitionError{From: common.OrderState_Issued, To: common.OrderState_Failed},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := tt.order
+			from := order.CurrentState()
+			err := transitOrder(&order, tt.state, "test")
+			if err != tt.wantErr {
+				t.Fatalf("transitOrder() error = %v, want %v", err, tt.wantErr)
+			}
+			wantState, wantHistory := tt.state, 1
+			if err != nil {
+				wantState, wantHistory = from, 0
+			}
+			if from == tt.state {
+				wantHistory = 0
+			}
+			if got := order.CurrentState(); got != wantState {
+				t.Errorf("CurrentState() = %v, want %v", got, wantState)
+			}
+			if len(order.StateHistory) != wantHistory {
+				t.Fatalf("StateHistory = %v, want %d transitions", order.StateHistory, wantHistory)
+			}
+			if wantHistory == 1 && (order.StateHistory[0].FromState != from || order.StateHistory[0].ToState != tt.state) {
+				t.Errorf("StateHistory = %+v, want %v to %v", order.StateHistory[0], from, tt.state)
+			}
+		})
+	}
+}
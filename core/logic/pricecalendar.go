@@ -0,0 +1,248 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"hotel-engine/core"
+	"hotel-engine/core/common"
+	"hotel-engine/core/common/logtags"
+	"hotel-engine/core/dbmodel"
+	"hotel-engine/core/dto"
+	hotelProviderInterface "hotel-engine/infrastructure/hotelproviderinterface"
+	"hotel-engine/infrastructure/logger"
+	"hotel-engine/utils/date"
+	"sync"
+	"time"
+)
+
+type calendarDay struct {
+	PricePerNight int64
+	Available     bool
+	Indicative    bool
+}
+
+//GetPriceCalendar prices a one night stay for each day of the range. the days are priced concurrently with a limit,
+//the days that could not be priced are returned as unavailable and the request fails only when none is priced
+func (g *hotelService) GetPriceCalendar(ctx context.Context, request dto.PriceCalendarRequestDto) (*dto.PriceCalendarResponseDto, error) {
+	from, err := date.StringToDate(request.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := date.StringToDate(request.To)
+	if err != nil {
+		return nil, err
+	}
+	if to.Before(from) {
+		return nil, common.DatesNotMatchError
+	}
+	count := int(to.Sub(from).Hours()/24) + 1
+	if count > g.calendarMaxDays {
+		return nil, common.PriceCalendarRangeTooLong
+	}
+	request.Supplier = g.hotelSupplier(request.Supplier)
+	provider, err := g.providers.Get(request.Supplier)
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]dto.PriceCalendarDayDto, count)
+	errs := make([]error, count)
+	slots := make(chan struct{}, g.calendarWorkers)
+	wg := sync.WaitGroup{}
+	for i := range days {
+		checkIn := from.Add(time.Hour * 24 * time.Duration(i))
+		days[i].Date = checkIn.Format(date.LayoutISO)
+		wg.Add(1)
+		go func(i int, checkIn time.Time) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			day, err := g.calendarDay(ctx, provider, request, checkIn)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			days[i].PricePerNight = day.PricePerNight
+			days[i].Available = day.Available
+			days[i].Indicative = day.Indicative
+		}(i, checkIn)
+	}
+	wg.Wait()
+
+	failed := 0
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		failed++
+		logger.WithName(logtags.PriceCalendarError).WithException(err).WithData(map[string]interface{}{
+			"hotelId": request.HotelId,
+			"date":    days[i].Date,
+		}).Warn("cannot price a day of the calendar")
+	}
+	if failed == count {
+		return nil, errs[0]
+	}
+	return &dto.PriceCalendarResponseDto{
+		HotelId: request.HotelId,
+		Days:    days,
+	}, nil
+}
+
+//calendarDay returns the cheapest price of a night. the default occupancy is priced like the sync but as a live request
+//with the deadline of the rooms, the synced price of the night is used when the supplier does not answer in time. a
+//synced price may be hours old so it is indicative and is not cached, the next request asks the supplier again. the
+//requested rooms are priced like the pdp and share its cache
+func (g *hotelService) calendarDay(ctx context.Context, provider core.HotelProvider, request dto.PriceCalendarRequestDto,
+	checkIn time.Time) (calendarDay, error) {
+	checkOut := checkIn.Add(time.Hour * 24)
+	key := roomCacheKey("calendar", request.Supplier, request.HotelId,
+		g.hotelCacheVersion(request.Supplier, request.HotelId), checkIn.Format(date.LayoutISO), occupancyKey(request.Rooms))
+	var day calendarDay
+	if g.responseCache.Get(key, &day) {
+		return day, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return day, err
+	}
+	if len(request.Rooms) == 0 {
+		liveCtx, cancel := context.WithTimeout(hotelProviderInterface.AsLiveTraffic(ctx), g.calendarTimeout)
+		defer cancel()
+		hotel, err := provider.GetHotelData(liveCtx, request.HotelId, checkIn, checkOut)
+		if err != nil {
+			if synced, found := g.syncedCalendarDay(request.HotelId, request.Supplier, checkIn); found {
+				synced.Indicative = true
+				return synced, nil
+			}
+			return day, err
+		}
+		day.PricePerNight = hotel.Price
+	} else {
+		rooms, err := g.cachedHotelRooms(ctx, dto.HotelRoomsDto{
+			HotelId:  request.HotelId,
+			CheckIn:  checkIn.Format(date.LayoutISO),
+			CheckOut: checkOut.Format(date.LayoutISO),
+			Rooms:    request.Rooms,
+			Supplier: request.Supplier,
+		})
+		if err != nil {
+			return day, err
+		}
+		for _, option := range rooms.Rooms {
+			if option.Price > 0 && (day.PricePerNight == 0 || option.Price < day.PricePerNight) {
+				day.PricePerNight = option.Price
+			}
+		}
+	}
+	day.Available = day.PricePerNight > 0
+	//an unavailable day may be a supplier that was not ready, it is kept as long as the rooms
+	ttl := g.calendarTtl
+	if !day.Available {
+		ttl = g.roomsCacheTtl
+	}
+	g.responseCache.Set(key, day, ttl)
+	return day, nil
+}
+
+//syncedCalendarDay returns the price of the night that is stored by the sync
+func (g *hotelService) syncedCalendarDay(hotelId, supplier string, checkIn time.Time) (calendarDay, bool) {
+	days, err := g.unitOfWork.HotelPrice().GetDays([]string{hotelId}, supplier, checkIn)
+	if err != nil {
+		return calendarDay{}, false
+	}
+	for _, day := range days {
+		if day.Date.Format(date.LayoutISO) == checkIn.Format(date.LayoutISO) {
+			return calendarDay{PricePerNight: day.Price, Available: day.Available}, true
+		}
+	}
+	return calendarDay{}, false
+}
+
+//SyncHotelCalendars stores the prices of the upcoming nights of the hotels for the elastic calendar. it is a pass of
+//its own so updating a hotel stays a single supplier call, the hotels are priced with a concurrency limit and a rate of
+//their own. the hotels with the oldest calendar go first and a run ends with its context, so a catalogue that cannot
+//be priced in one run is priced over the next runs
+func (g *hotelService) SyncHotelCalendars(ctx context.Context) {
+	hotels, err := g.unitOfWork.Hotel().GetHotelsForCalendar()
+	if err != nil {
+		logger.WithName(logtags.SyncHotelCalendarError).
+			ErrorException(err, "error while trying to get list of hotels for the calendar")
+		return
+	}
+	start := time.Now()
+	slots := make(chan struct{}, g.syncCalendarWorkers)
+	wg := sync.WaitGroup{}
+	synced := 0
+	for i := range hotels {
+		if ctx.Err() != nil {
+			break
+		}
+		synced++
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(hotel dbmodel.Hotel) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			provider, err := g.providers.Get(hotel.Supplier)
+			if err != nil {
+				logger.WithName(logtags.SyncHotelCalendarError).WithException(err).WithData(hotel.PlaceID).
+					Error("problem while getting hotel supplier")
+				return
+			}
+			g.syncHotelCalendar(ctx, provider, hotel, start)
+		}(hotels[i])
+	}
+	wg.Wait()
+	logger.WithName(logtags.SyncingHotelCalendarsCompleted).
+		WithData(fmt.Sprintf("syncing %d of %d hotel calendars completed in %d nanoseconds", synced, len(hotels),
+			time.Since(start))).
+		Info("syncing hotel calendars completed")
+}
+
+//syncHotelCalendar stores the prices of the upcoming nights of the hotel, the first night is the one that is already
+//synced with the hotel. the days that cannot be priced keep their last synced price, a hotel whose run has ended
+//before all of its nights are asked keeps its place at the front of the next run
+func (g *hotelService) syncHotelCalendar(ctx context.Context, provider core.HotelProvider, hotel dbmodel.Hotel,
+	start time.Time) {
+	year, month, day := start.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	days := make([]dbmodel.HotelPrice, 0, g.syncCalendarDays)
+	days = append(days, dbmodel.HotelPrice{
+		Date:      today,
+		Price:     hotel.Price,
+		OldPrice:  hotel.OldPrice,
+		Available: hotel.Price > 0 && hotel.RoomID != "0",
+	})
+	for i := 1; i < g.syncCalendarDays; i++ {
+		checkIn := start.Add(time.Hour * 24 * time.Duration(i))
+		if err := g.syncCalendarLimiter.Wait(ctx); err != nil {
+			break
+		}
+		night, err := provider.GetHotelData(ctx, hotel.PlaceID, checkIn, checkIn.Add(time.Hour*24))
+		if err != nil {
+			logger.WithName(logtags.SyncHotelCalendarError).WithException(err).WithData(map[string]interface{}{
+				"hotelId": hotel.PlaceID,
+				"date":    checkIn.Format(date.LayoutISO),
+			}).Warn("cannot price a night of the hotel calendar")
+			continue
+		}
+		days = append(days, dbmodel.HotelPrice{
+			Date:      today.Add(time.Hour * 24 * time.Duration(i)),
+			Price:     night.Price,
+			OldPrice:  night.OldPrice,
+			Available: night.Price > 0 && night.RoomID != "0",
+		})
+	}
+	if err := g.unitOfWork.HotelPrice().StoreDays(hotel.PlaceID, hotel.Supplier, today, days); err != nil {
+		logger.WithName(logtags.SyncHotelCalendarError).WithException(err).WithData(hotel.PlaceID).
+			Error("cannot store the hotel calendar")
+		return
+	}
+	if ctx.Err() != nil {
+		return
+	}
+	if err := g.unitOfWork.Hotel().SetCalendarSynced(hotel.ID, start); err != nil {
+		logger.WithName(logtags.SyncHotelCalendarError).WithException(err).WithData(hotel.PlaceID).
+			Error("cannot store the sync time of the hotel calendar")
+	}
+}
@@ -48,7 +48,7 @@ func (s *publicService) GetAllSorts() []dto.SortDto {
 }
 
 func (s *publicService) GetAllFilters() *dto.FiltersDto {
-	return dto.CreateDefaultFiltersDto(nil)
+	return dto.CreateDefaultFiltersDto(s.cacheStore.AmenityStore().GetAll())
 }
 
 func (s *publicService) SyncAllCities() ([]dto.CityDto, error) {
@@ -63,9 +63,20 @@ func (s *publicService) SyncAllCities() ([]dto.CityDto, error) {
 	}
 	err = s.unitOfWork.City().BulkInsert(s.mapper.ToCitiesModel(cities))
 	s.cacheStore.UpdateCityStore()
+	s.cacheStore.UpdateAutocompleteStore()
 	return cities, err
 }
 
+func (s *publicService) Autocomplete(query string, limit int) []dto.AutocompleteItemDto {
+	if limit <= 0 {
+		limit = common.DefaultAutocompleteSize
+	}
+	if limit > common.MaxAutocompleteSize {
+		limit = common.MaxAutocompleteSize
+	}
+	return s.cacheStore.AutocompleteStore().Search(query, limit)
+}
+
 func (s *publicService) GetAllPlaces() []dto.HotelPlaceDto {
 	places := s.unitOfWork.Place().GetAll()
 	return s.mapper.ToPlacesDto(places)
@@ -1,6 +1,7 @@
 package logic
 
 import (
+	"context"
 	"encoding/json"
 	"hotel-engine/core"
 	"hotel-engine/core/common/logtags"
@@ -25,7 +26,7 @@ func (r *RateReviewEventHandler) handleMessage(message dto.RateReviewEventDto) {
 	if message.ProductType != hotelProductType {
 		return
 	}
-	_ = r.hotelService.UpdateHotelRateReview(message)
+	_ = r.hotelService.UpdateHotelRateReview(context.Background(), message)
 }
 
 func (r *RateReviewEventHandler) subscribeCallback(d amqp.Delivery) {
@@ -42,17 +42,22 @@ func NewRedisLocker() core.DistributedLocker {
 	if con.IsDevelopment() {
 		return &fakeDevelopmentLocker{}
 	}
-	connectionDetails := strings.Split(con.MemoryStorageConnection, ",")
+	locker := redislock.New(newRedisClient(con.MemoryStorageConnection))
+	return &redisDistributedLock{
+		locker: locker,
+	}
+}
+
+//newRedisClient creates a client from a connection string like "address,password,db"
+func newRedisClient(connection string) *redis.Client {
+	connectionDetails := strings.Split(connection, ",")
 	db, err := strconv.Atoi(connectionDetails[2])
 	if err != nil {
 		db = 0
 	}
-	locker := redislock.New(redis.NewClient(&redis.Options{
+	return redis.NewClient(&redis.Options{
 		Addr:     connectionDetails[0],
 		Password: connectionDetails[1],
 		DB:       db,
-	}))
-	return &redisDistributedLock{
-		locker: locker,
-	}
+	})
 }
@@ -0,0 +1,280 @@
+package logic
+
+import (
+	"context"
+	"hotel-engine/core"
+	"hotel-engine/core/common"
+	"hotel-engine/core/common/logtags"
+	"hotel-engine/core/dbmodel"
+	"hotel-engine/core/dto"
+	"hotel-engine/infrastructure/logger"
+	"hotel-engine/utils/signedtoken"
+	"sort"
+	"time"
+)
+
+//refundQuote is what a refund preview promised, the refund is only sent while the supplier still agrees with it
+type refundQuote struct {
+	OrderId            string   `json:"orderId"`
+	ReferenceCodes     []string `json:"referenceCodes"`
+	TotalPenaltyAmount int64    `json:"totalPenaltyAmount"`
+	RefundableAmount   int64    `json:"refundableAmount"`
+	ExpiresAt          int64    `json:"expiresAt"`
+}
+
+type refundTotals struct {
+	paid       int64
+	penalty    int64
+	refundable int64
+}
+
+//enquiryOptions returns the rooms or passengers of the enquiry that are refunded, all of them when no reference code
+//is given
+func enquiryOptions(enquiry dto.OrderEnquiryResponseDto, referenceCodes []string) ([]dto.OrderEnquiryItemOptionDto, error) {
+	wanted := make(map[string]bool, len(referenceCodes))
+	for _, code := range referenceCodes {
+		wanted[code] = false
+	}
+	options := make([]dto.OrderEnquiryItemOptionDto, 0)
+	for _, item := range enquiry.Items {
+		for _, option := range item.Items {
+			if _, found := wanted[option.ReferenceCode]; found || len(referenceCodes) == 0 {
+				wanted[option.ReferenceCode] = true
+				options = append(options, option)
+			}
+		}
+	}
+	for _, code := range referenceCodes {
+		if !wanted[code] {
+			return nil, common.RefundReferenceCodeNotFound
+		}
+	}
+	return options, nil
+}
+
+func enquiryTotals(options []dto.OrderEnquiryItemOptionDto) refundTotals {
+	totals := refundTotals{}
+	for _, option := range options {
+		totals.paid += option.PaidAmount
+		totals.penalty += option.TotalPenaltyAmount
+		totals.refundable += option.RefundableAmount
+	}
+	return totals
+}
+
+//checkRefundable fails when the order or one of the reference codes is already in a refund request, a refund without
+//reference codes is the whole order
+func checkRefundable(order *dbmodel.Order, referenceCodes []string) error {
+	if order.RefundRequestId != 0 {
+		return common.RefundAlreadyRequested
+	}
+	requested := make(map[string]bool, len(referenceCodes))
+	for _, code := range referenceCodes {
+		requested[code] = true
+	}
+	for _, refund := range order.Refunds {
+		if refund.Rejected() {
+			continue
+		}
+		codes := refund.Codes()
+		if len(codes) == 0 || len(requested) == 0 {
+			return common.RefundAlreadyRequested
+		}
+		for _, code := range codes {
+			if requested[code] {
+				return common.RefundAlreadyRequested
+			}
+		}
+	}
+	return nil
+}
+
+func sameCodes(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	x := append([]string{}, a...)
+	y := append([]string{}, b...)
+	sort.Strings(x)
+	sort.Strings(y)
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
+//refundRooms divides the penalty of the supplier between the rooms by their price, the last room takes the rounding
+func refundRooms(rooms []dbmodel.OrderRoom, totals refundTotals) []dto.RefundPreviewRoomDto {
+	result := make([]dto.RefundPreviewRoomDto, 0, len(rooms))
+	var price int64
+	for _, room := range rooms {
+		price += room.Price
+	}
+	var divided int64
+	for i, room := range rooms {
+		penalty := totals.penalty - divided
+		if i < len(rooms)-1 {
+			penalty = 0
+			if price > 0 {
+				penalty = totals.penalty * room.Price / price
+			}
+		}
+		divided += penalty
+		refundable := room.Price - penalty
+		if refundable < 0 {
+			refundable = 0
+		}
+		result = append(result, dto.RefundPreviewRoomDto{
+			OrderRoomId:      room.ID,
+			Name:             room.Name,
+			NameEn:           room.NameEn,
+			Price:            room.Price,
+			PenaltyAmount:    penalty,
+			RefundableAmount: refundable,
+		})
+	}
+	return result
+}
+
+func refundPassengers(options []dto.OrderEnquiryItemOptionDto) []dto.RefundPreviewPassengerDto {
+	passengers := make([]dto.RefundPreviewPassengerDto, 0, len(options))
+	for _, option := range options {
+		name, lastName := option.PassengerInformation.NamePersian, option.PassengerInformation.LastNamePersian
+		if name == "" && lastName == "" {
+			name, lastName = option.PassengerInformation.Name, option.PassengerInformation.LastName
+		}
+		passengers = append(passengers, dto.RefundPreviewPassengerDto{
+			ReferenceCode:    option.ReferenceCode,
+			Name:             name,
+			LastName:         lastName,
+			IsRefundable:     option.IsRefundable,
+			PaidAmount:       option.PaidAmount,
+			PenaltyAmount:    option.TotalPenaltyAmount,
+			RefundableAmount: option.RefundableAmount,
+			RefundStatus:     option.RefundStatus,
+		})
+	}
+	return passengers
+}
+
+//orderForRefund returns the order with its refunds and the provider of its supplier
+func (g *hotelService) orderForRefund(orderId string) (*dbmodel.Order, core.HotelProvider, error) {
+	order, err := g.unitOfWork.Order().GetOneByIndraId(orderId)
+	if err != nil {
+		return nil, nil, err
+	}
+	provider, err := g.providers.Get(order.Supplier)
+	if err != nil {
+		return nil, nil, err
+	}
+	return order, provider, nil
+}
+
+//PreviewRefund is a dry run of the refund of the order or of some of its rooms or passengers, nothing is sent to the
+//supplier but the enquiry. the returned quote token must be sent with the refund so the guest is not refunded at
+//another penalty than the one shown. the rooms and the policy are only compared when the whole order is refunded
+func (g *hotelService) PreviewRefund(ctx context.Context, orderId string, referenceCodes []string) (*dto.RefundPreviewDto, error) {
+	order, provider, err := g.orderForRefund(orderId)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRefundable(order, referenceCodes); err != nil {
+		return nil, err
+	}
+	enquiry, err := provider.GetOrderEnquiry(ctx, orderId, order.ProviderOrderId)
+	if err != nil {
+		return nil, err
+	}
+	options, err := enquiryOptions(enquiry, referenceCodes)
+	if err != nil {
+		return nil, err
+	}
+	totals := enquiryTotals(options)
+	preview := &dto.RefundPreviewDto{
+		OrderId:            orderId,
+		ReferenceCodes:     referenceCodes,
+		PaidAmount:         totals.paid,
+		TotalPenaltyAmount: totals.penalty,
+		RefundableAmount:   totals.refundable,
+		Rooms:              make([]dto.RefundPreviewRoomDto, 0),
+		Passengers:         refundPassengers(options),
+	}
+
+	if len(referenceCodes) == 0 {
+		preview.Rooms = refundRooms(order.Rooms, totals)
+		if err := g.comparePolicyPenalty(ctx, preview); err != nil {
+			return nil, err
+		}
+	}
+
+	preview.QuoteExpiresAt = time.Now().Add(g.refundQuoteTtl)
+	preview.QuoteToken, err = signedtoken.Sign(g.refundQuoteSecret, refundQuote{
+		OrderId:            orderId,
+		ReferenceCodes:     referenceCodes,
+		TotalPenaltyAmount: totals.penalty,
+		RefundableAmount:   totals.refundable,
+		ExpiresAt:          preview.QuoteExpiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return preview, nil
+}
+
+//comparePolicyPenalty adds the penalty of the stored cancellation policy, a supplier penalty that is not the one of
+//the policy is logged for the support
+func (g *hotelService) comparePolicyPenalty(ctx context.Context, preview *dto.RefundPreviewDto) error {
+	policy, err := g.GetCancellationPenalty(ctx, preview.OrderId, time.Now())
+	if err == common.CancellationPolicyUnknown {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	preview.PolicyPenalty = &policy.Penalty
+	preview.PolicyRule = policy.Rule
+	if policy.Penalty != preview.TotalPenaltyAmount {
+		logger.WithName(logtags.RefundPolicyMismatch).WithData(map[string]interface{}{
+			"orderId":         preview.OrderId,
+			"policyPenalty":   policy.Penalty,
+			"supplierPenalty": preview.TotalPenaltyAmount,
+		}).Warn("penalty of the supplier is not the one of the cancellation policy")
+	}
+	return nil
+}
+
+//checkRefundQuote accepts the quote when it is signed for the refunded codes of the order, is not expired and the
+//supplier still returns the quoted amounts
+func (g *hotelService) checkRefundQuote(ctx context.Context, provider core.HotelProvider, order *dbmodel.Order,
+	request dto.OrderRefundRequestDto) error {
+	var quote refundQuote
+	if err := signedtoken.Verify(g.refundQuoteSecret, request.QuoteToken, &quote); err != nil ||
+		quote.OrderId != request.OrderId || !sameCodes(quote.ReferenceCodes, request.ReferenceCodes) {
+		return common.InvalidRefundQuote
+	}
+	if time.Now().Unix() > quote.ExpiresAt {
+		return common.RefundQuoteExpired
+	}
+	enquiry, err := provider.GetOrderEnquiry(ctx, request.OrderId, order.ProviderOrderId)
+	if err != nil {
+		return err
+	}
+	options, err := enquiryOptions(enquiry, request.ReferenceCodes)
+	if err != nil {
+		return err
+	}
+	if totals := enquiryTotals(options); totals.penalty != quote.TotalPenaltyAmount ||
+		totals.refundable != quote.RefundableAmount {
+		logger.WithName(logtags.RefundQuoteRejected).WithData(map[string]interface{}{
+			"orderId":          request.OrderId,
+			"quotedPenalty":    quote.TotalPenaltyAmount,
+			"quotedRefundable": quote.RefundableAmount,
+			"penalty":          totals.penalty,
+			"refundable":       totals.refundable,
+		}).Warn("refund penalty has changed since the quote")
+		return common.RefundQuoteChanged
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package logic
+
+import (
+	"context"
+	"hotel-engine/core"
+	"hotel-engine/core/common"
+	"hotel-engine/core/dbmodel"
+	"hotel-engine/core/dto"
+	"hotel-engine/utils/signedtoken"
+	"testing"
+	"time"
+)
+
+type enquiryProvider struct {
+	core.HotelProvider
+	enquiry dto.OrderEnquiryResponseDto
+}
+
+func (p *enquiryProvider) GetOrderEnquiry(context.Context, string, string) (dto.OrderEnquiryResponseDto, error) {
+	return p.enquiry, nil
+}
+
+func Test_checkRefundQuote(t *testing.T) {
+	provider := &enquiryProvider{enquiry: dto.OrderEnquiryResponseDto{Items: []dto.OrderEnquiryItemDto{{
+		Items: []dto.OrderEnquiryItemOptionDto{
+			{ReferenceCode: "A", PaidAmount: 1000, TotalPenaltyAmount: 300, RefundableAmount: 700},
+			{ReferenceCode: "B", PaidAmount: 1000, TotalPenaltyAmount: 500, RefundableAmount: 500},
+		},
+	}}}}
+	service := &hotelService{refundQuoteSecret: "secret"}
+	expiresAt := time.Now().Add(time.Minute).Unix()
+	tests := []struct {
+		name    string
+		secret  string
+		quote   refundQuote
+		request dto.OrderRefundRequestDto
+		wantErr error
+	}{
+		{
+			name:    "quote of the whole order",
+			secret:  "secret",
+			quote:   refundQuote{OrderId: "1", TotalPenaltyAmount: 800, RefundableAmount: 1200, ExpiresAt: expiresAt},
+			request: dto.OrderRefundRequestDto{OrderId: "1"},
+		},
+		{
+			name:   "quote of the same codes in another order",
+			secret: "secret",
+			quote: refundQuote{OrderId: "1", ReferenceCodes: []string{"B", "A"}, TotalPenaltyAmount: 800,
+				RefundableAmount: 1200, ExpiresAt: expiresAt},
+			request: dto.OrderRefundRequestDto{OrderId: "1", ReferenceCodes: []string{"A", "B"}},
+		},
+		{
+			name:   "quote of one code",
+			secret: "secret",
+			quote: refundQuote{OrderId: "1", ReferenceCodes: []string{"A"}, TotalPenaltyAmount: 300,
+				RefundableAmount: 700, ExpiresAt: expiresAt},
+			request: dto.OrderRefundRequestDto{OrderId: "1", ReferenceCodes: []string{"A"}},
+		},
+		{
+			name:    "quote of another order",
+			secret:  "secret",
+			quote:   refundQuote{OrderId: "2", TotalPenaltyAmount: 800, RefundableAmount: 1200, ExpiresAt: expiresAt},
+			request: dto.OrderRefundRequestDto{OrderId: "1"},
+			wantErr: common.InvalidRefundQuote,
+		},
+		{
+			name:   "quote of other codes",
+			secret: "secret",
+			quote: refundQuote{OrderId: "1", ReferenceCodes: []string{"A"}, TotalPenaltyAmount: 300,
+				RefundableAmount: 700, ExpiresAt: expiresAt},
+			request: dto.OrderRefundRequestDto{OrderId: "1", ReferenceCodes: []string{"B"}},
+			wantErr: common.InvalidRefundQuote,
+		},
+		{
+			name:    "quote signed with another secret",
+			secret:  "another",
+			quote:   refundQuote{OrderId: "1", TotalPenaltyAmount: 800, RefundableAmount: 1200, ExpiresAt: expiresAt},
+			request: dto.OrderRefundRequestDto{OrderId: "1"},
+			wantErr: common.InvalidRefundQuote,
+		},
+		{
+			name:    "no quote",
+			request: dto.OrderRefundRequestDto{OrderId: "1"},
+			wantErr: common.InvalidRefundQuote,
+		},
+		{
+			name:   "expired quote",
+			secret: "secret",
+			quote: refundQuote{OrderId: "1", TotalPenaltyAmount: 800, RefundableAmount: 1200,
+				ExpiresAt: time.Now().Add(-time.Minute).Unix()},
+			request: dto.OrderRefundRequestDto{OrderId: "1"},
+			wantErr: common.RefundQuoteExpired,
+		},
+		{
+			name:    "penalty changed since the quote",
+			secret:  "secret",
+			quote:   refundQuote{OrderId: "1", TotalPenaltyAmount: 600, RefundableAmount: 1200, ExpiresAt: expiresAt},
+			request: dto.OrderRefundRequestDto{OrderId: "1"},
+			wantErr: common.RefundQuoteChanged,
+		},
+		{
+			name:    "refundable amount changed since the quote",
+			secret:  "secret",
+			quote:   refundQuote{OrderId: "1", TotalPenaltyAmount: 800, RefundableAmount: 1400, ExpiresAt: expiresAt},
+			request: dto.OrderRefundRequestDto{OrderId: "1"},
+			wantErr: common.RefundQuoteChanged,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.secret != "" {
+				token, err := signedtoken.Sign(tt.secret, tt.quote)
+				if err != nil {
+					t.Fatalf("Sign() error = %v", err)
+				}
+				tt.request.QuoteToken = token
+			}
+			err := service.checkRefundQuote(context.Background(), provider, &dbmodel.Order{}, tt.request)
+			if err != tt.wantErr {
+				t.Errorf("checkRefundQuote() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
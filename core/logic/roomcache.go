@@ -0,0 +1,127 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"hotel-engine/core"
+	"hotel-engine/core/common/logtags"
+	"hotel-engine/core/dto"
+	"hotel-engine/infrastructure/logger"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//the room caches are keyed by the hotel cache version so invalidating a hotel drops every cached rooms list,
+//option info and order detail of it at once. a place id is only unique between the hotels of a supplier so the keys
+//have the supplier too
+func (g *hotelService) hotelCacheVersion(supplier, hotelId string) string {
+	var version string
+	if !g.responseCache.Get(roomCacheKey("version", supplier, hotelId), &version) {
+		return "0"
+	}
+	return version
+}
+
+//invalidateHotelRooms moves the hotel to a new cache version. the version outlives every cached entry of the old one
+func (g *hotelService) invalidateHotelRooms(supplier, hotelId, reason string) {
+	ttl := g.roomsCacheTtl
+	if g.optionInfoCacheTtl > ttl {
+		ttl = g.optionInfoCacheTtl
+	}
+	g.responseCache.Set(roomCacheKey("version", supplier, hotelId), strconv.FormatInt(time.Now().UnixNano(), 36), ttl)
+	logger.WithName(logtags.RoomCacheInvalidated).WithData(map[string]string{
+		"supplier": supplier,
+		"hotelId":  hotelId,
+		"reason":   reason,
+	}).Info("cached rooms of the hotel are invalidated")
+}
+
+func roomCacheKey(kind string, parts ...string) string {
+	return "hotel-engine:rooms:" + kind + ":" + strings.Join(parts, ":")
+}
+
+func occupancyKey(rooms []dto.RequestRoomDto) string {
+	data, _ := json.Marshal(rooms)
+	return string(data)
+}
+
+func (g *hotelService) cachedHotelRooms(ctx context.Context, request dto.HotelRoomsDto) (*dto.RateRoomResponseDto, error) {
+	supplier := g.hotelSupplier(request.Supplier)
+	key := roomCacheKey("list", supplier, request.HotelId, g.hotelCacheVersion(supplier, request.HotelId),
+		request.CheckIn, request.CheckOut, occupancyKey(request.Rooms))
+	var cached dto.RateRoomResponseDto
+	if g.responseCache.Get(key, &cached) {
+		return &cached, nil
+	}
+	provider, err := g.providers.Get(supplier)
+	if err != nil {
+		return nil, err
+	}
+	res, err := provider.GetHotelRooms(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	//an empty list usually means the supplier was not ready yet
+	if len(res.Rooms) != 0 {
+		g.responseCache.Set(key, res, g.roomsCacheTtl)
+	}
+	return res, nil
+}
+
+func (g *hotelService) cachedHotelRoomsWithSession(ctx context.Context, request dto.HotelRoomsWithSessionDto) (*dto.RateRoomResponseDto, error) {
+	supplier := g.hotelSupplier(request.Supplier)
+	key := roomCacheKey("session", supplier, request.HotelId, g.hotelCacheVersion(supplier, request.HotelId),
+		request.SessionId)
+	var cached dto.RateRoomResponseDto
+	if g.responseCache.Get(key, &cached) {
+		return &cached, nil
+	}
+	provider, err := g.providers.Get(supplier)
+	if err != nil {
+		return nil, err
+	}
+	res, err := provider.GetHotelRoomsWithSession(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Rooms) != 0 {
+		g.responseCache.Set(key, res, g.roomsCacheTtl)
+	}
+	return res, nil
+}
+
+func (g *hotelService) cachedHotelOptionInfo(ctx context.Context, request dto.OptionInfoRequestDto) (*dto.OptionInfoResponseDto, error) {
+	supplier := g.hotelSupplier(request.Supplier)
+	key := roomCacheKey("option-info", supplier, request.HotelId, g.hotelCacheVersion(supplier, request.HotelId),
+		request.SessionId, request.OptionId)
+	var cached dto.OptionInfoResponseDto
+	if g.responseCache.Get(key, &cached) {
+		return &cached, nil
+	}
+	provider, err := g.providers.Get(supplier)
+	if err != nil {
+		return nil, err
+	}
+	res, err := provider.GetHotelOptionInfo(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	g.responseCache.Set(key, res, g.optionInfoCacheTtl)
+	return res, nil
+}
+
+func (g *hotelService) cachedOrderDetail(ctx context.Context, provider core.HotelProvider, supplier, hotelId, sessionId,
+	optionId string) (*dto.OrderDetailDto, error) {
+	key := roomCacheKey("order-detail", supplier, hotelId, g.hotelCacheVersion(supplier, hotelId), sessionId, optionId)
+	var cached dto.OrderDetailDto
+	if g.responseCache.Get(key, &cached) {
+		return &cached, nil
+	}
+	res, err := provider.GetOrderDetail(ctx, hotelId, sessionId, optionId)
+	if err != nil {
+		return nil, err
+	}
+	g.responseCache.Set(key, res, g.optionInfoCacheTtl)
+	return res, nil
+}
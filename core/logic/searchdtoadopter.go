@@ -10,6 +10,8 @@ import (
 	"hotel-engine/infrastructure/hotelproviderinterface/dtos"
 	"hotel-engine/infrastructure/logger"
 	"math"
+	"strconv"
+	"strings"
 )
 
 type searchDtoAdopter struct {
@@ -79,6 +81,20 @@ func createSearchFilters(searchDto dto.SearchDto) []interface{} {
 		}
 	}
 
+	if len(searchDto.Amenities) > 0 {
+		filters = append(filters, dtos.SearchHotelsIntFilter{
+			Field: "facilities",
+			Value: searchDto.Amenities,
+		})
+	}
+
+	if len(searchDto.MealPlans) > 0 {
+		filters = append(filters, dtos.SearchHotelsStringFilter{
+			Field: "mealPlan",
+			Value: searchDto.MealPlans,
+		})
+	}
+
 	if searchDto.Score.Start != 0 || searchDto.Score.End != 0 {
 		filters = append(filters, dtos.SearchHotelsFloatFilter{
 			Field: "score",
@@ -158,6 +174,7 @@ func (p *searchDtoAdopter) CreateResultDto(results []dtos.Result, hotels []dbmod
 		newRes.RateReview.Count = hotel.RateReviewCount
 		newRes.RateReview.Score = hotel.RateReviewScore
 		newRes.Type = hotel.Type
+		newRes.Supplier = hotel.Supplier
 
 		newRes.Badges = make([]dto.SearchResponseBadgeDto, 0)
 		for _, badge := range hotel.Badges {
@@ -240,6 +257,128 @@ func (p *searchDtoAdopter) createSearchResponseHotelDto(result dtos.Result, city
 	}
 }
 
+//CreateCatalogueDto creates the database search of the request, the filters and sorts are the same as the provider ones.
+//meal plans are only known by the supplier so they are not filtered
+func (p *searchDtoAdopter) CreateCatalogueDto(searchDto dto.SearchDto) (*dto.CatalogueSearchDto, error) {
+	city, err := p.cacheStore.CityStore().FindOne(searchDto.City)
+	if err != nil {
+		return nil, err
+	}
+	if searchDto.PageNumber <= 0 {
+		searchDto.PageNumber = 1
+	}
+	if searchDto.PageSize <= 0 {
+		searchDto.PageSize = common.DefaultSearchPageSize
+	}
+	sort := "score"
+	if searchDto.Sort != "" {
+		sort = searchDto.Sort
+	}
+	hotelTypes := make([]string, 0, len(searchDto.HotelTypes))
+	for _, t := range searchDto.HotelTypes {
+		if _, found := common.HotelTypes[t]; found {
+			hotelTypes = append(hotelTypes, t)
+		}
+	}
+	return &dto.CatalogueSearchDto{
+		City:       city.Name,
+		Keyword:    searchDto.Keyword,
+		MinPrice:   searchDto.Price.Start,
+		MaxPrice:   searchDto.Price.End,
+		MinScore:   float64(searchDto.Score.Start),
+		MaxScore:   float64(searchDto.Score.End),
+		Stars:      searchDto.Stars,
+		HotelTypes: hotelTypes,
+		Regions:    searchDto.Region,
+		Amenities:  searchDto.Amenities,
+		Categories: searchDto.Categories,
+		SortField:  sort,
+		Ascending:  searchDto.SortDirection,
+		Limit:      searchDto.PageSize,
+		Skip:       searchDto.PageSize * (searchDto.PageNumber - 1),
+	}, nil
+}
+
+//CreateCatalogueResultDto creates the results from the synced hotels, the prices are the last synced ones
+func (p *searchDtoAdopter) CreateCatalogueResultDto(hotels []dbmodel.Hotel, days int) *dto.SearchResponseDto {
+	if days < 1 {
+		days = 1
+	}
+	resultsDto := make([]dto.SearchResponseHotelDto, 0, len(hotels))
+	for _, hotel := range hotels {
+		lat, lon := 0.0, 0.0
+		geo := strings.Split(hotel.GeoLocation, ",")
+		if len(geo) == 2 {
+			lat, _ = strconv.ParseFloat(geo[0], 64)
+			lon, _ = strconv.ParseFloat(geo[1], 64)
+		}
+		images := make([]string, 0)
+		if hotel.Images != "" {
+			images = strings.Split(hotel.Images, ",")
+		}
+		image := ""
+		if len(images) > 0 {
+			image = images[0]
+		}
+		amenities := make([]string, 0, len(hotel.Amenities))
+		for _, amenity := range hotel.Amenities {
+			amenities = append(amenities, amenity.Name)
+		}
+		badges := make([]dto.SearchResponseBadgeDto, 0, len(hotel.Badges))
+		for _, badge := range hotel.Badges {
+			badges = append(badges, dto.SearchResponseBadgeDto{
+				Name: badge.Text,
+				Icon: badge.Icon,
+			})
+		}
+		oldPrice := hotel.OldPrice
+		resultsDto = append(resultsDto, dto.SearchResponseHotelDto{
+			PlaceID:         hotel.PlaceID,
+			Id:              hotel.PlaceID,
+			Type:            hotel.Type,
+			Kind:            hotel.Kind,
+			MinNight:        hotel.MinNight,
+			ReservationType: hotel.ReservationType,
+			PaymentType:     hotel.PaymentType,
+			Name:            hotel.Name,
+			NameEn:          hotel.NameEn,
+			Description:     hotel.Description,
+			Region:          hotel.Region,
+			Images:          images,
+			Image:           image,
+			MinPrice:        float64(hotel.Price * int64(days)),
+			PricePerNight:   float64(hotel.Price),
+			Star:            hotel.Star,
+			Location: dto.SearchResponseLocationDto{
+				City:   hotel.City,
+				CityEn: hotel.CityEn,
+				Geo: dto.SearchResponseLocationGeoDto{
+					Lat: lat,
+					Lon: lon,
+				},
+				Province: hotel.Province,
+			},
+			Tags:     nil,
+			Verified: hotel.Verified,
+			RateReview: dto.SearchResponseRateReviewDto{
+				Count: hotel.RateReviewCount,
+				Score: hotel.RateReviewScore,
+			},
+			Amenities:     amenities,
+			OldPrice:      &oldPrice,
+			Discount:      int(hotel.DiscountPercent),
+			DiscountPrice: int(hotel.DiscountPrice),
+			Badges:        badges,
+			Supplier:      hotel.Supplier,
+		})
+	}
+	return &dto.SearchResponseDto{
+		Result:           resultsDto,
+		IndicativePrices: true,
+		Error:            nil,
+	}
+}
+
 func NewProviderSearchDtoFactory(cacheStore core.CacheStore) core.SearchDtoAdopter {
 	return &searchDtoAdopter{cacheStore: cacheStore}
 }
@@ -0,0 +1,197 @@
+package logic
+
+import (
+	"context"
+	"hotel-engine/core"
+	"hotel-engine/core/common"
+	"hotel-engine/core/common/logtags"
+	"hotel-engine/core/dbmodel"
+	"hotel-engine/core/dto"
+	"hotel-engine/infrastructure/hotelproviderinterface/dtos"
+	"hotel-engine/infrastructure/logger"
+	"sort"
+)
+
+//facetItem is the part of a search result that the filter options are counted on
+type facetItem struct {
+	star          int
+	pricePerNight float64
+	score         float64
+	hotelType     string
+	amenities     []int
+}
+
+func supplierFacetItems(results []dtos.Result) []facetItem {
+	items := make([]facetItem, 0, len(results))
+	for _, result := range results {
+		items = append(items, facetItem{
+			star:          result.Star,
+			pricePerNight: result.PricePerNight,
+			score:         float64(result.Score),
+			hotelType:     common.HotelTypesString[result.Accommodation.ID],
+			amenities:     result.Facilities,
+		})
+	}
+	return items
+}
+
+func catalogueFacetItems(hotels []dbmodel.Hotel) []facetItem {
+	items := make([]facetItem, 0, len(hotels))
+	for _, hotel := range hotels {
+		amenities := make([]int, 0, len(hotel.Amenities))
+		for _, amenity := range hotel.Amenities {
+			amenities = append(amenities, amenity.ID)
+		}
+		items = append(items, facetItem{
+			star:          hotel.Star,
+			pricePerNight: float64(hotel.Price),
+			score:         hotel.RateReviewScore,
+			hotelType:     hotel.Type,
+			amenities:     amenities,
+		})
+	}
+	return items
+}
+
+func priceBucket(price float64) int64 {
+	switch {
+	case price < 500000:
+		return dto.Price_0_500000
+	case price < 1000000:
+		return dto.Price_500000_1000000
+	case price < 1500000:
+		return dto.Price_1000000_1500000
+	case price < 2000000:
+		return dto.Price_1500000_2000000
+	default:
+		return dto.Price_over_2000000
+	}
+}
+
+func scoreBand(score float64) int64 {
+	switch {
+	case score < 6:
+		return dto.Score_lower_than_6
+	case score < 7:
+		return dto.Score_6_7
+	case score < 8:
+		return dto.Score_7_8
+	case score < 9:
+		return dto.Score_8_9
+	default:
+		return dto.Score_9_10
+	}
+}
+
+func starOption(star int) int64 {
+	if star < 1 || star > 5 {
+		return -1
+	}
+	return int64(star)
+}
+
+func setCountsByValue(output *dto.FilterOutput, counts map[int64]int) {
+	for i := range output.Filters {
+		count := counts[output.Filters[i].Value]
+		output.Filters[i].Count = &count
+	}
+}
+
+//searchFilters returns the filter options with the number of the items matching each of them and the price range
+//of the items. amenities and amenity categories that no item has are left out
+func (g *hotelService) searchFilters(items []facetItem) (*dto.FiltersDto, float64, float64) {
+	prices := make(map[int64]int)
+	stars := make(map[int64]int)
+	scores := make(map[int64]int)
+	types := make(map[string]int)
+	amenities := make(map[int]int)
+	categories := make(map[uint]int)
+	amenityStore := g.cacheStore.AmenityStore()
+	minPrice, maxPrice := 0.0, 0.0
+	for _, item := range items {
+		if item.pricePerNight > 0 {
+			prices[priceBucket(item.pricePerNight)]++
+			if minPrice == 0 || item.pricePerNight < minPrice {
+				minPrice = item.pricePerNight
+			}
+			if item.pricePerNight > maxPrice {
+				maxPrice = item.pricePerNight
+			}
+		}
+		stars[starOption(item.star)]++
+		scores[scoreBand(item.score)]++
+		types[item.hotelType]++
+		itemCategories := make(map[uint]bool)
+		for _, id := range item.amenities {
+			amenities[id]++
+			amenity, err := amenityStore.Get(id)
+			if err == nil && amenity.AmenityCategoryID != nil {
+				itemCategories[*amenity.AmenityCategoryID] = true
+			}
+		}
+		for id := range itemCategories {
+			categories[id]++
+		}
+	}
+
+	filters := dto.CreateDefaultFiltersDto(nil)
+	setCountsByValue(&filters.PriceFilter, prices)
+	setCountsByValue(&filters.StarFilters, stars)
+	setCountsByValue(&filters.ScoreFilters, scores)
+	for i := range filters.HotelTypes.Filters {
+		count := types[filters.HotelTypes.Filters[i].Key]
+		filters.HotelTypes.Filters[i].Count = &count
+	}
+
+	categoryOrder := make(map[uint]uint)
+	for _, amenity := range amenityStore.GetAll() {
+		count := amenities[amenity.ID]
+		if count == 0 {
+			continue
+		}
+		filters.AmenityFilters.Filters = append(filters.AmenityFilters.Filters, dto.FilterDto{
+			Key:   amenity.Name,
+			Value: int64(amenity.ID),
+			Count: &count,
+		})
+		category := amenity.AmenityCategory
+		if category == nil || categoryOrder[category.ID] != 0 || categories[category.ID] == 0 {
+			continue
+		}
+		categoryOrder[category.ID] = category.Order + 1
+		categoryCount := categories[category.ID]
+		filters.AmenityCategoryFilters.Filters = append(filters.AmenityCategoryFilters.Filters, dto.FilterDto{
+			Key:   category.Name,
+			Value: int64(category.ID),
+			Count: &categoryCount,
+		})
+	}
+	sort.SliceStable(filters.AmenityFilters.Filters, func(i, j int) bool {
+		return *filters.AmenityFilters.Filters[i].Count > *filters.AmenityFilters.Filters[j].Count
+	})
+	sort.SliceStable(filters.AmenityCategoryFilters.Filters, func(i, j int) bool {
+		return categoryOrder[uint(filters.AmenityCategoryFilters.Filters[i].Value)] <
+			categoryOrder[uint(filters.AmenityCategoryFilters.Filters[j].Value)]
+	})
+	return filters, minPrice, maxPrice
+}
+
+//facetResults returns the results that the filter options are counted on, the first results of the search up to the
+//facet window. the window is a cached page of the same session so it is requested once per session and filters
+func (g *hotelService) facetResults(ctx context.Context, provider core.HotelProvider, supplier, sessionId string,
+	searchDto dtos.ProviderSearchDto, page searchPage) []dtos.Result {
+	request := searchDto.RequestSearchHotels
+	if request.Skip == 0 && (request.Limit >= int64(g.facetWindow) || len(page.Results) >= page.TotalHits) {
+		return page.Results
+	}
+	searchDto.RequestSearchHotels.SessionId = sessionId
+	searchDto.RequestSearchHotels.Skip = 0
+	searchDto.RequestSearchHotels.Limit = int64(g.facetWindow)
+	window, err := g.searchPage(ctx, provider, supplier, searchDto)
+	if err != nil {
+		logger.WithName(logtags.SearchFacetsError).WithException(err).
+			Warn("cannot get the facet window, the filter options are counted on the page")
+		return page.Results
+	}
+	return window.Results
+}
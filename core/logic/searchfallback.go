@@ -0,0 +1,81 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"hotel-engine/core/common"
+	"hotel-engine/core/common/logtags"
+	"hotel-engine/core/dbmodel"
+	"hotel-engine/core/dto"
+	"hotel-engine/infrastructure/logger"
+	"hotel-engine/utils/geo"
+	"hotel-engine/utils/httphelper"
+	"net/http"
+)
+
+//isSupplierUnavailableError reports whether the search failed because the supplier is down or overloaded. business
+//errors of the supplier are not a reason to fall back to the synced hotels
+func isSupplierUnavailableError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || httphelper.IsRetryableError(err) {
+		return true
+	}
+	switch err {
+	case common.ProviderCircuitOpenProblem, common.ProviderTooManyRequestsProblem,
+		common.GetHotelListMaxTryLimitReached, common.ProviderRequestBudgetExhausted:
+		return true
+	}
+	var responseError *httphelper.ResponseError
+	return errors.As(err, &responseError) && responseError.StatusCode >= http.StatusInternalServerError
+}
+
+//catalogueSearch answers the search from the synced hotels. the prices are indicative, when the catalogue cannot be
+//searched either the supplier error is returned
+func (g *hotelService) catalogueSearch(request dto.SearchDto, area *geoSearch, days int, supplierErr error) (*dto.SearchResponseDto, error) {
+	search, err := g.searchDtoAdopter.CreateCatalogueDto(request)
+	if err != nil {
+		return nil, err
+	}
+	if area != nil {
+		search.Limit = int64(g.geoSearchWindow)
+		search.Skip = 0
+	}
+	hotels, total, err := g.unitOfWork.Hotel().SearchCatalogue(*search)
+	if err != nil {
+		logger.WithName(logtags.SearchFallbackUsed).WithException(err).
+			Error("cannot search the synced hotels after the supplier search failed")
+		return nil, supplierErr
+	}
+	distances := make(map[string]float64)
+	facets := hotels
+	if area != nil {
+		matches := area.match(len(hotels), func(i int) (geo.Point, bool) {
+			return geo.ParsePoint(hotels[i].GeoLocation)
+		})
+		facets = make([]dbmodel.Hotel, 0, len(matches))
+		for _, m := range matches {
+			facets = append(facets, hotels[m.index])
+		}
+		total = len(matches)
+		page := make([]dbmodel.Hotel, 0)
+		for _, m := range pageOf(matches, request.PageNumber, request.PageSize) {
+			page = append(page, hotels[m.index])
+			distances[hotels[m.index].PlaceID] = m.distance
+		}
+		hotels = page
+	} else if search.Skip != 0 || int64(len(hotels)) < int64(total) {
+		search.Skip = 0
+		search.Limit = int64(g.facetWindow)
+		if window, _, err := g.unitOfWork.Hotel().SearchCatalogue(*search); err == nil {
+			facets = window
+		}
+	}
+	res := g.searchDtoAdopter.CreateCatalogueResultDto(hotels, days)
+	g.setSearchOptions(res, area != nil, catalogueFacetItems(facets))
+	setDistances(res, distances)
+	res.TotalHits = total
+	logger.WithName(logtags.SearchFallbackUsed).WithException(supplierErr).WithData(map[string]interface{}{
+		"city":      request.City,
+		"totalHits": total,
+	}).Warn("supplier search is not available, answered from the synced hotels with indicative prices")
+	return res, nil
+}
@@ -0,0 +1,82 @@
+package logic
+
+import (
+	"hotel-engine/core/dbmodel"
+	"hotel-engine/core/dto"
+	"hotel-engine/infrastructure/hotelproviderinterface/dtos"
+)
+
+//localSearchFilter is the part of the search that the supplier does not know, regions are taken from the synced
+//hotels and amenity categories from the amenity store. like the geo search it is applied on a window of the results
+type localSearchFilter struct {
+	supplier   string
+	regions    map[string]bool
+	categories []uint
+	categoryOf map[int]uint
+}
+
+//localSearchFilter returns nil when all of the filters of the request are sent to the supplier
+func (g *hotelService) localSearchFilter(request dto.SearchDto) *localSearchFilter {
+	if len(request.Region) == 0 && len(request.Categories) == 0 {
+		return nil
+	}
+	filter := &localSearchFilter{
+		supplier:   g.hotelSupplier(request.Supplier),
+		regions:    make(map[string]bool, len(request.Region)),
+		categories: request.Categories,
+		categoryOf: make(map[int]uint),
+	}
+	for _, region := range request.Region {
+		filter.regions[region] = true
+	}
+	for _, amenity := range g.cacheStore.AmenityStore().GetAll() {
+		if amenity.AmenityCategoryID != nil {
+			filter.categoryOf[amenity.ID] = *amenity.AmenityCategoryID
+		}
+	}
+	return filter
+}
+
+func (f *localSearchFilter) matches(result dtos.Result, hotel *dbmodel.Hotel) bool {
+	if len(f.regions) > 0 && (hotel == nil || !f.regions[hotel.Region]) {
+		return false
+	}
+	for _, category := range f.categories {
+		found := false
+		for _, facility := range result.Facilities {
+			if c, ok := f.categoryOf[facility]; ok && c == category {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+//filterResults keeps the results that match the local filters, the order of the supplier is kept
+func (g *hotelService) filterResults(results []dtos.Result, filter *localSearchFilter) ([]dtos.Result, error) {
+	if filter == nil {
+		return results, nil
+	}
+	var hotels []dbmodel.Hotel
+	if len(filter.regions) > 0 {
+		var err error
+		if hotels, err = g.getHotelsFromResults(results, filter.supplier); err != nil {
+			return nil, err
+		}
+	}
+	hotelsById := make(map[string]*dbmodel.Hotel, len(hotels))
+	for i := range hotels {
+		hotelsById[hotels[i].PlaceID] = &hotels[i]
+	}
+	filtered := make([]dtos.Result, 0, len(results))
+	for _, result := range results {
+		if filter.matches(result, hotelsById[result.ID]) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,89 @@
+package logic
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"hotel-engine/core"
+	"hotel-engine/infrastructure/hotelproviderinterface/dtos"
+	"hotel-engine/utils/httphelper"
+	"net/http"
+)
+
+//searchPage is a completed page of the supplier search results
+type searchPage struct {
+	Results   []dtos.Result
+	TotalHits int
+}
+
+//searchFingerprint identifies the supplier session of a search, the session depends on the destination, dates and
+//rooms only so paging, sorting and filters are done in the same session
+func searchFingerprint(supplier string, session dtos.ProviderRequestSessionDto) string {
+	sum := sha1.Sum(append([]byte(supplier+":"), session.ToJson()...))
+	return "hotel-engine:search:session:" + hex.EncodeToString(sum[:])
+}
+
+func searchPageKey(supplier string, request dtos.ProviderSearchHotelsRequestDto) string {
+	sessionId := request.SessionId
+	request.SessionId = ""
+	data, _ := json.Marshal(request)
+	sum := sha1.Sum(data)
+	return "hotel-engine:search:page:" + supplier + ":" + sessionId + ":" + hex.EncodeToString(sum[:])
+}
+
+//isExpiredSessionError reports whether the supplier rejected the session of the search
+func isExpiredSessionError(err error) bool {
+	var responseError *httphelper.ResponseError
+	if !errors.As(err, &responseError) {
+		return false
+	}
+	return responseError.StatusCode == http.StatusBadRequest ||
+		responseError.StatusCode == http.StatusNotFound ||
+		responseError.StatusCode == http.StatusGone
+}
+
+//searchResults returns the results of the requested page and the session they belong to. the supplier session of the
+//search is reused while it is valid and completed pages are served from the cache
+func (g *hotelService) searchResults(ctx context.Context, provider core.HotelProvider, supplier string,
+	searchDto dtos.ProviderSearchDto) (searchPage, string, error) {
+	if searchDto.RequestSearchHotels.SessionId != "" {
+		page, err := g.searchPage(ctx, provider, supplier, searchDto)
+		return page, searchDto.RequestSearchHotels.SessionId, err
+	}
+	sessionKey := searchFingerprint(supplier, searchDto.RequestSession)
+	var sessionId string
+	if g.responseCache.Get(sessionKey, &sessionId) {
+		searchDto.RequestSearchHotels.SessionId = sessionId
+		page, err := g.searchPage(ctx, provider, supplier, searchDto)
+		if err == nil || !isExpiredSessionError(err) {
+			return page, sessionId, err
+		}
+		g.responseCache.Delete(sessionKey)
+	}
+	sessionId, err := provider.CreateSearchSession(ctx, searchDto.RequestSession)
+	if err != nil {
+		return searchPage{}, "", err
+	}
+	g.responseCache.Set(sessionKey, sessionId, g.searchSessionTtl)
+	searchDto.RequestSearchHotels.SessionId = sessionId
+	page, err := g.searchPage(ctx, provider, supplier, searchDto)
+	return page, sessionId, err
+}
+
+func (g *hotelService) searchPage(ctx context.Context, provider core.HotelProvider, supplier string,
+	searchDto dtos.ProviderSearchDto) (searchPage, error) {
+	key := searchPageKey(supplier, searchDto.RequestSearchHotels)
+	var page searchPage
+	if g.responseCache.Get(key, &page) {
+		return page, nil
+	}
+	results, totalHits, err := provider.SearchResult(ctx, searchDto)
+	if err != nil {
+		return searchPage{}, err
+	}
+	page = searchPage{Results: results, TotalHits: totalHits}
+	g.responseCache.Set(key, page, g.searchPageTtl)
+	return page, nil
+}
@@ -0,0 +1,206 @@
+package stores
+
+import (
+	"hotel-engine/core"
+	"hotel-engine/core/dbmodel"
+	"hotel-engine/core/dto"
+	"hotel-engine/utils/persian"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+//the ranks of a match, a lower rank is a better match
+const (
+	exactMatch = iota
+	prefixMatch
+	wordPrefixMatch
+	soundMatch
+	containsMatch
+	typoMatch
+	noMatch
+)
+
+var autocompleteTypeOrder = map[string]int{
+	dto.AutocompleteCity:     0,
+	dto.AutocompleteProvince: 1,
+	dto.AutocompleteHotel:    2,
+}
+
+type autocompleteKey struct {
+	text     string
+	compact  string
+	words    []string
+	skeleton string
+	sounds   []string
+}
+
+type autocompleteEntry struct {
+	item dto.AutocompleteItemDto
+	keys []autocompleteKey
+}
+
+type autocompleteCacheStore struct {
+	entries []autocompleteEntry
+}
+
+func newAutocompleteKey(name string) (autocompleteKey, bool) {
+	text := persian.Normalize(name)
+	if text == "" {
+		return autocompleteKey{}, false
+	}
+	skeleton := persian.Skeleton(text)
+	return autocompleteKey{
+		text:     text,
+		compact:  strings.Replace(text, " ", "", -1),
+		words:    strings.Fields(text),
+		skeleton: strings.Replace(skeleton, " ", "", -1),
+		sounds:   strings.Fields(skeleton),
+	}, true
+}
+
+//rank compares the key with a normalized query, the sound of the words is compared before the typos
+func (k autocompleteKey) rank(query autocompleteKey) int {
+	switch {
+	case k.text == query.text || k.compact == query.compact:
+		return exactMatch
+	case strings.HasPrefix(k.text, query.text) || strings.HasPrefix(k.compact, query.compact):
+		return prefixMatch
+	}
+	for _, word := range k.words {
+		if strings.HasPrefix(word, query.text) {
+			return wordPrefixMatch
+		}
+	}
+	if utf8.RuneCountInString(query.skeleton) >= 2 {
+		if strings.HasPrefix(k.skeleton, query.skeleton) {
+			return soundMatch
+		}
+		for _, sound := range k.sounds {
+			if strings.HasPrefix(sound, query.skeleton) {
+				return soundMatch
+			}
+		}
+	}
+	if utf8.RuneCountInString(query.compact) >= 2 && strings.Contains(k.compact, query.compact) {
+		return containsMatch
+	}
+	if hasTypos(k.compact, query.compact) || hasTypos(k.skeleton, query.skeleton) {
+		return typoMatch
+	}
+	return noMatch
+}
+
+//hasTypos reports whether the start of the text is the query with a few typos, one typo is tolerated for the queries
+//of at least four letters and two for the ones of at least eight
+func hasTypos(text, query string) bool {
+	length := utf8.RuneCountInString(query)
+	if length < 4 {
+		return false
+	}
+	allowed := 1
+	if length >= 8 {
+		allowed = 2
+	}
+	runes := []rune(text)
+	for size := length - 1; size <= length+1 && size <= len(runes); size++ {
+		if persian.Distance(string(runes[:size]), query) <= allowed {
+			return true
+		}
+	}
+	return false
+}
+
+//Search returns the best matches of the query, cities come before provinces and hotels with the same rank
+func (s *autocompleteCacheStore) Search(query string, limit int) []dto.AutocompleteItemDto {
+	items := make([]dto.AutocompleteItemDto, 0)
+	normalized, ok := newAutocompleteKey(query)
+	if !ok || limit <= 0 {
+		return items
+	}
+	type match struct {
+		entry *autocompleteEntry
+		rank  int
+	}
+	matches := make([]match, 0)
+	for i := range s.entries {
+		best := noMatch
+		for _, key := range s.entries[i].keys {
+			if rank := key.rank(normalized); rank < best {
+				best = rank
+			}
+		}
+		if best != noMatch {
+			matches = append(matches, match{entry: &s.entries[i], rank: best})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.rank != b.rank {
+			return a.rank < b.rank
+		}
+		if a.entry.item.Type != b.entry.item.Type {
+			return autocompleteTypeOrder[a.entry.item.Type] < autocompleteTypeOrder[b.entry.item.Type]
+		}
+		if la, lb := utf8.RuneCountInString(a.entry.item.Name), utf8.RuneCountInString(b.entry.item.Name); la != lb {
+			return la < lb
+		}
+		return a.entry.item.Name < b.entry.item.Name
+	})
+	for i := 0; i < len(matches) && i < limit; i++ {
+		items = append(items, matches[i].entry.item)
+	}
+	return items
+}
+
+func (s *autocompleteCacheStore) add(item dto.AutocompleteItemDto, names ...string) {
+	entry := autocompleteEntry{item: item}
+	for _, name := range names {
+		if key, ok := newAutocompleteKey(name); ok {
+			entry.keys = append(entry.keys, key)
+		}
+	}
+	if len(entry.keys) > 0 {
+		s.entries = append(s.entries, entry)
+	}
+}
+
+//NewAutocompleteCacheStore indexes the cities, their provinces and the hotels, the provinces of the hotels are used
+//to find the english name of the provinces
+func NewAutocompleteCacheStore(cities []dto.CityDto, hotels []dbmodel.Hotel) core.AutocompleteCacheStore {
+	store := &autocompleteCacheStore{entries: make([]autocompleteEntry, 0, len(cities)+len(hotels))}
+	provinces := make(map[string]string)
+	for _, city := range cities {
+		store.add(dto.AutocompleteItemDto{
+			Id:       city.Id,
+			Type:     dto.AutocompleteCity,
+			Name:     city.Name,
+			Province: city.State,
+		}, city.Name)
+		if city.State != "" {
+			provinces[city.State] = ""
+		}
+	}
+	for _, hotel := range hotels {
+		store.add(dto.AutocompleteItemDto{
+			Id:       hotel.PlaceID,
+			Type:     dto.AutocompleteHotel,
+			Name:     hotel.Name,
+			NameEn:   hotel.NameEn,
+			City:     hotel.City,
+			Province: hotel.Province,
+		}, hotel.Name, hotel.NameEn)
+		if hotel.Province != "" && provinces[hotel.Province] == "" {
+			provinces[hotel.Province] = hotel.ProvinceEn
+		}
+	}
+	for name, nameEn := range provinces {
+		store.add(dto.AutocompleteItemDto{
+			Id:     name,
+			Type:   dto.AutocompleteProvince,
+			Name:   name,
+			NameEn: nameEn,
+		}, name, nameEn)
+	}
+	return store
+}
@@ -4,6 +4,7 @@ import (
 	"hotel-engine/core"
 	"hotel-engine/core/common"
 	"hotel-engine/core/dto"
+	"hotel-engine/utils/persian"
 	"strings"
 )
 
@@ -15,9 +16,16 @@ func (s *cityCacheStore) GetAll() []dto.CityDto {
 	return s.cities
 }
 
+//FindOne prefers the city with the same name, the names are compared after the persian normalization
 func (s *cityCacheStore) FindOne(name string) (dto.CityDto, error) {
+	name = persian.Normalize(name)
 	for _, city := range s.cities {
-		if strings.Contains(city.Name, name) {
+		if persian.Normalize(city.Name) == name {
+			return city, nil
+		}
+	}
+	for _, city := range s.cities {
+		if strings.Contains(persian.Normalize(city.Name), name) {
 			return city, nil
 		}
 	}
@@ -25,9 +33,10 @@ func (s *cityCacheStore) FindOne(name string) (dto.CityDto, error) {
 }
 
 func (s *cityCacheStore) Find(name string) []dto.CityDto {
+	name = persian.Normalize(name)
 	cities := make([]dto.CityDto, 0)
 	for _, city := range s.cities {
-		if strings.Contains(city.Name, name) {
+		if strings.Contains(persian.Normalize(city.Name), name) {
 			cities = append(cities, city)
 		}
 	}
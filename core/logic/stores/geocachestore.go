@@ -0,0 +1,52 @@
+package stores
+
+import (
+	"hotel-engine/core"
+	"hotel-engine/core/dbmodel"
+	"hotel-engine/utils/geo"
+	"math"
+)
+
+type hotelLocation struct {
+	city  string
+	point geo.Point
+}
+
+type geoCacheStore struct {
+	hotels []hotelLocation
+	places map[string]geo.Point
+}
+
+func (s *geoCacheStore) Place(id string) (geo.Point, bool) {
+	point, found := s.places[id]
+	return point, found
+}
+
+//NearestHotelCity returns the city of the hotel closest to the point
+func (s *geoCacheStore) NearestHotelCity(point geo.Point) (string, bool) {
+	city, nearest := "", math.MaxFloat64
+	for _, hotel := range s.hotels {
+		if distance := geo.Distance(point, hotel.point); distance < nearest {
+			city, nearest = hotel.city, distance
+		}
+	}
+	return city, city != ""
+}
+
+func NewGeoCacheStore(hotels []dbmodel.Hotel, places []dbmodel.Place) core.GeoCacheStore {
+	store := &geoCacheStore{
+		hotels: make([]hotelLocation, 0, len(hotels)),
+		places: make(map[string]geo.Point, len(places)),
+	}
+	for _, hotel := range hotels {
+		if point, ok := geo.ParsePoint(hotel.GeoLocation); ok && hotel.City != "" {
+			store.hotels = append(store.hotels, hotelLocation{city: hotel.City, point: point})
+		}
+	}
+	for _, place := range places {
+		if point, ok := geo.ParsePoint(place.GeoLocation); ok {
+			store.places[place.ID] = point
+		}
+	}
+	return store
+}
@@ -1,6 +1,7 @@
 package logic
 
 import (
+	"context"
 	"fmt"
 	"hotel-engine/core"
 	"hotel-engine/core/common/logtags"
@@ -20,7 +21,7 @@ type syncService struct {
 
 func (s *syncService) UpdateDatabase() {
 	logger.Debug("updating database hotels data")
-	res, err := s.service.UpdateAllSync(time.Now())
+	res, err := s.service.UpdateAllSync(context.Background(), time.Now())
 	if err != nil {
 		logger.WithName(logtags.UpdatingHotelsJobError).WithDevMessage("sync hotels job -> updateDatabase").
 			ErrorException(err, "error while updating database hotels")
@@ -45,7 +46,7 @@ func (s *syncService) SyncElastic() {
 			page++
 			continue
 		}
-		err = s.feeder.Feed(mapper(hotels))
+		err = s.feeder.Feed(mapper(hotels, s.hotelCalendars(hotels)))
 		if err != nil {
 			logger.WithName(logtags.FeedElasticError).WithDevMessage("sync hotels service -> syncElastic -> feed").
 				ErrorException(err, "error while feeding elastic")
@@ -57,6 +58,34 @@ func (s *syncService) SyncElastic() {
 	}
 }
 
+//hotelCalendars returns the synced upcoming nights of the hotels by their supplier and place id
+func (s *syncService) hotelCalendars(hotels []dbmodel.Hotel) map[string][]dbmodel.HotelPrice {
+	calendars := make(map[string][]dbmodel.HotelPrice, len(hotels))
+	ids := make(map[string][]string)
+	for _, hotel := range hotels {
+		ids[hotel.Supplier] = append(ids[hotel.Supplier], hotel.PlaceID)
+	}
+	year, month, day := time.Now().Date()
+	for supplier, supplierIds := range ids {
+		days, err := s.unitOfWork.HotelPrice().GetDays(supplierIds, supplier,
+			time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			logger.WithName(logtags.GettingListOfHotelsError).WithDevMessage("sync hotels job -> syncElastic -> calendars").
+				ErrorException(err, "error while getting hotels calendar, the last synced price is used")
+			continue
+		}
+		for _, day := range days {
+			key := calendarKey(day.Supplier, day.PlaceID)
+			calendars[key] = append(calendars[key], day)
+		}
+	}
+	return calendars
+}
+
+func calendarKey(supplier, placeId string) string {
+	return supplier + ":" + placeId
+}
+
 func (s *syncService) UpdateAndSyncElastic() {
 	s.UpdateDatabase()
 	s.SyncElastic()
@@ -71,7 +100,7 @@ func (s *syncService) feed(index string) {
 	}
 }
 
-func mapper(hotels []dbmodel.Hotel) dto.ElasticUpdateRequest {
+func mapper(hotels []dbmodel.Hotel, calendars map[string][]dbmodel.HotelPrice) dto.ElasticUpdateRequest {
 	var docs = []dto.ElasticHotel{}
 	for _, hotel := range hotels {
 
@@ -123,7 +152,9 @@ func mapper(hotels []dbmodel.Hotel) dto.ElasticUpdateRequest {
 		}
 		hotelCalendar := []dto.ElasticCalendar{}
 
-		if hotel.RoomID != "0" {
+		if days, found := calendars[calendarKey(hotel.Supplier, hotel.PlaceID)]; found {
+			hotelCalendar = elasticCalendar(days)
+		} else if hotel.RoomID != "0" {
 			hotelCalendar = append(hotelCalendar, dto.ElasticCalendar{
 				Available: int(date),
 				Date:      int(date),
@@ -195,6 +226,31 @@ func mapper(hotels []dbmodel.Hotel) dto.ElasticUpdateRequest {
 	}
 }
 
+//elasticCalendar has an entry for each synced night, available is the date of the night when it can be reserved
+func elasticCalendar(days []dbmodel.HotelPrice) []dto.ElasticCalendar {
+	calendar := make([]dto.ElasticCalendar, 0, len(days))
+	for _, day := range days {
+		year, month, dayOfMonth := day.Date.Date()
+		date := year*10000 + int(month)*100 + dayOfMonth
+		available := 0
+		if day.Available {
+			available = date
+		}
+		calendar = append(calendar, dto.ElasticCalendar{
+			Available: available,
+			Date:      date,
+			Capacity: dto.ElasticCalendarCapacity{
+				Base: 1,
+			},
+			Price: int(day.Price),
+			Day:   dayOfMonth,
+			Month: int(month),
+			Year:  year,
+		})
+	}
+	return calendar
+}
+
 func (s *syncService) HasBeenSynced() (bool, error) {
 	return s.unitOfWork.Hotel().HasBeenSynced()
 }
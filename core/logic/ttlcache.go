@@ -0,0 +1,119 @@
+package logic
+
+import (
+	"encoding/json"
+	"github.com/go-redis/redis/v8"
+	"hotel-engine/core"
+	"hotel-engine/core/common/logtags"
+	"hotel-engine/infrastructure/config"
+	"hotel-engine/infrastructure/logger"
+	"sync"
+	"time"
+)
+
+const memoryCacheSweepInterval = time.Minute
+
+type memoryCacheEntry struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+//memoryTtlCache keeps the values as json so every reader gets its own copy, the same as the redis cache
+type memoryTtlCache struct {
+	lock    sync.RWMutex
+	entries map[string]memoryCacheEntry
+}
+
+func (c *memoryTtlCache) Get(key string, value interface{}) bool {
+	c.lock.RLock()
+	entry, found := c.entries[key]
+	c.lock.RUnlock()
+	if !found || time.Now().After(entry.expiresAt) {
+		return false
+	}
+	return json.Unmarshal(entry.data, value) == nil
+}
+
+func (c *memoryTtlCache) Set(key string, value interface{}, ttl time.Duration) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	c.lock.Lock()
+	c.entries[key] = memoryCacheEntry{data: data, expiresAt: time.Now().Add(ttl)}
+	c.lock.Unlock()
+}
+
+func (c *memoryTtlCache) Delete(keys ...string) {
+	c.lock.Lock()
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+	c.lock.Unlock()
+}
+
+func (c *memoryTtlCache) sweep() {
+	for now := range time.Tick(memoryCacheSweepInterval) {
+		c.lock.Lock()
+		for key, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, key)
+			}
+		}
+		c.lock.Unlock()
+	}
+}
+
+//redisTtlCache shares the cached values between the instances. redis errors are logged and treated as cache misses
+type redisTtlCache struct {
+	client *redis.Client
+}
+
+func (c *redisTtlCache) Get(key string, value interface{}) bool {
+	data, err := c.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return false
+	}
+	if err != nil {
+		logger.WithName(logtags.TtlCacheError).ErrorException(err, "cannot read from the redis cache")
+		return false
+	}
+	return json.Unmarshal(data, value) == nil
+}
+
+func (c *redisTtlCache) Set(key string, value interface{}, ttl time.Duration) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	if err = c.client.Set(ctx, key, data, ttl).Err(); err != nil {
+		logger.WithName(logtags.TtlCacheError).ErrorException(err, "cannot write to the redis cache")
+	}
+}
+
+func (c *redisTtlCache) Delete(keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	if err := c.client.Del(ctx, keys...).Err(); err != nil {
+		logger.WithName(logtags.TtlCacheError).ErrorException(err, "cannot delete from the redis cache")
+	}
+}
+
+func NewMemoryTtlCache() core.TtlCache {
+	cache := &memoryTtlCache{
+		entries: make(map[string]memoryCacheEntry),
+	}
+	go cache.sweep()
+	return cache
+}
+
+func NewTtlCache() core.TtlCache {
+	con := config.Get()
+	if con.RoomCache.Backend == "memory" {
+		return NewMemoryTtlCache()
+	}
+	return &redisTtlCache{
+		client: newRedisClient(con.MemoryStorageConnection),
+	}
+}
@@ -28,6 +28,12 @@ type Mapper interface {
 	ToOrderRoomDto(model dbmodel.OrderRoom) dto.OrderDetailRoomDto
 	ToOrderModel(item dto.OrderDetailDto) dbmodel.Order
 	ToOrderDto(model dbmodel.Order) dto.OrderDetailDto
+	ToCancellationRuleModel(item dto.CancellationRuleDto) dbmodel.OrderCancellationRule
+	ToCancellationRuleDto(model dbmodel.OrderCancellationRule) dto.CancellationRuleDto
+	ToOrderRefundDto(model dbmodel.OrderRefund) dto.OrderRefundDto
+	ToOrderStateTransitionDto(model dbmodel.OrderStateTransition) dto.OrderStateTransitionDto
+	ToOrderSagaDto(model dbmodel.OrderSaga) dto.OrderSagaDto
+	ToOrderSummaryDto(model dbmodel.Order, hotel *dbmodel.Hotel) dto.OrderSummaryDto
 
 	ToHotelsDetail(hotels []dbmodel.Hotel) *dto.SyncedHotelsDetail
 	ToHotelSyncDetail(hotel dbmodel.Hotel) dto.HotelSyncDetail
@@ -2,6 +2,7 @@ package core
 
 import (
 	"hotel-engine/core/dbmodel"
+	"hotel-engine/core/dto"
 	"time"
 )
 
@@ -13,6 +14,9 @@ type UnitOfWork interface {
 	AmenityCategory() AmenityCategoryRepository
 	Place() PlaceRepository
 	Badge() BadgeRepository
+	HotelPrice() HotelPriceRepository
+	IdempotencyKey() IdempotencyKeyRepository
+	OrderSaga() OrderSagaRepository
 }
 
 type CityRepository interface {
@@ -24,7 +28,14 @@ type OrderRepository interface {
 	Insert(order dbmodel.Order) (*dbmodel.Order, error)
 	GetOneByIndraId(indraId string) (*dbmodel.Order, error)
 	StoreOrUpdate(order dbmodel.Order) error
-	GetProperOrderIdsForRefundUpdateStatus(fromDate time.Time) ([]string, error)
+	UpdateReconciliation(order dbmodel.Order) error
+	GetProperOrderIdsForRefundUpdateStatus(fromDate time.Time, supplier string) ([]string, error)
+	StoreRefund(refund dbmodel.OrderRefund) error
+	GetPendingRefunds(fromDate time.Time, supplier string) ([]dbmodel.OrderRefund, error)
+	GetStateHistory(orderId uint) ([]dbmodel.OrderStateTransition, error)
+	GetOrdersToReconcile(fromDate time.Time, supplier string) ([]dbmodel.Order, error)
+	GetOrdersList(filter dto.OrdersPageRequestDto) ([]dbmodel.Order, int, error)
+	GetOrdersTotals(filter dto.OrdersPageRequestDto) ([]dbmodel.OrderStateTotal, error)
 }
 
 type AmenityRepository interface {
@@ -45,21 +56,46 @@ type AmenityCategoryRepository interface {
 	GetAll() []*dbmodel.AmenityCategory
 }
 
+type HotelPriceRepository interface {
+	StoreDays(placeId, supplier string, today time.Time, days []dbmodel.HotelPrice) error
+	GetDays(placeIds []string, supplier string, from time.Time) ([]dbmodel.HotelPrice, error)
+}
+
+type OrderSagaRepository interface {
+	GetByOrderId(orderId uint) (*dbmodel.OrderSaga, error)
+	StoreOrUpdate(saga *dbmodel.OrderSaga) error
+	GetStuck(before time.Time) ([]dbmodel.OrderSaga, error)
+}
+
+type IdempotencyKeyRepository interface {
+	Get(key string) (*dbmodel.IdempotencyKey, error)
+	Insert(item dbmodel.IdempotencyKey) (*dbmodel.IdempotencyKey, error)
+	StoreOrUpdate(item dbmodel.IdempotencyKey) error
+	Delete(item dbmodel.IdempotencyKey) error
+	Reclaim(item dbmodel.IdempotencyKey, hash string) (*dbmodel.IdempotencyKey, error)
+	Touch(item dbmodel.IdempotencyKey) error
+}
+
 type PlaceRepository interface {
 	GetAll() []dbmodel.Place
 }
 
 type HotelRepository interface {
-	FindByID(hotelId string) (*dbmodel.Hotel, error)
-	FindByIDForSync(hotelId string) (*dbmodel.Hotel, error)
+	FindByID(hotelId, supplier string) (*dbmodel.Hotel, error)
+	FindByIDForSync(hotelId, supplier string) (*dbmodel.Hotel, error)
 	StoreOrUpdate(hotel *dbmodel.Hotel) error
 	Delete(hotel dbmodel.Hotel) error
-	GetAllHotelIds() ([]string, error)
+	GetAllHotelIds(supplier string) ([]string, error)
 	GetHotelsPageForSync(page int, size int) ([]dbmodel.Hotel, error)
-	GetHotels(ids []string) ([]dbmodel.Hotel, error)
-	GetHotel(hotelId string) (*dbmodel.Hotel, error)
+	GetHotels(ids []string, supplier string) ([]dbmodel.Hotel, error)
+	GetHotel(hotelId, supplier string) (*dbmodel.Hotel, error)
 	GetAllHotels() ([]dbmodel.Hotel, error)
+	GetHotelsForCalendar() ([]dbmodel.Hotel, error)
+	SetCalendarSynced(hotelId uint, at time.Time) error
+	GetAllLocations() ([]dbmodel.Hotel, error)
+	GetAllNames() ([]dbmodel.Hotel, error)
 	HasBeenSynced() (bool, error)
 	GetHotelsList(page int, size int, search string) ([]dbmodel.Hotel, int, error)
-	RemoveFAQ(hotelId string, faq *dbmodel.FAQ) (*dbmodel.Hotel, error)
+	SearchCatalogue(search dto.CatalogueSearchDto) ([]dbmodel.Hotel, int, error)
+	RemoveFAQ(hotelId, supplier string, faq *dbmodel.FAQ) (*dbmodel.Hotel, error)
 }
@@ -1,47 +1,58 @@
 package core
 
 import (
+	"context"
 	"hotel-engine/core/dbmodel"
 	"hotel-engine/core/dto"
 	"hotel-engine/infrastructure/hotelproviderinterface/dtos"
+	"hotel-engine/utils/geo"
 	"time"
 )
 
 type HotelService interface {
-	FindHotelById(id string) (*dto.HotelDto, error)
-	GetRoomCancellationPolicy(hotelId, roomId, sessionId string) (*dto.RoomCancellationPolicyDto, error)
-	SearchResult(request dto.SearchDto) (*dto.SearchResponseDto, error)
-
-	UpdateSomeHotels(hotelsDto dto.SyncSomeHotelsDto, date time.Time) (*dto.UpdateResultDto, error)
-	UpdateAllSync(date time.Time) (*dto.UpdateResultDto, error)
-	UpdateAll(date time.Time) (*dto.TaskRunningResult, error)
-
-	SyncAllHotels() (*dto.TaskRunningResult, error)
-	SyncSomeHotels(hotelsDto dto.SyncSomeHotelsDto) (*dto.UpdateResultDto, error)
-	SyncedHotels() (*dto.SyncedHotelsDetail, error)
-	GetHotelRoomsWithSession(dto dto.HotelRoomsWithSessionDto) (*dto.RateRoomResponseDto, error)
-	GetHotelRooms(dto dto.HotelRoomsDto) (*dto.RateRoomResponseDto, error)
-	HotelAvailable(dto dto.AvailableDto) (*dto.AvailableResponseDto, error)
-	FinalizeHotelOrder(dto dto.FinalizeOrderDto) (*dto.FinalizeOrderResponseDto, error)
-	GetHotelDetails(request dto.HotelDetailsDto) (*dto.HotelPDPDto, error)
-	SetAmenityIcon(request dto.SetAmenityIconDto) (dto.HotelAmenityDto, error)
-	GetHotelOptionInfo(infoDto dto.OptionInfoRequestDto) (*dto.OptionInfoResponseDto, error)
-	GetHotels(ids []string) ([]dto.HotelDto, error)
-	GetAnOrderDetail(orderId string) (*dto.OrderDetailDto, error)
-
-	ConfirmOrder(orderId string) (dto.ConfirmResponseDto, error)
-	PayByAccount(orderId string) (dto.OrderPayByAccountResponseDto, error)
-	GetOrderStatus(orderId string) (dto.OrderStatusResponseDto, error)
-	GetOrderEnquiry(orderId string) (dto.OrderEnquiryResponseDto, error)
-	RefundOrder(refundRequest dto.OrderRefundRequestDto) (dto.OrderRefundResponseDto, error)
-	UpdateHotelRateReview(rateDto dto.RateReviewEventDto) error
-	UpdateRefundedOrdersPaymentStatus(date time.Time)
-	SetAmenityCategory(body dto.SetAmenityCategoryDto) (dto.HotelAmenityDto, error)
-
-	GetHotelsList(body dto.HotelsPageRequestDto) (dto.HotelsPageResponseDto, error)
-	SetHotelSeoDetails(body dto.SetHotelSeoRequestDto) (dto.HotelDto, error)
-	SetHotelFaq(requestDto dto.SetHotelFaqRequestDto) (dto.HotelDto, error)
-	RemoveHotelFaq(hotelId string, faqId uint) (dto.HotelDto, error)
+	FindHotelById(ctx context.Context, id, supplier string) (*dto.HotelDto, error)
+	GetRoomCancellationPolicy(ctx context.Context, hotelId, roomId, sessionId,
+		supplier string) (*dto.RoomCancellationPolicyDto, error)
+	SearchResult(ctx context.Context, request dto.SearchDto) (*dto.SearchResponseDto, error)
+
+	UpdateSomeHotels(ctx context.Context, hotelsDto dto.SyncSomeHotelsDto, date time.Time) (*dto.UpdateResultDto, error)
+	UpdateAllSync(ctx context.Context, date time.Time) (*dto.UpdateResultDto, error)
+	UpdateAll(ctx context.Context, date time.Time) (*dto.TaskRunningResult, error)
+
+	SyncAllHotels(ctx context.Context) (*dto.TaskRunningResult, error)
+	SyncSomeHotels(ctx context.Context, hotelsDto dto.SyncSomeHotelsDto) (*dto.UpdateResultDto, error)
+	SyncedHotels(ctx context.Context) (*dto.SyncedHotelsDetail, error)
+	GetHotelRoomsWithSession(ctx context.Context, dto dto.HotelRoomsWithSessionDto) (*dto.RateRoomResponseDto, error)
+	GetHotelRooms(ctx context.Context, dto dto.HotelRoomsDto) (*dto.RateRoomResponseDto, error)
+	GetPriceCalendar(ctx context.Context, request dto.PriceCalendarRequestDto) (*dto.PriceCalendarResponseDto, error)
+	HotelAvailable(ctx context.Context, dto dto.AvailableDto) (*dto.AvailableResponseDto, error)
+	FinalizeHotelOrder(ctx context.Context, dto dto.FinalizeOrderDto) (*dto.FinalizeOrderResponseDto, error)
+	GetHotelDetails(ctx context.Context, request dto.HotelDetailsDto) (*dto.HotelPDPDto, error)
+	SetAmenityIcon(ctx context.Context, request dto.SetAmenityIconDto) (dto.HotelAmenityDto, error)
+	GetHotelOptionInfo(ctx context.Context, infoDto dto.OptionInfoRequestDto) (*dto.OptionInfoResponseDto, error)
+	GetHotels(ctx context.Context, ids []string, supplier string) ([]dto.HotelDto, error)
+	GetAnOrderDetail(ctx context.Context, orderId string) (*dto.OrderDetailDto, error)
+	GetCancellationPenalty(ctx context.Context, orderId string, at time.Time) (*dto.CancellationPenaltyDto, error)
+
+	ConfirmOrder(ctx context.Context, orderId string) (dto.ConfirmResponseDto, error)
+	PayByAccount(ctx context.Context, orderId string) (dto.OrderPayByAccountResponseDto, error)
+	GetOrderStatus(ctx context.Context, orderId string) (dto.OrderStatusResponseDto, error)
+	GetOrderEnquiry(ctx context.Context, orderId string) (dto.OrderEnquiryResponseDto, error)
+	RefundOrder(ctx context.Context, refundRequest dto.OrderRefundRequestDto) (dto.OrderRefundResponseDto, error)
+	PreviewRefund(ctx context.Context, orderId string, referenceCodes []string) (*dto.RefundPreviewDto, error)
+	UpdateHotelRateReview(ctx context.Context, rateDto dto.RateReviewEventDto) error
+	UpdateRefundedOrdersPaymentStatus(ctx context.Context, date time.Time)
+	ResumeOrderSagas(ctx context.Context)
+	GetStuckOrderSagas(ctx context.Context) ([]dto.OrderSagaDto, error)
+	ReconcileOrders(ctx context.Context, fromDate time.Time)
+	SyncHotelCalendars(ctx context.Context)
+	SetAmenityCategory(ctx context.Context, body dto.SetAmenityCategoryDto) (dto.HotelAmenityDto, error)
+
+	GetHotelsList(ctx context.Context, body dto.HotelsPageRequestDto) (dto.HotelsPageResponseDto, error)
+	GetOrdersList(ctx context.Context, body dto.OrdersPageRequestDto) (dto.OrdersPageResponseDto, error)
+	SetHotelSeoDetails(ctx context.Context, body dto.SetHotelSeoRequestDto) (dto.HotelDto, error)
+	SetHotelFaq(ctx context.Context, requestDto dto.SetHotelFaqRequestDto) (dto.HotelDto, error)
+	RemoveHotelFaq(ctx context.Context, hotelId, supplier string, faqId uint) (dto.HotelDto, error)
 }
 
 type PublicService interface {
@@ -53,6 +64,7 @@ type PublicService interface {
 	SyncAllCities() ([]dto.CityDto, error)
 	GetAllPlaces() []dto.HotelPlaceDto
 	GetChildAgeRanges() []dto.ChildAgeRangeDto
+	Autocomplete(query string, limit int) []dto.AutocompleteItemDto
 
 	CreateAmenityCategory(dto dto.AmenityCategoryDto) (dto.AmenityCategoryDto, error)
 	GetAmenityCategory(id uint) (*dto.AmenityCategoryDto, error)
@@ -85,42 +97,65 @@ type AmenityCacheStore interface {
 	Get(id int) (dto.HotelAmenityDto, error)
 }
 
+type GeoCacheStore interface {
+	Place(id string) (geo.Point, bool)
+	NearestHotelCity(point geo.Point) (string, bool)
+}
+
+type AutocompleteCacheStore interface {
+	Search(query string, limit int) []dto.AutocompleteItemDto
+}
+
 type CacheStore interface {
 	AmenityStore() AmenityCacheStore
 	CityStore() CityCacheStore
+	GeoStore() GeoCacheStore
+	AutocompleteStore() AutocompleteCacheStore
 	UpdateStores() error
 	UpdateCityStore()
 	UpdateAmenityStore()
+	UpdateGeoStore()
+	UpdateAutocompleteStore()
 }
 
 type SearchDtoAdopter interface {
 	CreateProviderDto(searchDto dto.SearchDto) (*dtos.ProviderSearchDto, error)
 	CreateResultDto(results []dtos.Result, hotels []dbmodel.Hotel,
 		cityId string, days int) (*dto.SearchResponseDto, error)
+	CreateCatalogueDto(searchDto dto.SearchDto) (*dto.CatalogueSearchDto, error)
+	CreateCatalogueResultDto(hotels []dbmodel.Hotel, days int) *dto.SearchResponseDto
 }
 
 type HotelProvider interface {
-	GetAccessToken() (string, error)
-	GetRoomCancellationPolicy(hotelId, roomId, sessionId string) (*dto.RoomCancellationPolicyDto, error)
-	CreateHotelEnumerable([]dto.CityDto) HotelEnumerable
-	GetHotelData(hotelId string, checkIn, checkout time.Time) (*dto.HotelDto, error)
-	GetHotels(limit, skip uint32) (*dtos.HotelsListResult, error)
-	SearchHotels(limit, skip uint32, hotelGiataId, cityId, hotelName string, cityBaseId int64) (*dtos.HotelsListResult, error)
-	SearchResult(searchDto dtos.ProviderSearchDto) ([]dtos.Result, int, error)
-	DirectHotel(dto dto.HotelRoomsDto) (*dto.DirectResponseDto, error)
-	GetHotelRooms(dto dto.HotelRoomsDto) (*dto.RateRoomResponseDto, error)
-	GetHotelRoomsWithSession(dto dto.HotelRoomsWithSessionDto) (*dto.RateRoomResponseDto, error)
-	HotelAvailable(dto dto.AvailableDto) (*dto.AvailableResponseDto, error)
-	GetOrderDetail(hotelId, sessionId, optionId string) (*dto.OrderDetailDto, error)
-	GetHotelOptionInfo(infoDto dto.OptionInfoRequestDto) (*dto.OptionInfoResponseDto, error)
-
-	ConfirmOrder(orderId string) (dto.ConfirmResponseDto, error)
-	PayByAccount(orderId string) (dto.OrderPayByAccountResponseDto, error)
-	GetOrderStatus(orderId string) (dto.OrderStatusResponseDto, error)
-	GetOrderEnquiry(orderId, providerId string) (dto.OrderEnquiryResponseDto, error)
-	RefundOrder(orderId, referenceCode string) (dto.OrderRefundResponseDto, error)
-	GetHotelType(hotelId string) (string, error)
-	GetOrdersRefundStatus(ids []string, size int, page int) (*dto.OrdersRefundStatusResponseDto, error)
+	GetAccessToken(ctx context.Context) (string, error)
+	GetRoomCancellationPolicy(ctx context.Context, hotelId, roomId, sessionId string) (*dto.RoomCancellationPolicyDto, error)
+	CreateHotelEnumerable(ctx context.Context, cities []dto.CityDto) HotelEnumerable
+	GetHotelData(ctx context.Context, hotelId string, checkIn, checkout time.Time) (*dto.HotelDto, error)
+	GetHotels(ctx context.Context, limit, skip uint32) (*dtos.HotelsListResult, error)
+	SearchHotels(ctx context.Context, limit, skip uint32, hotelGiataId, cityId, hotelName string, cityBaseId int64) (*dtos.HotelsListResult, error)
+	CreateSearchSession(ctx context.Context, data dtos.ProviderRequestSessionDto) (string, error)
+	SearchResult(ctx context.Context, searchDto dtos.ProviderSearchDto) ([]dtos.Result, int, error)
+	DirectHotel(ctx context.Context, dto dto.HotelRoomsDto) (*dto.DirectResponseDto, error)
+	GetHotelRooms(ctx context.Context, dto dto.HotelRoomsDto) (*dto.RateRoomResponseDto, error)
+	GetHotelRoomsWithSession(ctx context.Context, dto dto.HotelRoomsWithSessionDto) (*dto.RateRoomResponseDto, error)
+	HotelAvailable(ctx context.Context, dto dto.AvailableDto) (*dto.AvailableResponseDto, error)
+	GetOrderDetail(ctx context.Context, hotelId, sessionId, optionId string) (*dto.OrderDetailDto, error)
+	GetHotelOptionInfo(ctx context.Context, infoDto dto.OptionInfoRequestDto) (*dto.OptionInfoResponseDto, error)
+
+	ConfirmOrder(ctx context.Context, orderId string) (dto.ConfirmResponseDto, error)
+	PayByAccount(ctx context.Context, orderId string) (dto.OrderPayByAccountResponseDto, error)
+	GetOrderStatus(ctx context.Context, orderId string) (dto.OrderStatusResponseDto, error)
+	GetOrderEnquiry(ctx context.Context, orderId, providerId string) (dto.OrderEnquiryResponseDto, error)
+	RefundOrder(ctx context.Context, orderId string, referenceCodes []string) (dto.OrderRefundResponseDto, error)
+	GetHotelType(ctx context.Context, hotelId string) (string, error)
+	GetOrdersRefundStatus(ctx context.Context, ids []string, size int, page int) (*dto.OrdersRefundStatusResponseDto, error)
+}
+
+type HotelProviderRegistry interface {
+	Register(supplier string, provider HotelProvider) error
+	Get(supplier string) (HotelProvider, error)
+	DefaultSupplier() string
+	Suppliers() []string
 }
 
 type BasicInformationProvider interface {
@@ -146,6 +181,13 @@ type DistributedLocker interface {
 	Lock(key string, duration time.Duration, toDo func()) error
 }
 
+type TtlCache interface {
+	Get(key string, value interface{}) bool
+	Set(key string, value interface{}, ttl time.Duration)
+	Delete(keys ...string)
+}
+
 type OrderEventDispatcher interface {
 	OrderRefundRequestFinalized(event dto.OrderRefundRequestFinalizedDto)
+	OrderReconciled(event dto.OrderReconciledDto)
 }
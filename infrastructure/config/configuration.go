@@ -2,6 +2,7 @@ package config
 
 import (
 	"hotel-engine/utils/date"
+	"hotel-engine/utils/retry"
 	"log"
 	"os"
 	"strconv"
@@ -43,11 +44,80 @@ type Configuration struct {
 	RefundPullingFromDate     time.Time
 	RefundPullingCronTab      string
 	RefundEventTopic          string
+	OrderEventTopic           string
 	RateReviewSubscribeString string
 	OrderServiceEndpoint      string
 	AvailableHotelsWhiteList  []string
 	AvailablePhonesWhiteList  []string
 	TrySyncUntil              int
+	DefaultSupplier           string
+	TokenRefreshMargin        time.Duration
+	ProviderCircuitBreaker    struct {
+		FailureThreshold   int
+		OpenTimeout        time.Duration
+		HalfOpenMaxCalls   int
+		MaxConcurrentCalls int
+	}
+	ProviderTimeouts struct {
+		Http    time.Duration
+		Search  time.Duration
+		Rooms   time.Duration
+		Booking time.Duration
+		Sync    time.Duration
+	}
+	ProviderRetry struct {
+		HotelData    retry.Policy
+		SearchResult retry.Policy
+		HotelRooms   retry.Policy
+		OrderRead    retry.Policy
+	}
+	ProviderRateLimit struct {
+		SyncRate  float64
+		SyncBurst int
+		LiveRate  float64
+		LiveBurst int
+	}
+	RoomCache struct {
+		Backend       string
+		RoomsTtl      time.Duration
+		OptionInfoTtl time.Duration
+	}
+	SearchCache struct {
+		SessionTtl time.Duration
+		PageTtl    time.Duration
+	}
+	SearchFallbackEnabled bool
+	GeoSearchWindow       int
+	SearchFacetWindow     int
+	SyncCalendar          struct {
+		CronTab       string
+		Days          int
+		Concurrency   int
+		RatePerSecond float64
+		RunTime       time.Duration
+	}
+	PriceCalendar struct {
+		Concurrency int
+		MaxDays     int
+		Ttl         time.Duration
+	}
+	RefundQuote struct {
+		Secret string
+		Ttl    time.Duration
+	}
+	IdempotencyKey struct {
+		Ttl        time.Duration
+		StaleAfter time.Duration
+	}
+	OrderSaga struct {
+		CronTab     string
+		MaxAttempts int
+		StaleAfter  time.Duration
+	}
+	OrderReconciliation struct {
+		CronTab string
+		MaxDays int
+	}
 }
 
 func (l Configuration) IsProduction() bool {
@@ -103,6 +173,143 @@ func CreateDefaultConfiguration() Configuration {
 		log.Fatalln("The healthCheckAttempts number is not valid")
 	}
 
+	breakerFailureThreshold, err := strconv.Atoi(os.Getenv("HOTEL_ENGINE_PROVIDER_BREAKER_FAILURE_THRESHOLD"))
+	if err != nil {
+		log.Fatalln("The provider breaker failure threshold number is not valid")
+	}
+
+	breakerOpenTimeoutInSecond, err := strconv.Atoi(os.Getenv("HOTEL_ENGINE_PROVIDER_BREAKER_OPEN_TIMEOUT_IN_SECOND"))
+	if err != nil {
+		log.Fatalln("The provider breaker open timeout number is not valid")
+	}
+
+	breakerHalfOpenMaxCalls, err := strconv.Atoi(os.Getenv("HOTEL_ENGINE_PROVIDER_BREAKER_HALF_OPEN_MAX_CALLS"))
+	if err != nil {
+		log.Fatalln("The provider breaker half open max calls number is not valid")
+	}
+
+	providerMaxConcurrentCalls, err := strconv.Atoi(os.Getenv("HOTEL_ENGINE_PROVIDER_MAX_CONCURRENT_CALLS"))
+	if err != nil {
+		log.Fatalln("The provider max concurrent calls number is not valid")
+	}
+
+	providerHttpTimeout := readDurationInSecond("HOTEL_ENGINE_PROVIDER_HTTP_TIMEOUT_IN_SECOND",
+		"The provider http timeout number is not valid")
+	providerSearchTimeout := readDurationInSecond("HOTEL_ENGINE_PROVIDER_SEARCH_TIMEOUT_IN_SECOND",
+		"The provider search timeout number is not valid")
+	providerRoomsTimeout := readDurationInSecond("HOTEL_ENGINE_PROVIDER_ROOMS_TIMEOUT_IN_SECOND",
+		"The provider rooms timeout number is not valid")
+	providerBookingTimeout := readDurationInSecond("HOTEL_ENGINE_PROVIDER_BOOKING_TIMEOUT_IN_SECOND",
+		"The provider booking timeout number is not valid")
+	providerSyncTimeout := readDurationInSecond("HOTEL_ENGINE_PROVIDER_SYNC_TIMEOUT_IN_SECOND",
+		"The provider sync timeout number is not valid")
+
+	syncRatePerSecond := readRatePerSecond("HOTEL_ENGINE_PROVIDER_SYNC_RATE_PER_SECOND",
+		"The provider sync rate per second number is not valid")
+	syncBurst, err := strconv.Atoi(os.Getenv("HOTEL_ENGINE_PROVIDER_SYNC_BURST"))
+	if err != nil || syncBurst < 1 {
+		log.Fatalln("The provider sync burst number is not valid")
+	}
+	liveRatePerSecond := readRatePerSecond("HOTEL_ENGINE_PROVIDER_LIVE_RATE_PER_SECOND",
+		"The provider live rate per second number is not valid")
+	liveBurst, err := strconv.Atoi(os.Getenv("HOTEL_ENGINE_PROVIDER_LIVE_BURST"))
+	if err != nil || liveBurst < 1 {
+		log.Fatalln("The provider live burst number is not valid")
+	}
+
+	roomCacheBackend := os.Getenv("HOTEL_ENGINE_ROOM_CACHE_BACKEND")
+	if roomCacheBackend != "memory" && roomCacheBackend != "redis" {
+		log.Fatalln("The room cache backend must be memory or redis")
+	}
+	roomsCacheTtl := readDurationInSecond("HOTEL_ENGINE_ROOM_CACHE_ROOMS_TTL_IN_SECOND",
+		"The rooms cache ttl number is not valid")
+	optionInfoCacheTtl := readDurationInSecond("HOTEL_ENGINE_ROOM_CACHE_OPTION_INFO_TTL_IN_SECOND",
+		"The option info cache ttl number is not valid")
+
+	searchSessionTtl := readDurationInSecond("HOTEL_ENGINE_SEARCH_SESSION_TTL_IN_SECOND",
+		"The search session ttl number is not valid")
+	searchPageTtl := readDurationInSecond("HOTEL_ENGINE_SEARCH_PAGE_TTL_IN_SECOND",
+		"The search page ttl number is not valid")
+
+	geoSearchWindow, err := strconv.Atoi(os.Getenv("HOTEL_ENGINE_GEO_SEARCH_WINDOW"))
+	if err != nil || geoSearchWindow < 1 {
+		log.Fatalln("The geo search window number is not valid")
+	}
+
+	searchFacetWindow, err := strconv.Atoi(os.Getenv("HOTEL_ENGINE_SEARCH_FACET_WINDOW"))
+	if err != nil || searchFacetWindow < 1 {
+		log.Fatalln("The search facet window number is not valid")
+	}
+
+	priceCalendarConcurrency, err := strconv.Atoi(os.Getenv("HOTEL_ENGINE_PRICE_CALENDAR_CONCURRENCY"))
+	if err != nil || priceCalendarConcurrency < 1 {
+		log.Fatalln("The price calendar concurrency number is not valid")
+	}
+
+	priceCalendarMaxDays, err := strconv.Atoi(os.Getenv("HOTEL_ENGINE_PRICE_CALENDAR_MAX_DAYS"))
+	if err != nil || priceCalendarMaxDays < 1 {
+		log.Fatalln("The price calendar max days number is not valid")
+	}
+
+	syncCalendarDays, err := strconv.Atoi(os.Getenv("HOTEL_ENGINE_SYNC_CALENDAR_DAYS"))
+	if err != nil || syncCalendarDays < 1 {
+		log.Fatalln("The sync calendar days number is not valid")
+	}
+
+	syncCalendarConcurrency, err := strconv.Atoi(os.Getenv("HOTEL_ENGINE_SYNC_CALENDAR_CONCURRENCY"))
+	if err != nil || syncCalendarConcurrency < 1 {
+		log.Fatalln("The sync calendar concurrency number is not valid")
+	}
+
+	//the calendar has a rate of its own below the sync rate so it never uses up the budget of the hotel sync, a run
+	//stops at its run time and the next run goes on with the hotels that have the oldest calendars
+	syncCalendarRatePerSecond := readRatePerSecond("HOTEL_ENGINE_SYNC_CALENDAR_RATE_PER_SECOND",
+		"The sync calendar rate per second number is not valid")
+	syncCalendarRunTime := readDurationInSecond("HOTEL_ENGINE_SYNC_CALENDAR_RUN_TIME_IN_SECOND",
+		"The sync calendar run time number is not valid")
+
+	priceCalendarTtl := readDurationInSecond("HOTEL_ENGINE_PRICE_CALENDAR_TTL_IN_SECOND",
+		"The price calendar ttl number is not valid")
+
+	refundQuoteSecret := os.Getenv("HOTEL_ENGINE_REFUND_QUOTE_SECRET")
+	if refundQuoteSecret == "" {
+		log.Fatalln("The refund quote secret is not valid")
+	}
+
+	refundQuoteTtl := readDurationInSecond("HOTEL_ENGINE_REFUND_QUOTE_TTL_IN_SECOND",
+		"The refund quote ttl number is not valid")
+
+	idempotencyKeyTtl := readDurationInSecond("HOTEL_ENGINE_IDEMPOTENCY_KEY_TTL_IN_SECOND",
+		"The idempotency key ttl number is not valid")
+
+	idempotencyKeyStaleAfter := readDurationInSecond("HOTEL_ENGINE_IDEMPOTENCY_KEY_STALE_AFTER_IN_SECOND",
+		"The idempotency key stale after number is not valid")
+
+	orderSagaMaxAttempts, err := strconv.Atoi(os.Getenv("HOTEL_ENGINE_ORDER_SAGA_MAX_ATTEMPTS"))
+	if err != nil || orderSagaMaxAttempts < 1 {
+		log.Fatalln("The order saga max attempts number is not valid")
+	}
+
+	orderSagaStaleAfter := readDurationInSecond("HOTEL_ENGINE_ORDER_SAGA_STALE_AFTER_IN_SECOND",
+		"The order saga stale after number is not valid")
+
+	orderReconciliationMaxDays, err := strconv.Atoi(os.Getenv("HOTEL_ENGINE_ORDER_RECONCILIATION_MAX_DAYS"))
+	if err != nil || orderReconciliationMaxDays < 1 {
+		log.Fatalln("The order reconciliation max days number is not valid")
+	}
+
+	providerTokenRefreshMargin := readDurationInSecond("HOTEL_ENGINE_PROVIDER_TOKEN_REFRESH_MARGIN_IN_SECOND",
+		"The provider token refresh margin number is not valid")
+
+	hotelDataRetry := readRetryPolicy("HOTEL_ENGINE_RETRY_HOTEL_DATA",
+		"The hotel data retry policy is not valid")
+	searchResultRetry := readRetryPolicy("HOTEL_ENGINE_RETRY_SEARCH_RESULT",
+		"The search result retry policy is not valid")
+	hotelRoomsRetry := readRetryPolicy("HOTEL_ENGINE_RETRY_HOTEL_ROOMS",
+		"The hotel rooms retry policy is not valid")
+	orderReadRetry := readRetryPolicy("HOTEL_ENGINE_RETRY_ORDER_READ",
+		"The order read retry policy is not valid")
+
 	return Configuration{
 		ContainerName:             os.Getenv("HOTEL_ENGINE_CONTAINER_NAME"),
 		ContainerPort:             outSideOfContainerPort,
@@ -125,6 +332,7 @@ func CreateDefaultConfiguration() Configuration {
 		RefundPullingFromDate:     refundPullingFromDate,
 		RefundPullingMaxTryDays:   refundPullingMaxTryDays,
 		RefundEventTopic:          os.Getenv("HOTEL_ENGINE_REFUND_EVENT_TOPIC"),
+		OrderEventTopic:           os.Getenv("HOTEL_ENGINE_ORDER_EVENT_TOPIC"),
 		ServiceName:               os.Getenv("APP_NAME"),
 		RateReviewSubscribeString: os.Getenv("HOTEL_ENGINE_RATE_REVIEW_SUBSCRIBE_STRING"),
 		BalanceAlertLimit:         balanceLimit,
@@ -133,6 +341,8 @@ func CreateDefaultConfiguration() Configuration {
 		AvailableHotelsWhiteList:  strings.Split(os.Getenv("HOTEL_ENGINE_STAGE_AVAILABLE_HOTELS_WHITE_LIST"), ","),
 		AvailablePhonesWhiteList:  strings.Split(os.Getenv("HOTEL_ENGINE_STAGE_AVAILABLE_PHONES_WHITE_LIST"), ","),
 		TrySyncUntil:              trySyncUntil,
+		DefaultSupplier:           os.Getenv("HOTEL_ENGINE_DEFAULT_SUPPLIER"),
+		TokenRefreshMargin:        providerTokenRefreshMargin,
 		Rabbitmq: struct {
 			ConnectionString string
 			Feeder           struct {
@@ -153,5 +363,147 @@ func CreateDefaultConfiguration() Configuration {
 			},
 		},
 		HealthCheckThresholdInSecond: healthCheckThresholdInSecond,
+		ProviderCircuitBreaker: struct {
+			FailureThreshold   int
+			OpenTimeout        time.Duration
+			HalfOpenMaxCalls   int
+			MaxConcurrentCalls int
+		}{
+			FailureThreshold:   breakerFailureThreshold,
+			OpenTimeout:        time.Duration(breakerOpenTimeoutInSecond) * time.Second,
+			HalfOpenMaxCalls:   breakerHalfOpenMaxCalls,
+			MaxConcurrentCalls: providerMaxConcurrentCalls,
+		},
+		ProviderTimeouts: struct {
+			Http    time.Duration
+			Search  time.Duration
+			Rooms   time.Duration
+			Booking time.Duration
+			Sync    time.Duration
+		}{
+			Http:    providerHttpTimeout,
+			Search:  providerSearchTimeout,
+			Rooms:   providerRoomsTimeout,
+			Booking: providerBookingTimeout,
+			Sync:    providerSyncTimeout,
+		},
+		ProviderRetry: struct {
+			HotelData    retry.Policy
+			SearchResult retry.Policy
+			HotelRooms   retry.Policy
+			OrderRead    retry.Policy
+		}{
+			HotelData:    hotelDataRetry,
+			SearchResult: searchResultRetry,
+			HotelRooms:   hotelRoomsRetry,
+			OrderRead:    orderReadRetry,
+		},
+		ProviderRateLimit: struct {
+			SyncRate  float64
+			SyncBurst int
+			LiveRate  float64
+			LiveBurst int
+		}{
+			SyncRate:  syncRatePerSecond,
+			SyncBurst: syncBurst,
+			LiveRate:  liveRatePerSecond,
+			LiveBurst: liveBurst,
+		},
+		RoomCache: struct {
+			Backend       string
+			RoomsTtl      time.Duration
+			OptionInfoTtl time.Duration
+		}{
+			Backend:       roomCacheBackend,
+			RoomsTtl:      roomsCacheTtl,
+			OptionInfoTtl: optionInfoCacheTtl,
+		},
+		SearchCache: struct {
+			SessionTtl time.Duration
+			PageTtl    time.Duration
+		}{
+			SessionTtl: searchSessionTtl,
+			PageTtl:    searchPageTtl,
+		},
+		SearchFallbackEnabled: os.Getenv("HOTEL_ENGINE_SEARCH_FALLBACK_ENABLED") == "true",
+		GeoSearchWindow:       geoSearchWindow,
+		SearchFacetWindow:     searchFacetWindow,
+		SyncCalendar: struct {
+			CronTab       string
+			Days          int
+			Concurrency   int
+			RatePerSecond float64
+			RunTime       time.Duration
+		}{
+			CronTab:       os.Getenv("HOTEL_ENGINE_SYNC_CALENDAR_CRON_TAB"),
+			Days:          syncCalendarDays,
+			Concurrency:   syncCalendarConcurrency,
+			RatePerSecond: syncCalendarRatePerSecond,
+			RunTime:       syncCalendarRunTime,
+		},
+		PriceCalendar: struct {
+			Concurrency int
+			MaxDays     int
+			Ttl         time.Duration
+		}{
+			Concurrency: priceCalendarConcurrency,
+			MaxDays:     priceCalendarMaxDays,
+			Ttl:         priceCalendarTtl,
+		},
+		RefundQuote: struct {
+			Secret string
+			Ttl    time.Duration
+		}{
+			Secret: refundQuoteSecret,
+			Ttl:    refundQuoteTtl,
+		},
+		IdempotencyKey: struct {
+			Ttl        time.Duration
+			StaleAfter time.Duration
+		}{
+			Ttl:        idempotencyKeyTtl,
+			StaleAfter: idempotencyKeyStaleAfter,
+		},
+		OrderSaga: struct {
+			CronTab     string
+			MaxAttempts int
+			StaleAfter  time.Duration
+		}{
+			CronTab:     os.Getenv("HOTEL_ENGINE_ORDER_SAGA_CRON_TAB"),
+			MaxAttempts: orderSagaMaxAttempts,
+			StaleAfter:  orderSagaStaleAfter,
+		},
+		OrderReconciliation: struct {
+			CronTab string
+			MaxDays int
+		}{
+			CronTab: os.Getenv("HOTEL_ENGINE_ORDER_RECONCILIATION_CRON_TAB"),
+			MaxDays: orderReconciliationMaxDays,
+		},
+	}
+}
+
+func readDurationInSecond(key, invalidMessage string) time.Duration {
+	seconds, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || seconds <= 0 {
+		log.Fatalln(invalidMessage)
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+//readRatePerSecond reads a requests per second rate, zero means unlimited
+func readRatePerSecond(key, invalidMessage string) float64 {
+	rate, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil || rate < 0 {
+		log.Fatalln(invalidMessage)
+	}
+	return rate
+}
+
+func readRetryPolicy(key, invalidMessage string) retry.Policy {
+	policy, err := retry.ParsePolicy(os.Getenv(key))
+	if err != nil {
+		log.Fatalln(invalidMessage, err)
 	}
+	return policy
 }
@@ -0,0 +1,53 @@
+package health
+
+import (
+	"fmt"
+	"hotel-engine/core/common/logtags"
+	"hotel-engine/infrastructure/logger"
+	"hotel-engine/utils/circuitbreaker"
+	"strings"
+)
+
+type circuitBreakerHealthChecker struct {
+	breakers *circuitbreaker.Group
+	tag      string
+}
+
+//Check reports every breaker state. open or half-open breakers degrade the service but do not make it unhealthy
+func (c *circuitBreakerHealthChecker) Check() HealthResultDto {
+	data := map[string]string{}
+	notClosed := make([]string, 0)
+	for _, snapshot := range c.breakers.Snapshots() {
+		data[snapshot.Name] = fmt.Sprintf("%s, failures: %d, in flight: %d",
+			snapshot.State, snapshot.Failures, snapshot.InFlight)
+		if snapshot.State != circuitbreaker.Closed {
+			notClosed = append(notClosed, snapshot.Name)
+		}
+	}
+	if len(notClosed) == 0 {
+		return HealthResultDto{
+			Status:   Healthy,
+			Duration: defaultTimeStampFormat,
+			Data:     data,
+		}
+	}
+	description := "provider circuit breakers are not closed for : " + strings.Join(notClosed, ", ")
+	logger.WithName(logtags.ProviderCircuitBreakerHealthCheck).WithData(data).Warn(description)
+	return HealthResultDto{
+		Status:      Degraded,
+		Duration:    defaultTimeStampFormat,
+		Description: description,
+		Data:        data,
+	}
+}
+
+func (c *circuitBreakerHealthChecker) Tag() string {
+	return c.tag
+}
+
+func NewCircuitBreakerHealthChecker(tag string, breakers *circuitbreaker.Group) Checker {
+	return &circuitBreakerHealthChecker{
+		breakers: breakers,
+		tag:      tag,
+	}
+}
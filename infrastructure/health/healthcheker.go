@@ -4,6 +4,7 @@ type Health string
 
 const Healthy Health = "Healthy"
 const UnHealthy Health = "Unhealthy"
+const Degraded Health = "Degraded"
 
 const defaultTimeStampFormat = "00:00:00.0000000"
 
@@ -60,6 +61,9 @@ func (s *checkerService) GetHealth() map[string]interface{} {
 		if result.health.Status == UnHealthy {
 			resultObject["status"] = UnHealthy
 		}
+		if result.health.Status == Degraded && resultObject["status"] == Healthy {
+			resultObject["status"] = Degraded
+		}
 		entries[result.tag] = result.health
 	}
 	resultObject["entries"] = entries
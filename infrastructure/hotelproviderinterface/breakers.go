@@ -0,0 +1,97 @@
+package hotelProviderInterface
+
+import (
+	"context"
+	"hotel-engine/core/common/logtags"
+	"hotel-engine/infrastructure/config"
+	"hotel-engine/infrastructure/logger"
+	"hotel-engine/utils/circuitbreaker"
+	"net/http"
+	"sync"
+)
+
+var breakers *circuitbreaker.Group
+var breakersOnce sync.Once
+
+//Breakers returns the circuit breakers guarding the provider endpoints, one breaker per host and endpoint
+func Breakers() *circuitbreaker.Group {
+	breakersOnce.Do(func() {
+		c := config.Get().ProviderCircuitBreaker
+		breakers = circuitbreaker.NewGroup(circuitbreaker.Settings{
+			FailureThreshold:   c.FailureThreshold,
+			OpenTimeout:        c.OpenTimeout,
+			HalfOpenMaxCalls:   c.HalfOpenMaxCalls,
+			MaxConcurrentCalls: c.MaxConcurrentCalls,
+		}, logBreakerStateChange)
+	})
+	return breakers
+}
+
+func logBreakerStateChange(name string, from, to circuitbreaker.State) {
+	entry := logger.WithName(logtags.ProviderCircuitBreakerStateChanged).WithData(map[string]interface{}{
+		"endpoint": name,
+		"from":     from,
+		"to":       to,
+	})
+	if to == circuitbreaker.Open {
+		entry.Error("provider circuit breaker opened, calls to the endpoint fail fast")
+		return
+	}
+	entry.Warn("provider circuit breaker state changed")
+}
+
+//do waits for the request budget and sends the request through the endpoint circuit breaker. transport errors, 5xx
+//and 429 responses count as failures, requests cancelled by the caller are ignored
+func (p *hotelProvider) do(endpoint string, req *http.Request) (*http.Response, error) {
+	if err := p.wait(req.Context(), endpoint); err != nil {
+		return nil, err
+	}
+	done, err := p.breakers.Get(req.URL.Host + endpoint).Allow()
+	if err != nil {
+		return nil, err
+	}
+	res, err := p.client.Do(req)
+	switch {
+	case err != nil && req.Context().Err() == context.Canceled:
+		done(circuitbreaker.Ignored)
+	case err != nil || res.StatusCode >= http.StatusInternalServerError ||
+		res.StatusCode == http.StatusTooManyRequests:
+		done(circuitbreaker.Failed)
+	default:
+		done(circuitbreaker.Succeeded)
+	}
+	return res, err
+}
+
+//doAuthenticated sends the request with the access token. when the provider rejects the token it is refreshed once
+//and the request is sent again, the request is rejected before being processed so it is safe to send it again
+func (p *hotelProvider) doAuthenticated(endpoint string, req *http.Request) (*http.Response, error) {
+	token, err := Token()
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	res, err := p.do(endpoint, req)
+	if err != nil || (res.StatusCode != http.StatusUnauthorized && res.StatusCode != http.StatusForbidden) {
+		return res, err
+	}
+	res.Body.Close()
+	logger.WithName(logtags.ProviderTokenRejected).WithData(map[string]interface{}{
+		"endpoint":   endpoint,
+		"statusCode": res.StatusCode,
+	}).Warn("provider rejected the access token, refreshing it")
+
+	retryReq := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retryReq.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	if token, err = RefreshToken(token); err != nil {
+		logger.WithName(logtags.CannotGetAccessTokenError).
+			ErrorException(err, "error in refreshing rejected access token")
+		return nil, err
+	}
+	retryReq.Header.Set("Authorization", "Bearer "+token)
+	return p.do(endpoint, retryReq)
+}
@@ -7,5 +7,6 @@ type AccessTokenResponse struct {
 }
 
 type AccessTokenResult struct {
-	Token string `json:"token"`
+	Token     string `json:"token" schema:"required"`
+	ExpiresIn int64  `json:"expiresIn,omitempty"`
 }
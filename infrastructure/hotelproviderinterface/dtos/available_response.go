@@ -0,0 +1,23 @@
+package dtos
+
+type AvailableResponse struct {
+	BaseResponse
+	Result AvailableResult `json:"result" schema:"required"`
+}
+
+type AvailableResult struct {
+	Id         int64            `json:"id" schema:"required"`
+	TotalPrice int64            `json:"totalPrice" schema:"required"`
+	Details    AvailableDetails `json:"details" schema:"required"`
+}
+
+type AvailableDetails struct {
+	OrderId string               `json:"orderId" schema:"required"`
+	Status  string               `json:"status"`
+	Detail  AvailableDetailDates `json:"detail"`
+}
+
+type AvailableDetailDates struct {
+	CheckIn  string `json:"checkIn"`
+	CheckOut string `json:"checkOut"`
+}
@@ -0,0 +1,39 @@
+package dtos
+
+type OrderEnquiryResponse struct {
+	BaseResponse
+	Result OrderEnquiryResult `json:"result" schema:"required"`
+}
+
+type OrderEnquiryResult struct {
+	AllowedRefundPaymentMethods []string           `json:"allowedRefundPaymentMethods"`
+	Items                       []OrderEnquiryItem `json:"items" schema:"required"`
+}
+
+type OrderEnquiryItem struct {
+	ProviderId          string                   `json:"providerId"`
+	ProductProviderType string                   `json:"productProviderType"`
+	Destination         string                   `json:"destination"`
+	DestinationName     string                   `json:"destinationName"`
+	Items               []OrderEnquiryItemOption `json:"items"`
+}
+
+type OrderEnquiryItemOption struct {
+	ReferenceCode        string                           `json:"referenceCode" schema:"required"`
+	IsRefundable         bool                             `json:"isRefundable"`
+	PaidAmount           int64                            `json:"paidAmount"`
+	TotalPenaltyAmount   int64                            `json:"totalPenaltyAmount"`
+	RefundableAmount     int64                            `json:"refundableAmount"`
+	RefundableType       string                           `json:"refundableType"`
+	RefundableStatus     string                           `json:"refundableStatus"`
+	RefundStatus         string                           `json:"refundStatus"`
+	PassengerInformation OrderEnquiryPassengerInformation `json:"passengerInformation"`
+}
+
+type OrderEnquiryPassengerInformation struct {
+	Title           string `json:"title"`
+	Name            string `json:"name"`
+	LastName        string `json:"lastName"`
+	NamePersian     string `json:"namePersian"`
+	LastNamePersian string `json:"lastNamePersian"`
+}
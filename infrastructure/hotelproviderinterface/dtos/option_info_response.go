@@ -0,0 +1,43 @@
+package dtos
+
+type OptionInfoResponse struct {
+	BaseResponse
+	Result OptionInfoResult `json:"result"`
+}
+
+type OptionInfoResult struct {
+	Detail OptionInfoDetail `json:"detail" schema:"required"`
+	Policy OptionPolicy     `json:"policy"`
+}
+
+type OptionInfoDetail struct {
+	Price            int64                      `json:"price" schema:"required"`
+	Provider         string                     `json:"provider"`
+	Currency         string                     `json:"currency"`
+	MealPlan         string                     `json:"mealPlan"`
+	RestrictedMarkup OptionInfoRestrictedMarkup `json:"restrictedMarkup"`
+	Rooms            []OptionInfoRoom           `json:"rooms"`
+}
+
+type OptionInfoRestrictedMarkup struct {
+	Amount int64  `json:"amount"`
+	Type   string `json:"type"`
+}
+
+type OptionInfoRoom struct {
+	Name string `json:"name"`
+}
+
+type OptionPolicy struct {
+	NonRefundable bool                `json:"nonRefundable" schema:"required"`
+	General       OptionGeneralPolicy `json:"general"`
+}
+
+type OptionGeneralPolicy struct {
+	Policies []string `json:"policies"`
+}
+
+//CancellationPolicyResponse is not wrapped in a result like the other responses
+type CancellationPolicyResponse struct {
+	Policy OptionPolicy `json:"policy" schema:"required"`
+}
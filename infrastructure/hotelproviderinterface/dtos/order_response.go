@@ -0,0 +1,32 @@
+package dtos
+
+type ConfirmOrderResponse struct {
+	BaseResponse
+	Result bool `json:"result" schema:"required"`
+}
+
+type OrderStatusResponse struct {
+	BaseResponse
+	Result string `json:"result" schema:"required"`
+}
+
+type PayByAccountResponse struct {
+	BaseResponse
+	Result PayByAccountResult `json:"result" schema:"required"`
+}
+
+type PayByAccountResult struct {
+	TransactionStatus string   `json:"transactionStatus" schema:"required"`
+	RequestId         string   `json:"requestId"`
+	TransactionIds    []string `json:"transactionIds"`
+	ResultMessage     string   `json:"resultMessage"`
+}
+
+type RefundOrderResponse struct {
+	BaseResponse
+	Result RefundOrderResult `json:"result" schema:"required"`
+}
+
+type RefundOrderResult struct {
+	RefundRequestId int64 `json:"refundRequestId" schema:"required"`
+}
@@ -0,0 +1,79 @@
+package dtos
+
+type RateRoomResponse struct {
+	BaseResponse
+	Result RateRoomResult `json:"result"`
+}
+
+type RateRoomResult struct {
+	FinalResult bool             `json:"finalResult" schema:"required"`
+	Hotel       *RateRoomHotel   `json:"hotel,omitempty"`
+	Rooms       []RateRoomOption `json:"rooms"`
+}
+
+type RateRoomHotel struct {
+	HotelId      string          `json:"hotelId,omitempty"`
+	Id           string          `json:"_id,omitempty"`
+	Name         Name            `json:"name" schema:"required"`
+	Description  HotelText       `json:"description"`
+	Address      string          `json:"address"`
+	CheckinTime  string          `json:"checkinTime"`
+	CheckoutTime string          `json:"checkoutTime"`
+	City         Name            `json:"city"`
+	State        Name            `json:"state"`
+	Country      HotelCountry    `json:"country"`
+	Star         int             `json:"star"`
+	Score        float64         `json:"score"`
+	Location     Location        `json:"location"`
+	Images       []HotelImage    `json:"images"`
+	Facilities   []HotelFacility `json:"facilities"`
+	Places       []HotelPlace    `json:"places"`
+	Badges       []Badge         `json:"badges"`
+}
+
+type HotelText struct {
+	Fa string `json:"fa"`
+}
+
+type HotelCountry struct {
+	En   string `json:"en"`
+	Fa   string `json:"fa"`
+	Code string `json:"code"`
+}
+
+type HotelImage struct {
+	Url string `json:"url"`
+}
+
+type HotelFacility struct {
+	Id      int  `json:"id"`
+	Name    Name `json:"name"`
+	GroupId int  `json:"groupId"`
+}
+
+type HotelPlace struct {
+	Id       string    `json:"_id"`
+	Name     string    `json:"name"`
+	Location []float64 `json:"location"`
+	Distance float64   `json:"distance"`
+	Priority int       `json:"priority"`
+}
+
+type RateRoomOption struct {
+	Id            string         `json:"id" schema:"required"`
+	ProviderName  string         `json:"providerName"`
+	Provider      string         `json:"provider"`
+	NonRefundable bool           `json:"nonRefundable"`
+	MealPlan      string         `json:"mealPlan"`
+	Currency      string         `json:"currency"`
+	Price         int64          `json:"price" schema:"required"`
+	OldPrice      int64          `json:"oldPrice,omitempty"`
+	Rooms         []RateRoomItem `json:"rooms"`
+}
+
+type RateRoomItem struct {
+	Name          string `json:"name"`
+	NameEn        string `json:"name_en"`
+	Price         int64  `json:"price"`
+	PricePerNight int64  `json:"pricePerNight"`
+}
@@ -13,14 +13,16 @@ type RefundItemDto struct {
 	ReferenceCode string `json:"referenceCode"`
 }
 
-func NewRefundRequestDto(orderId, referenceCode string) RefundRequestDto {
+func NewRefundRequestDto(orderId string, referenceCodes []string) RefundRequestDto {
+	items := make([]RefundItemDto, 0, len(referenceCodes))
+	for _, referenceCode := range referenceCodes {
+		items = append(items, RefundItemDto{ReferenceCode: referenceCode})
+	}
 	return RefundRequestDto{
 		RefundRequestType:   "Personal",
 		RefundPaymentMethod: "UserAccount",
 		OrderId:             orderId,
-		Items: []RefundItemDto{
-			{ReferenceCode: referenceCode},
-		},
+		Items:               items,
 	}
 }
 
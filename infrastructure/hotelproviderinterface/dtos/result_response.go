@@ -78,38 +78,6 @@ type Request struct {
 	StayNo      int         `json:"stayNo"`
 	Nationality string      `json:"nationality"`
 }
-type Star struct {
-	Num0 int `json:"0"`
-	Num1 int `json:"1"`
-	Num2 int `json:"2"`
-	Num3 int `json:"3"`
-	Num4 int `json:"4"`
-	Num5 int `json:"5"`
-}
-type Score struct {
-	Num0 int `json:"0"`
-	Num3 int `json:"3"`
-	Num4 int `json:"4"`
-	Num5 int `json:"5"`
-	Num6 int `json:"6"`
-	Num7 int `json:"7"`
-	Num8 int `json:"8"`
-	Num9 int `json:"9"`
-}
-type FacilitiesStat struct {
-	Num1027 int `json:"1027"`
-	Num1048 int `json:"1048"`
-	Num1056 int `json:"1056"`
-	Num1062 int `json:"1062"`
-}
-type Price struct {
-	Num0        int `json:"0"`
-	Num5000000  int `json:"5000000"`
-	Num10000000 int `json:"10000000"`
-}
-type AccommodationsStat struct {
-	Num204 int `json:"204"`
-}
 type Group struct {
 	Name Name `json:"name"`
 }
@@ -125,25 +93,25 @@ type Accommodations struct {
 	Name Name `json:"name"`
 }
 type Info struct {
-	Expire             int                `json:"expire"`
-	Area               []interface{}      `json:"area"`
-	ResultNo           int                `json:"resultNo"`
-	Star               Star               `json:"star"`
-	Score              Score              `json:"score"`
-	FacilitiesStat     FacilitiesStat     `json:"facilitiesStat"`
-	Price              Price              `json:"price"`
-	AccommodationsStat AccommodationsStat `json:"accommodationsStat"`
-	MinPrice           int                `json:"minPrice"`
-	MaxPrice           int                `json:"maxPrice"`
-	Facilities         []Facilities       `json:"facilities"`
-	Accommodations     []Accommodations   `json:"accommodations"`
-	TotalResultNo      int                `json:"totalResultNo"`
+	Expire             int              `json:"expire"`
+	Area               []interface{}    `json:"area"`
+	ResultNo           int              `json:"resultNo"`
+	Star               map[string]int   `json:"star"`
+	Score              map[string]int   `json:"score"`
+	FacilitiesStat     map[string]int   `json:"facilitiesStat"`
+	Price              map[string]int   `json:"price"`
+	AccommodationsStat map[string]int   `json:"accommodationsStat"`
+	MinPrice           int              `json:"minPrice"`
+	MaxPrice           int              `json:"maxPrice"`
+	Facilities         []Facilities     `json:"facilities"`
+	Accommodations     []Accommodations `json:"accommodations"`
+	TotalResultNo      int              `json:"totalResultNo"`
 }
 type Result2 struct {
 	Result        []Result `json:"result"`
 	Request       Request  `json:"request"`
 	Info          Info     `json:"info"`
-	Progress      int      `json:"progress"`
+	Progress      int      `json:"progress" schema:"required"`
 	LastChunk     bool     `json:"lastChunk"`
 	SessionExpire int      `json:"sessionExpire"`
 }
@@ -1,6 +1,7 @@
 package hotelProviderInterface
 
 import (
+	"context"
 	"hotel-engine/core"
 	"hotel-engine/core/common"
 	"hotel-engine/core/dto"
@@ -9,6 +10,7 @@ import (
 )
 
 type hotelEnumerable struct {
+	ctx         context.Context
 	total       int
 	cities      []dto.CityDto
 	current     int
@@ -22,7 +24,7 @@ func (e *hotelEnumerable) MoveNext() bool {
 }
 
 func (e *hotelEnumerable) Current() ([]dto.HotelIdentifierDto, error) {
-	res, err := e.provider.SearchHotels(uint32(e.itemPerPage), 0, "", "",
+	res, err := e.provider.SearchHotels(e.ctx, uint32(e.itemPerPage), 0, "", "",
 		"", e.cities[e.current].BaseId)
 
 	if err != nil {
@@ -52,8 +54,9 @@ func (e *hotelEnumerable) Reset() {
 	e.current = -1
 }
 
-func NewHotelEnumerable(provider core.HotelProvider, cities []dto.CityDto) core.HotelEnumerable {
+func NewHotelEnumerable(ctx context.Context, provider core.HotelProvider, cities []dto.CityDto) core.HotelEnumerable {
 	return &hotelEnumerable{
+		ctx:         ctx,
 		cities:      cities,
 		total:       len(cities),
 		current:     -1,
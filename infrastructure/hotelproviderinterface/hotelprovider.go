@@ -2,6 +2,7 @@ package hotelProviderInterface
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"hotel-engine/core"
@@ -12,7 +13,11 @@ import (
 	"hotel-engine/infrastructure/constants"
 	"hotel-engine/infrastructure/hotelproviderinterface/dtos"
 	"hotel-engine/infrastructure/logger"
+	"hotel-engine/utils/circuitbreaker"
 	"hotel-engine/utils/httphelper"
+	"hotel-engine/utils/ratelimit"
+	"hotel-engine/utils/retry"
+	"hotel-engine/utils/sideeffect"
 	"io/ioutil"
 	"math"
 	"net/http"
@@ -21,8 +26,6 @@ import (
 	"time"
 
 	strip "github.com/grokify/html-strip-tags-go"
-
-	"github.com/tidwall/gjson"
 )
 
 const (
@@ -50,44 +53,69 @@ type hotelProvider struct {
 	baseOrderUrl        string
 	client              *http.Client
 	baseOrderServiceUrl string
+	breakers            *circuitbreaker.Group
+	limiters            map[traffic]*ratelimit.Limiter
+	searchTimeout       time.Duration
+	roomsTimeout        time.Duration
+	bookingTimeout      time.Duration
+	syncTimeout         time.Duration
+	hotelDataRetry      retry.Policy
+	searchResultRetry   retry.Policy
+	hotelRoomsRetry     retry.Policy
+	orderReadRetry      retry.Policy
+}
+
+func (p *hotelProvider) GetAccessToken(ctx context.Context) (string, error) {
+	result, err := p.login(ctx)
+	if err != nil {
+		return "", err
+	}
+	return result.Token, nil
 }
 
-func (p *hotelProvider) GetAccessToken() (string, error) {
+func (p *hotelProvider) login(ctx context.Context) (dtos.AccessTokenResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.bookingTimeout)
+	defer cancel()
 	logger.Debug("trying to get a new token")
 	body, err := json.Marshal(map[string]string{
 		"username": p.username,
 		"password": p.password,
 	})
 	if err != nil {
-		return "", err
+		return dtos.AccessTokenResult{}, err
 	}
-	req, err := http.NewRequest("POST", p.baseHotelUrl+TokenEndpoint, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseHotelUrl+TokenEndpoint, bytes.NewBuffer(body))
 	if err != nil {
-		return "", err
+		return dtos.AccessTokenResult{}, err
 	}
 
 	req.Close = true
 	req.Header.Add("ab-channel", common.ABChannelName)
 	logger.WithName(logtags.GetAccessTokenRequest).WithData(body).Info("Get access token request log")
-	res, err := p.client.Do(req)
+	res, err := p.do(TokenEndpoint, req)
 	if err != nil {
-		return "", err
+		return dtos.AccessTokenResult{}, err
 	}
+	defer res.Body.Close()
 	err = httphelper.GetResponseError(res)
 	if err != nil {
-		return "", err
+		return dtos.AccessTokenResult{}, err
 	}
-	var result dtos.AccessTokenResponse
-	err = json.NewDecoder(res.Body).Decode(&result)
-
+	jsonData, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return "", err
+		return dtos.AccessTokenResult{}, err
+	}
+	var result dtos.AccessTokenResponse
+	if err = decodeResponse(TokenEndpoint, jsonData, &result); err != nil {
+		return dtos.AccessTokenResult{}, err
 	}
 	logger.Debug("new token has been taken successfully")
-	return result.Result.Token, nil
+	return result.Result, nil
 }
 
-func (p *hotelProvider) SearchHotels(limit, skip uint32, hotelGiataId, cityId, hotelName string, cityBaseId int64) (*dtos.HotelsListResult, error) {
+func (p *hotelProvider) SearchHotels(ctx context.Context, limit, skip uint32, hotelGiataId, cityId, hotelName string, cityBaseId int64) (*dtos.HotelsListResult, error) {
+	ctx, cancel := context.WithTimeout(orSyncTraffic(ctx), p.syncTimeout)
+	defer cancel()
 	requestBody := map[string]interface{}{
 		"hotelGiataId": hotelGiataId,
 		"cityId":       cityId,
@@ -103,41 +131,45 @@ func (p *hotelProvider) SearchHotels(limit, skip uint32, hotelGiataId, cityId, h
 		return nil, err
 	}
 	urlParam := fmt.Sprintf("?limit=%d&skip=%d", limit, skip)
-	req, err := http.NewRequest("POST", p.baseHotelUrl+HotelListEndpoint+urlParam, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseHotelUrl+HotelListEndpoint+urlParam, bytes.NewBuffer(body))
 
 	if err != nil {
 		return nil, err
 	}
-	token, err := Token()
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Add("Authorization", "Bearer "+token)
 	req.Header.Add("ab-channel", common.ABChannelName)
 
 	req.Close = true
 
-	res, err := p.client.Do(req)
+	res, err := p.doAuthenticated(HotelListEndpoint, req)
 
 	if err != nil {
 		return nil, err
 	}
-	var result dtos.HotelsListResponse
-	err = json.NewDecoder(res.Body).Decode(&result)
-
+	defer res.Body.Close()
+	err = httphelper.GetResponseError(res)
+	if err != nil {
+		return nil, err
+	}
+	jsonData, err := ioutil.ReadAll(res.Body)
 	if err != nil {
 		return nil, err
 	}
+	var result dtos.HotelsListResponse
+	if err = decodeResponse(HotelListEndpoint, jsonData, &result); err != nil {
+		return nil, err
+	}
 	return &result.Result, nil
 }
 
-func (p *hotelProvider) GetHotels(limit, skip uint32) (*dtos.HotelsListResult, error) {
-	return p.SearchHotels(limit, skip, "", "", "", -1)
+func (p *hotelProvider) GetHotels(ctx context.Context, limit, skip uint32) (*dtos.HotelsListResult, error) {
+	return p.SearchHotels(ctx, limit, skip, "", "", "", -1)
 }
 
-func (p *hotelProvider) GetHotelData(hotelId string, checkIn, checkout time.Time) (*dto.HotelDto, error) {
+func (p *hotelProvider) GetHotelData(ctx context.Context, hotelId string, checkIn, checkout time.Time) (*dto.HotelDto, error) {
+	ctx, cancel := context.WithTimeout(orSyncTraffic(ctx), p.syncTimeout)
+	defer cancel()
 	body := dtos.NewSearchDirectRequest(hotelId, checkIn, checkout).ToJson()
-	req, err := http.NewRequest("POST", p.baseHotelUrl+SearchDirectEndpoint, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseHotelUrl+SearchDirectEndpoint, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
 	}
@@ -145,18 +177,7 @@ func (p *hotelProvider) GetHotelData(hotelId string, checkIn, checkout time.Time
 
 	req.Close = true
 
-	res, err := p.client.Do(req)
-
-	if err != nil {
-		return nil, err
-	}
-	err = httphelper.GetResponseError(res)
-	if err != nil {
-		return nil, err
-	}
-	var searchRes dtos.SearchDirectResponse
-	err = json.NewDecoder(res.Body).Decode(&searchRes)
-
+	searchRes, err := p.searchDirect(req)
 	if err != nil {
 		return nil, err
 	}
@@ -166,58 +187,55 @@ func (p *hotelProvider) GetHotelData(hotelId string, checkIn, checkout time.Time
 		"hotelId":   searchRes.Result.HotelId,
 	})
 
-	delays := []uint{1, 2, 4, 8, 16, 32, 64, 128}
-	maxTry := len(delays)
-	for i := 0; i < maxTry; i++ {
-		req, err = http.NewRequest("POST", p.baseHotelUrl+HotelPriceEndpoint, bytes.NewBuffer(roomReqBody))
+	var hotel *dto.HotelDto
+	err = p.hotelDataRetry.Run(ctx, httphelper.IsRetryableError, func(attempt int) error {
+		req, err := http.NewRequestWithContext(ctx, "POST", p.baseHotelUrl+HotelPriceEndpoint, bytes.NewBuffer(roomReqBody))
 		if err != nil {
-			return nil, err
+			return err
 		}
 		req.Header.Add("ab-channel", common.ABChannelName)
 		req.Close = true
 
-		time.Sleep(time.Duration(delays[i]) * time.Second)
-		res, err = p.client.Do(req)
+		res, err := p.do(HotelPriceEndpoint, req)
 		if err != nil {
-			return nil, err
+			return err
 		}
+		defer res.Body.Close()
 		err = httphelper.GetResponseError(res)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		jsonData, err := ioutil.ReadAll(res.Body)
-
+		rateRoom, err := p.rateRoom(res)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		jsonString := string(jsonData)
-		finalResult := gjson.Get(jsonString, "result.finalResult").Bool()
-		if !finalResult {
-			continue
-		}
-		return getHotelDto(jsonString, checkIn)
+		hotel, err = getHotelDto(rateRoom, checkIn)
+		return err
+	})
+	if err == retry.ErrNotReady {
+		return nil, common.CannotGetHotelDataForSync
 	}
-	return nil, common.CannotGetHotelDataForSync
+	if err != nil {
+		return nil, err
+	}
+	return hotel, nil
 }
 
-func (p *hotelProvider) CreateHotelEnumerable(cities []dto.CityDto) core.HotelEnumerable {
-	return NewHotelEnumerable(p, cities)
+func (p *hotelProvider) CreateHotelEnumerable(ctx context.Context, cities []dto.CityDto) core.HotelEnumerable {
+	return NewHotelEnumerable(ctx, p, cities)
 }
 
-func getHotelDto(jsonString string, date time.Time) (*dto.HotelDto, error) {
-	if !gjson.Valid(jsonString) {
-		logger.WithName(logtags.InvalidJsonResponseError).WithData(map[string]interface{}{
-			"response": jsonString,
-		}).Error(common.JsonDataIsNotValid.Error())
-		return nil, common.JsonDataIsNotValid
+func getHotelDto(result dtos.RateRoomResult, date time.Time) (*dto.HotelDto, error) {
+	if result.Hotel == nil {
+		reportSchemaDrift(HotelPriceEndpoint, "requiredMissing", []string{"result.hotel"})
+		return nil, common.ProviderResponseSchemaChanged
 	}
+	hotel := result.Hotel
+	rooms := result.Rooms
 
-	hotel := gjson.Get(jsonString, "result.hotel")
-	rooms := gjson.Get(jsonString, "result.rooms").Array()
-
-	placeID := hotel.Get("hotelId").String()
+	placeID := hotel.HotelId
 	if placeID == "" {
-		placeID = hotel.Get("_id").String()
+		placeID = hotel.Id
 	}
 
 	price := int64(0)
@@ -226,11 +244,9 @@ func getHotelDto(jsonString string, date time.Time) (*dto.HotelDto, error) {
 	discountPrice := int64(0)
 	roomID := "0"
 	if len(rooms) != 0 {
-		roomID = rooms[0].Get("id").String()
-		price = rooms[0].Get("price").Int()
-		if rooms[0].Get("oldPrice").Exists() {
-			oldPrice = rooms[0].Get("oldPrice").Int()
-		}
+		roomID = rooms[0].Id
+		price = rooms[0].Price
+		oldPrice = rooms[0].OldPrice
 	}
 	if oldPrice != 0 && oldPrice > price {
 		discountPercent = int64(math.Round((float64(oldPrice-price) / float64(oldPrice)) * 100))
@@ -239,56 +255,53 @@ func getHotelDto(jsonString string, date time.Time) (*dto.HotelDto, error) {
 
 	//geoLocation
 	geoLocation := ""
-	coordinates := hotel.Get("location.coordinates").Array()
-	if len(coordinates) == 2 {
-		geoLocation = fmt.Sprintf("%f", coordinates[1].Float()) + "," +
-			fmt.Sprintf("%f", coordinates[0].Float())
+	if len(hotel.Location.Coordinates) == 2 {
+		geoLocation = fmt.Sprintf("%f", hotel.Location.Coordinates[1]) + "," +
+			fmt.Sprintf("%f", hotel.Location.Coordinates[0])
 	}
 
 	//images
 	images := []string{}
-	for _, i := range hotel.Get("images.#.url").Array() {
-		images = append(images, i.String())
+	for _, i := range hotel.Images {
+		images = append(images, i.Url)
 	}
 
 	//amenities
 	amenities := []dto.HotelAmenityDto{}
-	for _, f := range hotel.Get("facilities").Array() {
+	for _, f := range hotel.Facilities {
 		amenities = append(amenities, dto.HotelAmenityDto{
-			ID:      int(f.Get("id").Int()),
-			Name:    f.Get("name.fa").String(),
-			NameEn:  strings.ToLower(f.Get("name.en").String()),
-			GroupId: int(f.Get("groupId").Int()),
+			ID:      f.Id,
+			Name:    f.Name.Fa,
+			NameEn:  strings.ToLower(f.Name.En),
+			GroupId: f.GroupId,
 		})
 	}
 
 	places := make([]dto.HotelPlaceDto, 0)
-	for _, f := range hotel.Get("places").Array() {
+	for _, f := range hotel.Places {
 
 		placeLocation := ""
-		placeLocations := f.Get("location").Array()
-
-		if len(placeLocations) == 2 {
-			placeLocation = fmt.Sprintf("%f", placeLocations[1].Float()) + "," +
-				fmt.Sprintf("%f", placeLocations[0].Float())
+		if len(f.Location) == 2 {
+			placeLocation = fmt.Sprintf("%f", f.Location[1]) + "," +
+				fmt.Sprintf("%f", f.Location[0])
 		}
 		places = append(places, dto.HotelPlaceDto{
-			ID:          f.Get("_id").String(),
-			Name:        f.Get("name").String(),
+			ID:          f.Id,
+			Name:        f.Name,
 			GeoLocation: placeLocation,
-			Distance:    f.Get("distance").Float(),
-			Priority:    int(f.Get("priority").Int()),
+			Distance:    f.Distance,
+			Priority:    f.Priority,
 		})
 	}
 
 	badges := make([]dto.HotelBadgeDto, 0)
-	for _, f := range hotel.Get("badges").Array() {
+	for _, f := range hotel.Badges {
 		badges = append(badges, dto.HotelBadgeDto{
-			ID:              f.Get("_id").String(),
-			Text:            f.Get("text").String(),
-			Icon:            f.Get("icon").String(),
-			TextColor:       f.Get("color.text").String(),
-			BackgroundColor: f.Get("color.background").String(),
+			ID:              f.Id,
+			Text:            f.Text,
+			Icon:            f.Icon,
+			TextColor:       f.Color.Text,
+			BackgroundColor: f.Color.Background,
 		})
 	}
 
@@ -304,19 +317,19 @@ func getHotelDto(jsonString string, date time.Time) (*dto.HotelDto, error) {
 		Tags:            []string{"hotel"},
 		SuitableFor:     []string{},
 		Verified:        true,
-		Name:            hotel.Get("name.fa").String(),
-		NameEn:          hotel.Get("name.en").String(),
-		Description:     strip.StripTags(hotel.Get("description.fa").String()),
+		Name:            hotel.Name.Fa,
+		NameEn:          hotel.Name.En,
+		Description:     strip.StripTags(hotel.Description.Fa),
 		Images:          images,
 		Amenities:       amenities,
 		Places:          places,
-		Address:         hotel.Get("address").String(),
-		CheckInTime:     hotel.Get("checkinTime").String(),
-		CheckOutTime:    hotel.Get("checkoutTime").String(),
-		City:            hotel.Get("city.fa").String(),
-		CityEn:          hotel.Get("city.en").String(),
-		Province:        hotel.Get("state.fa").String(),
-		ProvinceEn:      hotel.Get("state.en").String(),
+		Address:         hotel.Address,
+		CheckInTime:     hotel.CheckinTime,
+		CheckOutTime:    hotel.CheckoutTime,
+		City:            hotel.City.Fa,
+		CityEn:          hotel.City.En,
+		Province:        hotel.State.Fa,
+		ProvinceEn:      hotel.State.En,
 		Capacity:        2,
 		CheckIn:         date,
 		CheckOut:        date.Add(time.Hour * 24),
@@ -324,21 +337,23 @@ func getHotelDto(jsonString string, date time.Time) (*dto.HotelDto, error) {
 		Price:           price,
 		RateReviewCount: 0,
 		RateReviewScore: 0,
-		Star:            int(hotel.Get("star").Int()),
-		Country:         hotel.Get("country.fa").String(),
-		CountryEn:       hotel.Get("country.en").String(),
-		CountryCode:     hotel.Get("country.code").String(),
+		Star:            hotel.Star,
+		Country:         hotel.Country.Fa,
+		CountryEn:       hotel.Country.En,
+		CountryCode:     hotel.Country.Code,
 		OldPrice:        oldPrice,
 		DiscountPercent: discountPercent,
 		DiscountPrice:   discountPrice,
 		Badges:          badges,
-		Sort:            hotel.Get("score").Float(),
+		Sort:            hotel.Score,
 	}, nil
 }
 
-func (p *hotelProvider) SearchResult(searchDto dtos.ProviderSearchDto) ([]dtos.Result, int, error) {
+func (p *hotelProvider) SearchResult(ctx context.Context, searchDto dtos.ProviderSearchDto) ([]dtos.Result, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.searchTimeout)
+	defer cancel()
 	if searchDto.RequestSearchHotels.SessionId == "" {
-		sessionId, err := p.getSearchSessionId(searchDto.RequestSession)
+		sessionId, err := p.getSearchSessionId(ctx, searchDto.RequestSession)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -346,39 +361,55 @@ func (p *hotelProvider) SearchResult(searchDto dtos.ProviderSearchDto) ([]dtos.R
 	}
 
 	body := searchDto.RequestSearchHotels.ToJson()
-	delays := []uint{0, 200, 300, 500, 800, 1300, 2100, 3400}
-	maxTry := len(delays)
-	for i := 0; i < maxTry; i++ {
-		req, err := http.NewRequest("POST", p.baseOrderUrl+SearchResultEndpoint, bytes.NewBuffer(body))
+	var result dtos.ResultResponse
+	err := p.searchResultRetry.Run(ctx, httphelper.IsRetryableError, func(attempt int) error {
+		req, err := http.NewRequestWithContext(ctx, "POST", p.baseOrderUrl+SearchResultEndpoint, bytes.NewBuffer(body))
 		if err != nil {
-			return nil, 0, err
+			return err
 		}
 		req.Header.Add("ab-channel", common.ABChannelName)
 		req.Close = true
 
-		time.Sleep(time.Duration(delays[i]) * time.Millisecond)
-		res, err := p.client.Do(req)
+		res, err := p.do(SearchResultEndpoint, req)
 		if err != nil {
-			return nil, 0, err
+			return err
 		}
+		defer res.Body.Close()
 		if err = httphelper.GetResponseError(res); err != nil {
-			return nil, 0, err
+			return err
 		}
-		var result dtos.ResultResponse
 
-		if err = json.NewDecoder(res.Body).Decode(&result); err != nil {
-			return nil, 0, err
+		jsonData, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+		result = dtos.ResultResponse{}
+		if err = json.Unmarshal(jsonData, &result); err != nil {
+			return decodeResponse(SearchResultEndpoint, jsonData, &result)
 		}
-		if result.Result.Progress >= 100 {
-			return result.Result.Result, result.Result.Info.ResultNo, nil
+		if result.Result.Progress < 100 {
+			return retry.ErrNotReady
 		}
+		return checkResponseSchema(SearchResultEndpoint, jsonData, &result)
+	})
+	if err == retry.ErrNotReady {
+		return nil, 0, common.GetHotelListMaxTryLimitReached
+	}
+	if err != nil {
+		return nil, 0, err
 	}
-	return nil, 0, common.GetHotelListMaxTryLimitReached
+	return result.Result.Result, result.Result.Info.ResultNo, nil
 }
 
-func (p *hotelProvider) getSearchSessionId(data dtos.ProviderRequestSessionDto) (string, error) {
+func (p *hotelProvider) CreateSearchSession(ctx context.Context, data dtos.ProviderRequestSessionDto) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.searchTimeout)
+	defer cancel()
+	return p.getSearchSessionId(ctx, data)
+}
+
+func (p *hotelProvider) getSearchSessionId(ctx context.Context, data dtos.ProviderRequestSessionDto) (string, error) {
 	body := data.ToJson()
-	req, err := http.NewRequest("POST", p.baseOrderUrl+SearchEndpoint, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseOrderUrl+SearchEndpoint, bytes.NewBuffer(body))
 
 	if err != nil {
 		return "", err
@@ -387,33 +418,38 @@ func (p *hotelProvider) getSearchSessionId(data dtos.ProviderRequestSessionDto)
 
 	req.Close = true
 
-	res, err := p.client.Do(req)
+	res, err := p.do(SearchEndpoint, req)
 
 	if err != nil {
 		return "", err
 	}
+	defer res.Body.Close()
 	err = httphelper.GetResponseError(res)
 	if err != nil {
 		return "", err
 	}
 
-	var searchRes dtos.SearchResponse
-	err = json.NewDecoder(res.Body).Decode(&searchRes)
-
+	jsonData, err := ioutil.ReadAll(res.Body)
 	if err != nil {
 		return "", err
 	}
+	var searchRes dtos.SearchResponse
+	if err = decodeResponse(SearchEndpoint, jsonData, &searchRes); err != nil {
+		return "", err
+	}
 	return searchRes.Result.SessionId, nil
 }
 
-func (p *hotelProvider) DirectHotel(data dto.HotelRoomsDto) (*dto.DirectResponseDto, error) {
+func (p *hotelProvider) DirectHotel(ctx context.Context, data dto.HotelRoomsDto) (*dto.DirectResponseDto, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.roomsTimeout)
+	defer cancel()
 	requestModel := dtos.NewDirectRequestDto(data.HotelId,
 		data.CheckIn, data.CheckOut, data.Rooms)
 	body, err := json.Marshal(requestModel)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("POST", p.baseOrderUrl+SearchDirectEndpoint, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseOrderUrl+SearchDirectEndpoint, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
 	}
@@ -421,18 +457,7 @@ func (p *hotelProvider) DirectHotel(data dto.HotelRoomsDto) (*dto.DirectResponse
 
 	req.Close = true
 
-	res, err := p.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	err = httphelper.GetResponseError(res)
-	if err != nil {
-		return nil, err
-	}
-
-	var searchRes dtos.SearchDirectResponse
-	err = json.NewDecoder(res.Body).Decode(&searchRes)
-
+	searchRes, err := p.searchDirect(req)
 	if err != nil {
 		return nil, err
 	}
@@ -444,8 +469,10 @@ func (p *hotelProvider) DirectHotel(data dto.HotelRoomsDto) (*dto.DirectResponse
 	}, nil
 }
 
-func (p *hotelProvider) GetHotelRooms(data dto.HotelRoomsDto) (*dto.RateRoomResponseDto, error) {
-	sessionDto, err := p.DirectHotel(data)
+func (p *hotelProvider) GetHotelRooms(ctx context.Context, data dto.HotelRoomsDto) (*dto.RateRoomResponseDto, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.roomsTimeout)
+	defer cancel()
+	sessionDto, err := p.DirectHotel(ctx, data)
 	if err != nil {
 		return nil, err
 	}
@@ -458,7 +485,7 @@ func (p *hotelProvider) GetHotelRooms(data dto.HotelRoomsDto) (*dto.RateRoomResp
 	if err != nil {
 		return nil, err
 	}
-	rooms, err := p.getHotelRoomsWithDelay(body)
+	rooms, err := p.getHotelRoomsWithDelay(ctx, body)
 	return &dto.RateRoomResponseDto{
 		Rooms:     rooms,
 		HotelId:   data.HotelId,
@@ -466,7 +493,9 @@ func (p *hotelProvider) GetHotelRooms(data dto.HotelRoomsDto) (*dto.RateRoomResp
 		Error:     nil,
 	}, err
 }
-func (p *hotelProvider) GetHotelRoomsWithSession(data dto.HotelRoomsWithSessionDto) (*dto.RateRoomResponseDto, error) {
+func (p *hotelProvider) GetHotelRoomsWithSession(ctx context.Context, data dto.HotelRoomsWithSessionDto) (*dto.RateRoomResponseDto, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.roomsTimeout)
+	defer cancel()
 
 	body, err := json.Marshal(map[string]string{
 		"sessionId": data.SessionId,
@@ -476,7 +505,7 @@ func (p *hotelProvider) GetHotelRoomsWithSession(data dto.HotelRoomsWithSessionD
 	if err != nil {
 		return nil, err
 	}
-	rooms, err := p.getHotelRoomsWithDelay(body)
+	rooms, err := p.getHotelRoomsWithDelay(ctx, body)
 	return &dto.RateRoomResponseDto{
 		Rooms:     rooms,
 		HotelId:   data.HotelId,
@@ -485,8 +514,8 @@ func (p *hotelProvider) GetHotelRoomsWithSession(data dto.HotelRoomsWithSessionD
 	}, err
 }
 
-func (p *hotelProvider) GetHotelType(hotelId string) (string, error) {
-	result, err := p.SearchHotels(1, 0, hotelId, "", "", -1)
+func (p *hotelProvider) GetHotelType(ctx context.Context, hotelId string) (string, error) {
+	result, err := p.SearchHotels(ctx, 1, 0, hotelId, "", "", -1)
 	if err != nil {
 		return common.HotelType_Hotel, err
 	}
@@ -505,7 +534,9 @@ func (p *hotelProvider) GetHotelType(hotelId string) (string, error) {
 	return typeString, nil
 }
 
-func (p *hotelProvider) GetHotelOptionInfo(infoDto dto.OptionInfoRequestDto) (*dto.OptionInfoResponseDto, error) {
+func (p *hotelProvider) GetHotelOptionInfo(ctx context.Context, infoDto dto.OptionInfoRequestDto) (*dto.OptionInfoResponseDto, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.roomsTimeout)
+	defer cancel()
 	data := map[string]string{
 		"sessionId": infoDto.SessionId,
 		"hotelId":   infoDto.HotelId,
@@ -516,7 +547,7 @@ func (p *hotelProvider) GetHotelOptionInfo(infoDto dto.OptionInfoRequestDto) (*d
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("POST", p.baseOrderUrl+HotelOptionInfoEndpoint, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseOrderUrl+HotelOptionInfoEndpoint, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
 	}
@@ -524,7 +555,7 @@ func (p *hotelProvider) GetHotelOptionInfo(infoDto dto.OptionInfoRequestDto) (*d
 	logger.WithName(logtags.GetHotelOptionInfoRequest).WithData(data).Info("Get hotel options info request log")
 	req.Close = true
 
-	res, err := p.client.Do(req)
+	res, err := p.do(HotelOptionInfoEndpoint, req)
 	if err != nil {
 		return nil, err
 	}
@@ -533,157 +564,152 @@ func (p *hotelProvider) GetHotelOptionInfo(infoDto dto.OptionInfoRequestDto) (*d
 		return nil, err
 	}
 
+	defer res.Body.Close()
 	jsonData, err := ioutil.ReadAll(res.Body)
 
 	if err != nil {
 		return nil, err
 	}
-	jsonString := string(jsonData)
-
-	policiesArray := gjson.Get(jsonString, "result.policy.general.policies").Array()
-	policies := make([]string, 0, len(policiesArray))
-	for _, policy := range policiesArray {
-		policies = append(policies, policy.String())
+	var result dtos.OptionInfoResponse
+	if err = decodeResponse(HotelOptionInfoEndpoint, jsonData, &result); err != nil {
+		return nil, err
 	}
 
-	roomsArray := gjson.Get(jsonString, "result.detail.rooms").Array()
-	rooms := make([]dto.OptionInfoDetailRoomDto, 0, len(roomsArray))
-	for _, room := range roomsArray {
+	detail := result.Result.Detail
+	rooms := make([]dto.OptionInfoDetailRoomDto, 0, len(detail.Rooms))
+	for _, room := range detail.Rooms {
 		rooms = append(rooms, dto.OptionInfoDetailRoomDto{
-			Name: room.Get("name").String(),
+			Name: room.Name,
 		})
 	}
 
 	return &dto.OptionInfoResponseDto{
 		Detail: dto.OptionInfoDetailDto{
-			Price:    gjson.Get(jsonString, "result.detail.price").Int(),
+			Price:    detail.Price,
 			HotelId:  infoDto.HotelId,
-			Provider: gjson.Get(jsonString, "result.detail.provider").String(),
-			Currency: gjson.Get(jsonString, "result.detail.currency").String(),
+			Provider: detail.Provider,
+			Currency: detail.Currency,
 			RestrictedMarkup: dto.OptionInfoDetailRestrictedMarkupDto{
-				Amount: gjson.Get(jsonString, "result.detail.restrictedMarkup.amount").Int(),
-				Type:   gjson.Get(jsonString, "result.detail.restrictedMarkup.type").String(),
+				Amount: detail.RestrictedMarkup.Amount,
+				Type:   detail.RestrictedMarkup.Type,
 			},
-			MealPlan: gjson.Get(jsonString, "result.detail.mealPlan").String(),
+			MealPlan: detail.MealPlan,
 			Rooms:    rooms,
 		},
 		Policy: dto.OptionCancellationDto{
-			NonRefundable:   gjson.Get(jsonString, "result.policy.nonRefundable").Bool(),
-			GeneralPolicies: policies,
+			NonRefundable:   result.Result.Policy.NonRefundable,
+			GeneralPolicies: policiesOf(result.Result.Policy),
 		},
 		Error: nil,
-	}, err
+	}, nil
 }
 
-func (p *hotelProvider) getHotelRoomsWithDelay(body []byte) ([]dto.RoomOptionDto, error) {
-	delays := []uint{800, 50, 50, 150, 200, 300}
-	maxTry := len(delays)
-	for i := 0; i < maxTry; i++ {
-		req, err := http.NewRequest("POST", p.baseOrderUrl+HotelPriceEndpoint, bytes.NewBuffer(body))
+func (p *hotelProvider) getHotelRoomsWithDelay(ctx context.Context, body []byte) ([]dto.RoomOptionDto, error) {
+	var roomOptions []dtos.RateRoomOption
+	err := p.hotelRoomsRetry.Run(ctx, httphelper.IsRetryableError, func(attempt int) error {
+		req, err := http.NewRequestWithContext(ctx, "POST", p.baseOrderUrl+HotelPriceEndpoint, bytes.NewBuffer(body))
 		if err != nil {
-			return nil, err
+			return err
 		}
 		req.Header.Add("ab-channel", common.ABChannelName)
 		req.Close = true
 
-		time.Sleep(time.Duration(delays[i]) * time.Millisecond)
-		res, err := p.client.Do(req)
+		res, err := p.do(HotelPriceEndpoint, req)
 		if err != nil {
-			return nil, err
+			return err
 		}
+		defer res.Body.Close()
 		err = httphelper.GetResponseError(res)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		jsonData, err := ioutil.ReadAll(res.Body)
+		rateRoom, err := p.rateRoom(res)
 		if err != nil {
-			return nil, err
-		}
-		jsonString := string(jsonData)
-		finalResult := gjson.Get(jsonString, "result.finalResult").Bool()
-		if !finalResult {
-			continue
+			return err
 		}
-		roomsJsonArray := gjson.Get(jsonString, "result.rooms").Array()
-		if len(roomsJsonArray) == 0 {
-			continue
+		roomOptions = rateRoom.Rooms
+		if len(roomOptions) == 0 {
+			return retry.ErrNotReady
 		}
-		logger.Debug(fmt.Sprintf("rooms data fetched after %d tries", i+1))
-		rooms := make([]dto.RoomOptionDto, 0)
-		for i, r := range roomsJsonArray {
-			oldPrice := int64(0)
-			discountPrice := int64(0)
-			discountPercent := int64(0)
-			price := int64(0)
-			roomList := make([]dto.RoomDto, 0)
-			roomsArray := r.Get("rooms").Array()
-			price = r.Get("price").Int()
-			for j, each := range roomsArray {
-				roomList = append(roomList, dto.RoomDto{
-					ExtraCharge:   make([]interface{}, 0),
-					PricePerNight: each.Get("pricePerNight").Int(),
-					Name:          each.Get("name").String(),
-					NameEn:        each.Get("name_en").String(),
-					Price:         each.Get("price").Int(),
-					Number:        j + 1,
-				})
-			}
-			mealPlan := dto.MealPlans["Unknown"]
-			if val, ok := dto.MealPlans[r.Get("mealPlan").String()]; ok {
-				mealPlan = val
-			}
-
-			if r.Get("oldPrice").Exists() {
-				oldPrice = r.Get("oldPrice").Int()
-			}
-			if oldPrice != 0 && oldPrice > price {
-				discountPercent = int64(math.Round((float64(oldPrice-price) / float64(oldPrice)) * 100))
-				discountPrice = oldPrice - price
-			}
-
-			rooms = append(rooms, dto.RoomOptionDto{
-				Id:              r.Get("id").String(),
-				ProviderName:    r.Get("providerName").String(),
-				Provider:        r.Get("provider").String(),
-				NonRefundable:   r.Get("nonRefundable").Bool(),
-				MealPlan:        mealPlan,
-				Currency:        r.Get("currency").String(),
-				Price:           price,
-				Rooms:           roomList,
-				Number:          i + 1,
-				OldPrice:        oldPrice,
-				DiscountPercent: discountPercent,
-				DiscountPrice:   discountPrice,
+		logger.Debug(fmt.Sprintf("rooms data fetched after %d tries", attempt+1))
+		return nil
+	})
+	if err == retry.ErrNotReady {
+		return []dto.RoomOptionDto{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rooms := make([]dto.RoomOptionDto, 0)
+	for i, r := range roomOptions {
+		discountPrice := int64(0)
+		discountPercent := int64(0)
+		price := r.Price
+		oldPrice := r.OldPrice
+		roomList := make([]dto.RoomDto, 0)
+		for j, each := range r.Rooms {
+			roomList = append(roomList, dto.RoomDto{
+				ExtraCharge:   make([]interface{}, 0),
+				PricePerNight: each.PricePerNight,
+				Name:          each.Name,
+				NameEn:        each.NameEn,
+				Price:         each.Price,
+				Number:        j + 1,
 			})
 		}
-		return rooms, nil
+		mealPlan := dto.MealPlans["Unknown"]
+		if val, ok := dto.MealPlans[r.MealPlan]; ok {
+			mealPlan = val
+		}
+
+		if oldPrice != 0 && oldPrice > price {
+			discountPercent = int64(math.Round((float64(oldPrice-price) / float64(oldPrice)) * 100))
+			discountPrice = oldPrice - price
+		}
+
+		rooms = append(rooms, dto.RoomOptionDto{
+			Id:              r.Id,
+			ProviderName:    r.ProviderName,
+			Provider:        r.Provider,
+			NonRefundable:   r.NonRefundable,
+			MealPlan:        mealPlan,
+			Currency:        r.Currency,
+			Price:           price,
+			Rooms:           roomList,
+			Number:          i + 1,
+			OldPrice:        oldPrice,
+			DiscountPercent: discountPercent,
+			DiscountPrice:   discountPrice,
+		})
 	}
-	return []dto.RoomOptionDto{}, nil
+	return rooms, nil
 }
 
-func (p *hotelProvider) HotelAvailable(data dto.AvailableDto) (*dto.AvailableResponseDto, error) {
+func (p *hotelProvider) HotelAvailable(ctx context.Context, data dto.AvailableDto) (*dto.AvailableResponseDto, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.bookingTimeout)
+	defer cancel()
+	//the supplier routes the request in the engine and is not a field of the supplier request
+	data.Supplier = ""
 	body, err := json.Marshal(data)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("POST", p.baseOrderUrl+AvailableEndpoint, bytes.NewBuffer(body))
-	if err != nil {
-		return nil, err
-	}
-	token, err := Token()
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseOrderUrl+AvailableEndpoint, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Add("Authorization", "Bearer "+token)
 	req.Header.Add("ab-channel", common.ABChannelName)
 	logger.WithName(logtags.HotelAvailableRequest).WithData(data).Info("hotel available request log")
 	req.Close = true
+	sideeffect.Mark(ctx)
 
-	res, err := p.client.Do(req)
+	res, err := p.doAuthenticated(AvailableEndpoint, req)
 	if err != nil {
 		return nil, err
 	}
+	defer res.Body.Close()
 	err = httphelper.GetResponseError(res)
 	if err != nil {
 		return nil, err
@@ -693,22 +719,28 @@ func (p *hotelProvider) HotelAvailable(data dto.AvailableDto) (*dto.AvailableRes
 	if err != nil {
 		return nil, err
 	}
-	jsonString := string(jsonData)
+	var available dtos.AvailableResponse
+	if err = decodeResponse(AvailableEndpoint, jsonData, &available); err != nil {
+		return nil, err
+	}
+	result := available.Result
 
 	return &dto.AvailableResponseDto{
-		OrderId:      gjson.Get(jsonString, "result.details.orderId").String(),
-		TotalPrice:   gjson.Get(jsonString, "result.totalPrice").Int(),
-		Status:       gjson.Get(jsonString, "result.details.status").String(),
-		IndraOrderId: gjson.Get(jsonString, "result.id").Int(),
-		CheckIn:      gjson.Get(jsonString, "result.details.detail.checkIn").String(),
-		CheckOut:     gjson.Get(jsonString, "result.details.detail.checkOut").String(),
+		OrderId:      result.Details.OrderId,
+		TotalPrice:   result.TotalPrice,
+		Status:       result.Details.Status,
+		IndraOrderId: result.Id,
+		CheckIn:      result.Details.Detail.CheckIn,
+		CheckOut:     result.Details.Detail.CheckOut,
 		OptionId:     data.OptionId,
 		Error:        nil,
 	}, nil
 }
 
-func (p *hotelProvider) GetOrderDetail(hotelId, sessionId, optionId string) (*dto.OrderDetailDto, error) {
-	info, err := p.GetHotelOptionInfo(dto.OptionInfoRequestDto{
+func (p *hotelProvider) GetOrderDetail(ctx context.Context, hotelId, sessionId, optionId string) (*dto.OrderDetailDto, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.roomsTimeout)
+	defer cancel()
+	info, err := p.GetHotelOptionInfo(ctx, dto.OptionInfoRequestDto{
 		OptionId:  optionId,
 		HotelId:   hotelId,
 		SessionId: sessionId,
@@ -716,7 +748,7 @@ func (p *hotelProvider) GetOrderDetail(hotelId, sessionId, optionId string) (*dt
 	if err != nil {
 		return nil, err
 	}
-	roomsList, err := p.GetHotelRoomsWithSession(dto.HotelRoomsWithSessionDto{
+	roomsList, err := p.GetHotelRoomsWithSession(ctx, dto.HotelRoomsWithSessionDto{
 		HotelId:   hotelId,
 		SessionId: sessionId,
 	})
@@ -761,7 +793,9 @@ func (p *hotelProvider) GetOrderDetail(hotelId, sessionId, optionId string) (*dt
 	}, nil
 }
 
-func (p *hotelProvider) GetRoomCancellationPolicy(hotelId, roomId, sessionId string) (*dto.RoomCancellationPolicyDto, error) {
+func (p *hotelProvider) GetRoomCancellationPolicy(ctx context.Context, hotelId, roomId, sessionId string) (*dto.RoomCancellationPolicyDto, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.roomsTimeout)
+	defer cancel()
 	body, err := json.Marshal(map[string]string{
 		"hotelId":   hotelId,
 		"optionId":  roomId,
@@ -770,16 +804,17 @@ func (p *hotelProvider) GetRoomCancellationPolicy(hotelId, roomId, sessionId str
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("POST", p.baseOrderUrl+RoomCancellationPolicy, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseOrderUrl+RoomCancellationPolicy, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Add("ab-channel", common.ABChannelName)
 	req.Close = true
-	res, err := p.client.Do(req)
+	res, err := p.do(RoomCancellationPolicy, req)
 	if err != nil {
 		return nil, err
 	}
+	defer res.Body.Close()
 	err = httphelper.GetResponseError(res)
 	if err != nil {
 		logger.WithName(logtags.GettingCancellationPolicyError).ErrorException(err, "error while trying to get cancellation policy")
@@ -789,32 +824,26 @@ func (p *hotelProvider) GetRoomCancellationPolicy(hotelId, roomId, sessionId str
 	if err != nil {
 		return nil, err
 	}
-	jsonString := string(jsonData)
-	if !gjson.Valid(jsonString) {
-		logger.WithName(logtags.InvalidJsonResponseError).WithData(map[string]interface{}{
-			"response": jsonString,
-		}).Error(common.JsonDataIsNotValid.Error())
-		return nil, common.JsonDataIsNotValid
-	}
-	policiesArray := gjson.Get(jsonString, "policy.general.policies").Array()
-	policies := make([]string, 0, len(policiesArray))
-	for _, policy := range policiesArray {
-		policies = append(policies, policy.String())
+	var result dtos.CancellationPolicyResponse
+	if err = decodeResponse(RoomCancellationPolicy, jsonData, &result); err != nil {
+		return nil, err
 	}
 
-	nonRefundable := gjson.Get(jsonString, "policy.nonRefundable").Bool()
-	if nonRefundable {
+	policies := policiesOf(result.Policy)
+	if result.Policy.NonRefundable {
 		policies = []string{common.NonRefundableDefaultMessage}
 	}
 	return &dto.RoomCancellationPolicyDto{
-		NonRefundable:   nonRefundable,
+		NonRefundable:   result.Policy.NonRefundable,
 		GeneralPolicies: policies,
 		Error:           nil,
 	}, nil
 }
 
-func (p *hotelProvider) ConfirmOrder(orderId string) (dto.ConfirmResponseDto, error) {
-	req, err := http.NewRequest("POST", p.baseOrderUrl+
+func (p *hotelProvider) ConfirmOrder(ctx context.Context, orderId string) (dto.ConfirmResponseDto, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.bookingTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseOrderUrl+
 		strings.Replace(ConfirmOrderEndPoint, "{orderId}", orderId, 1), nil)
 	if err != nil {
 		return dto.ConfirmResponseDto{}, err
@@ -824,12 +853,14 @@ func (p *hotelProvider) ConfirmOrder(orderId string) (dto.ConfirmResponseDto, er
 		"orderId": orderId,
 	}).Info("Confirm order request log")
 	req.Close = true
+	sideeffect.Mark(ctx)
 
-	stringData, err := p.requestToUrl(req, true)
+	var result dtos.ConfirmOrderResponse
+	err = p.requestToUrl(ConfirmOrderEndPoint, req, true, &result)
 	if err != nil {
 		return dto.ConfirmResponseDto{}, err
 	}
-	if gjson.Get(stringData, "result").Bool() {
+	if result.Result {
 		return dto.ConfirmResponseDto{
 			OrderId: orderId,
 			Error:   nil,
@@ -838,9 +869,11 @@ func (p *hotelProvider) ConfirmOrder(orderId string) (dto.ConfirmResponseDto, er
 	return dto.ConfirmResponseDto{}, common.ErrorInConfirmingOrder
 }
 
-func (p *hotelProvider) PayByAccount(orderId string) (dto.OrderPayByAccountResponseDto, error) {
+func (p *hotelProvider) PayByAccount(ctx context.Context, orderId string) (dto.OrderPayByAccountResponseDto, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.bookingTimeout)
+	defer cancel()
 	data := dtos.NewPayByAccountRequest("")
-	req, err := http.NewRequest("POST", p.baseOrderUrl+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseOrderUrl+
 		strings.Replace(PayByBankAndAccountEndpoint, "{orderId}", orderId, 1), bytes.NewBuffer(data.ToJson()))
 	if err != nil {
 		return dto.OrderPayByAccountResponseDto{}, err
@@ -853,87 +886,89 @@ func (p *hotelProvider) PayByAccount(orderId string) (dto.OrderPayByAccountRespo
 		"reqBody": data,
 	}).Info("Pay by account request log")
 	req.Close = true
+	sideeffect.Mark(ctx)
 
-	stringData, err := p.requestToUrl(req, true)
+	var response dtos.PayByAccountResponse
+	err = p.requestToUrl(PayByBankAndAccountEndpoint, req, true, &response)
 	if err != nil {
 		return dto.OrderPayByAccountResponseDto{}, err
 	}
-	result := gjson.Get(stringData, "result")
-	transactionIds := make([]string, 0)
-	for _, item := range result.Get("transactionIds").Array() {
-		transactionIds = append(transactionIds, item.String())
-	}
+	result := response.Result
+	transactionIds := make([]string, 0, len(result.TransactionIds))
+	transactionIds = append(transactionIds, result.TransactionIds...)
 
 	return dto.OrderPayByAccountResponseDto{
-		TransactionStatus: result.Get("transactionStatus").String(),
-		RequestId:         result.Get("requestId").String(),
+		TransactionStatus: result.TransactionStatus,
+		RequestId:         result.RequestId,
 		TransactionIds:    transactionIds,
-		ResultMessage:     result.Get("resultMessage").String(),
+		ResultMessage:     result.ResultMessage,
 		Error:             nil,
 	}, nil
 }
 
-func (p *hotelProvider) GetOrderStatus(orderId string) (dto.OrderStatusResponseDto, error) {
-	stringData, err := p.getFromUrl(p.baseOrderUrl+
+func (p *hotelProvider) GetOrderStatus(ctx context.Context, orderId string) (dto.OrderStatusResponseDto, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.bookingTimeout)
+	defer cancel()
+	var result dtos.OrderStatusResponse
+	err := p.getFromUrl(ctx, GetOrderStatusEndpoint, p.baseOrderUrl+
 		strings.Replace(GetOrderStatusEndpoint, "{orderId}", orderId, 1),
-		true)
+		true, &result)
 	if err != nil {
 		return dto.OrderStatusResponseDto{}, err
 	}
-	status := gjson.Get(stringData, "result").String()
 	return dto.OrderStatusResponseDto{
 		OrderId: orderId,
-		Status:  status,
+		Status:  result.Result,
 		Error:   nil,
 	}, nil
 }
 
-func (p *hotelProvider) GetOrderEnquiry(orderId, providerId string) (dto.OrderEnquiryResponseDto, error) {
+func (p *hotelProvider) GetOrderEnquiry(ctx context.Context, orderId, providerId string) (dto.OrderEnquiryResponseDto, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.bookingTimeout)
+	defer cancel()
 	parameters := "?providerId=" + providerId
 
 	logger.WithName(logtags.GetOrderEnquiryRequest).WithData(map[string]interface{}{
 		"orderId": orderId,
 	}).Info("Get order enquiry request log")
 
-	stringData, err := p.getFromUrl(p.baseOrderUrl+
+	var response dtos.OrderEnquiryResponse
+	err := p.getFromUrl(ctx, EnquiryOrderEndpoint, p.baseOrderUrl+
 		strings.Replace(EnquiryOrderEndpoint, "{orderId}", orderId, 1)+
-		parameters, true)
+		parameters, true, &response)
 	if err != nil {
 		return dto.OrderEnquiryResponseDto{}, err
 	}
-	result := gjson.Get(stringData, "result")
-	allowedRefundPaymentMethods := make([]string, 0)
-	for _, item := range result.Get("allowedRefundPaymentMethods").Array() {
-		allowedRefundPaymentMethods = append(allowedRefundPaymentMethods, item.String())
-	}
-	//amenities
+	result := response.Result
+	allowedRefundPaymentMethods := make([]string, 0, len(result.AllowedRefundPaymentMethods))
+	allowedRefundPaymentMethods = append(allowedRefundPaymentMethods, result.AllowedRefundPaymentMethods...)
 	var items []dto.OrderEnquiryItemDto
-	for _, f := range result.Get("items").Array() {
+	for _, f := range result.Items {
 		var itemOptions []dto.OrderEnquiryItemOptionDto
-		for _, o := range f.Get("items").Array() {
+		for _, o := range f.Items {
 			itemOptions = append(itemOptions, dto.OrderEnquiryItemOptionDto{
-				ReferenceCode:      o.Get("referenceCode").String(),
-				IsRefundable:       o.Get("isRefundable").Bool(),
-				PaidAmount:         o.Get("paidAmount").Int(),
-				TotalPenaltyAmount: o.Get("totalPenaltyAmount").Int(),
-				RefundableAmount:   o.Get("refundableAmount").Int(),
-				RefundableType:     o.Get("refundableType").String(),
-				RefundableStatus:   o.Get("refundableStatus").String(),
-				RefundStatus:       o.Get("refundStatus").String(),
+				ReferenceCode:      o.ReferenceCode,
+				IsRefundable:       o.IsRefundable,
+				PaidAmount:         o.PaidAmount,
+				TotalPenaltyAmount: o.TotalPenaltyAmount,
+				RefundableAmount:   o.RefundableAmount,
+				RefundableType:     o.RefundableType,
+				RefundableStatus:   o.RefundableStatus,
+				RefundStatus:       o.RefundStatus,
 				PassengerInformation: dto.OrderEnquiryItemOptionPassengerInformation{
-					Title:           o.Get("passengerInformation.title").String(),
-					Name:            o.Get("passengerInformation.name").String(),
-					LastName:        o.Get("passengerInformation.lastName").String(),
-					NamePersian:     o.Get("passengerInformation.namePersian").String(),
-					LastNamePersian: o.Get("passengerInformation.lastNamePersian").String(),
+					Title:           o.PassengerInformation.Title,
+					Name:            o.PassengerInformation.Name,
+					LastName:        o.PassengerInformation.LastName,
+					NamePersian:     o.PassengerInformation.NamePersian,
+					LastNamePersian: o.PassengerInformation.LastNamePersian,
 				},
 			})
 		}
 		items = append(items, dto.OrderEnquiryItemDto{
-			ProviderId:          f.Get("providerId").String(),
-			ProductProviderType: f.Get("productProviderType").String(),
-			Destination:         f.Get("destination").String(),
-			DestinationName:     f.Get("destinationName").String(),
+			ProviderId:          f.ProviderId,
+			ProductProviderType: f.ProductProviderType,
+			Destination:         f.Destination,
+			DestinationName:     f.DestinationName,
 			Items:               itemOptions,
 		})
 	}
@@ -944,33 +979,43 @@ func (p *hotelProvider) GetOrderEnquiry(orderId, providerId string) (dto.OrderEn
 	}, nil
 }
 
-func (p *hotelProvider) GetOrdersRefundStatus(ids []string, size int, page int) (*dto.OrdersRefundStatusResponseDto, error) {
+func (p *hotelProvider) GetOrdersRefundStatus(ctx context.Context, ids []string, size int, page int) (*dto.OrdersRefundStatusResponseDto, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.bookingTimeout)
+	defer cancel()
 	parameters := fmt.Sprintf("?page_no=%d&page_size=%d", page, size)
 	for _, id := range ids {
 		parameters += "&orderIds=" + id
 	}
-	req, err := http.NewRequest("GET", p.baseOrderServiceUrl+OrdersRefundStatusEndpoint+parameters, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Add("ab-channel", common.ABChannelName)
-
 	logger.WithName(logtags.GetOrdersRefundStatusRequest).WithData(map[string]interface{}{
 		"ids": ids,
 	}).Info("Get orders refund status request log")
-	req.Close = true
-
-	res, err := p.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	err = httphelper.GetResponseError(res)
-	if err != nil {
-		return nil, err
-	}
 
 	var result dto.OrdersRefundStatusResponseDto
-	err = json.NewDecoder(res.Body).Decode(&result)
+	err := p.orderReadRetry.Run(ctx, httphelper.IsRetryableError, func(attempt int) error {
+		req, err := http.NewRequestWithContext(ctx, "GET", p.baseOrderServiceUrl+OrdersRefundStatusEndpoint+parameters, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Add("ab-channel", common.ABChannelName)
+		req.Close = true
+
+		res, err := p.do(OrdersRefundStatusEndpoint, req)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		err = httphelper.GetResponseError(res)
+		if err != nil {
+			return err
+		}
+
+		jsonData, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+		result = dto.OrdersRefundStatusResponseDto{}
+		return decodeResponse(OrdersRefundStatusEndpoint, jsonData, &result)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -978,9 +1023,11 @@ func (p *hotelProvider) GetOrdersRefundStatus(ids []string, size int, page int)
 	return &result, nil
 }
 
-func (p *hotelProvider) RefundOrder(orderId, referenceCode string) (dto.OrderRefundResponseDto, error) {
-	body := dtos.NewRefundRequestDto(orderId, referenceCode)
-	req, err := http.NewRequest("POST", p.baseOrderUrl+OrderRefundEndpoint, bytes.NewBuffer(body.ToJson()))
+func (p *hotelProvider) RefundOrder(ctx context.Context, orderId string, referenceCodes []string) (dto.OrderRefundResponseDto, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.bookingTimeout)
+	defer cancel()
+	body := dtos.NewRefundRequestDto(orderId, referenceCodes)
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseOrderUrl+OrderRefundEndpoint, bytes.NewBuffer(body.ToJson()))
 	if err != nil {
 		return dto.OrderRefundResponseDto{}, err
 	}
@@ -992,66 +1039,109 @@ func (p *hotelProvider) RefundOrder(orderId, referenceCode string) (dto.OrderRef
 		"reqBody": body,
 	}).Info("Refund order request log")
 	req.Close = true
+	sideeffect.Mark(ctx)
 
-	stringData, err := p.requestToUrl(req, true)
+	var result dtos.RefundOrderResponse
+	err = p.requestToUrl(OrderRefundEndpoint, req, true, &result)
 	if err != nil {
 		return dto.OrderRefundResponseDto{}, err
 	}
 
-	result := gjson.Get(stringData, "result")
-
 	return dto.OrderRefundResponseDto{
 		OrderId:         orderId,
-		RefundRequestId: result.Get("refundRequestId").Int(),
+		RefundRequestId: result.Result.RefundRequestId,
 		Error:           nil,
 	}, nil
 }
 
-func (p *hotelProvider) getFromUrl(url string, needAuthentication bool) (string, error) {
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return "", err
-	}
-	req.Header.Add("ab-channel", common.ABChannelName)
-	req.Close = true
+//getFromUrl reads the url into v, get requests are idempotent so they are retried by the order read policy
+func (p *hotelProvider) getFromUrl(ctx context.Context, endpoint, url string, needAuthentication bool, v interface{}) error {
+	return p.orderReadRetry.Run(ctx, httphelper.IsRetryableError, func(attempt int) error {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Add("ab-channel", common.ABChannelName)
+		req.Close = true
 
-	return p.requestToUrl(req, needAuthentication)
+		return p.requestToUrl(endpoint, req, needAuthentication, v)
+	})
 }
 
-func (p *hotelProvider) requestToUrl(req *http.Request, needAuthentication bool) (string, error) {
+func (p *hotelProvider) requestToUrl(endpoint string, req *http.Request, needAuthentication bool, v interface{}) error {
+	var res *http.Response
+	var err error
 	if needAuthentication {
-		token, err := Token()
-		if err != nil {
-			return "", err
-		}
-		req.Header.Add("Authorization", "Bearer "+token)
+		res, err = p.doAuthenticated(endpoint, req)
+	} else {
+		res, err = p.do(endpoint, req)
 	}
-	res, err := p.client.Do(req)
 	if err != nil {
-		return "", err
+		return err
 	}
+	defer res.Body.Close()
 
 	err = httphelper.GetResponseError(res)
 	if err != nil {
-		return "", err
+		return err
 	}
 
 	jsonData, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return "", err
+		return err
 	}
+	return decodeResponse(endpoint, jsonData, v)
+}
 
-	jsonString := string(jsonData)
+//searchDirect opens a session for a single hotel
+func (p *hotelProvider) searchDirect(req *http.Request) (dtos.SearchDirectResponse, error) {
+	res, err := p.do(SearchDirectEndpoint, req)
+	if err != nil {
+		return dtos.SearchDirectResponse{}, err
+	}
+	defer res.Body.Close()
+	err = httphelper.GetResponseError(res)
+	if err != nil {
+		return dtos.SearchDirectResponse{}, err
+	}
+	jsonData, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return dtos.SearchDirectResponse{}, err
+	}
+	var result dtos.SearchDirectResponse
+	err = decodeResponse(SearchDirectEndpoint, jsonData, &result)
+	return result, err
+}
 
-	if !gjson.Valid(jsonString) {
-		logger.WithName(logtags.InvalidJsonResponseError).WithData(map[string]interface{}{
-			"response": jsonString,
-		}).Error(common.JsonDataIsNotValid.Error())
+//rateRoom reads a rate room polling response, results that are not final return retry.ErrNotReady
+func (p *hotelProvider) rateRoom(res *http.Response) (dtos.RateRoomResult, error) {
+	jsonData, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return dtos.RateRoomResult{}, err
+	}
+	var result dtos.RateRoomResponse
+	if err = json.Unmarshal(jsonData, &result); err != nil {
+		return dtos.RateRoomResult{}, decodeResponse(HotelPriceEndpoint, jsonData, &result)
+	}
+	if !result.Result.FinalResult {
+		return dtos.RateRoomResult{}, retry.ErrNotReady
+	}
+	if err = checkResponseSchema(HotelPriceEndpoint, jsonData, &result); err != nil {
+		return dtos.RateRoomResult{}, err
 	}
-	return jsonString, nil
+	return result.Result, nil
+}
+
+func policiesOf(policy dtos.OptionPolicy) []string {
+	policies := make([]string, 0, len(policy.General.Policies))
+	return append(policies, policy.General.Policies...)
 }
 
 func NewHotelProvider() core.HotelProvider {
+	return newHotelProvider()
+}
+
+func newHotelProvider() *hotelProvider {
 	conf := config.Get()
 	provider := &hotelProvider{
 		password:            conf.ProviderPassword,
@@ -1059,7 +1149,19 @@ func NewHotelProvider() core.HotelProvider {
 		baseHotelUrl:        conf.ProviderEndpoint,
 		baseOrderUrl:        conf.OrderEndpoint,
 		baseOrderServiceUrl: conf.OrderServiceEndpoint,
-		client:              &http.Client{},
+		client: &http.Client{
+			Timeout: conf.ProviderTimeouts.Http,
+		},
+		breakers:          Breakers(),
+		limiters:          sharedLimiters(),
+		searchTimeout:     conf.ProviderTimeouts.Search,
+		roomsTimeout:      conf.ProviderTimeouts.Rooms,
+		bookingTimeout:    conf.ProviderTimeouts.Booking,
+		syncTimeout:       conf.ProviderTimeouts.Sync,
+		hotelDataRetry:    conf.ProviderRetry.HotelData,
+		searchResultRetry: conf.ProviderRetry.SearchResult,
+		hotelRoomsRetry:   conf.ProviderRetry.HotelRooms,
+		orderReadRetry:    conf.ProviderRetry.OrderRead,
 	}
 	return provider
 }
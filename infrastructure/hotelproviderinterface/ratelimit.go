@@ -0,0 +1,77 @@
+package hotelProviderInterface
+
+import (
+	"context"
+	"hotel-engine/core/common"
+	"hotel-engine/core/common/logtags"
+	"hotel-engine/infrastructure/config"
+	"hotel-engine/infrastructure/logger"
+	"hotel-engine/utils/ratelimit"
+	"sync"
+)
+
+type trafficKey struct{}
+
+type traffic string
+
+const (
+	liveTraffic traffic = "live"
+	syncTraffic traffic = "sync"
+)
+
+var limiters map[traffic]*ratelimit.Limiter
+var limitersOnce sync.Once
+
+//sharedLimiters returns the request budgets shared by every provider. background sync and live customer traffic have
+//separate buckets so a sync can never use up the budget of search and booking
+func sharedLimiters() map[traffic]*ratelimit.Limiter {
+	limitersOnce.Do(func() {
+		c := config.Get().ProviderRateLimit
+		limiters = map[traffic]*ratelimit.Limiter{
+			syncTraffic: ratelimit.NewLimiter(c.SyncRate, c.SyncBurst),
+			liveTraffic: ratelimit.NewLimiter(c.LiveRate, c.LiveBurst),
+		}
+	})
+	return limiters
+}
+
+//AsSyncTraffic marks the provider calls made with the context as background sync traffic, the jobs mark their
+//context so their calls never use the budget of the customers
+func AsSyncTraffic(ctx context.Context) context.Context {
+	return context.WithValue(ctx, trafficKey{}, syncTraffic)
+}
+
+//AsLiveTraffic marks the provider calls made with the context as live customer traffic, the mark is kept by the calls
+//that are sync traffic by default
+func AsLiveTraffic(ctx context.Context) context.Context {
+	return context.WithValue(ctx, trafficKey{}, liveTraffic)
+}
+
+//orSyncTraffic marks the context as sync traffic unless the caller has marked its traffic
+func orSyncTraffic(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(trafficKey{}).(traffic); ok {
+		return ctx
+	}
+	return AsSyncTraffic(ctx)
+}
+
+func trafficOf(ctx context.Context) traffic {
+	if t, ok := ctx.Value(trafficKey{}).(traffic); ok {
+		return t
+	}
+	return liveTraffic
+}
+
+//wait takes a token from the budget of the request traffic, calls without a traffic mark are live traffic
+func (p *hotelProvider) wait(ctx context.Context, endpoint string) error {
+	t := trafficOf(ctx)
+	err := p.limiters[t].Wait(ctx)
+	if err == ratelimit.ErrDeadlineTooShort {
+		logger.WithName(logtags.ProviderRequestThrottled).WithData(map[string]interface{}{
+			"endpoint": endpoint,
+			"traffic":  t,
+		}).Warn("provider request budget is exhausted before the request deadline")
+		return common.ProviderRequestBudgetExhausted
+	}
+	return err
+}
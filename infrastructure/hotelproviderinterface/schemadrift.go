@@ -0,0 +1,67 @@
+package hotelProviderInterface
+
+import (
+	"encoding/json"
+	"hotel-engine/core/common"
+	"hotel-engine/core/common/logtags"
+	"hotel-engine/infrastructure/logger"
+	"hotel-engine/utils/schemadrift"
+	"sync"
+)
+
+//reportedDrifts keeps the drifts already logged so every changed field is reported once per endpoint
+var reportedDrifts sync.Map
+
+//decodeResponse strictly decodes a provider response. unknown and missing fields are logged, type changes and
+//missing required fields fail the decoding
+func decodeResponse(endpoint string, data []byte, v interface{}) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		if typeError, ok := err.(*json.UnmarshalTypeError); ok {
+			reportSchemaDrift(endpoint, "typeChanged", []string{typeError.Field})
+			return common.ProviderResponseSchemaChanged
+		}
+		logger.WithName(logtags.InvalidJsonResponseError).WithData(map[string]interface{}{
+			"endpoint": endpoint,
+			"response": string(data),
+		}).Error(common.JsonDataIsNotValid.Error())
+		return common.JsonDataIsNotValid
+	}
+	return checkResponseSchema(endpoint, data, v)
+}
+
+//checkResponseSchema compares an already decoded response with its dto, polling endpoints check only final results
+func checkResponseSchema(endpoint string, data []byte, v interface{}) error {
+	report, err := schemadrift.Compare(data, v)
+	if err != nil {
+		return common.JsonDataIsNotValid
+	}
+	reportSchemaDrift(endpoint, "unknown", report.Unknown)
+	reportSchemaDrift(endpoint, "missing", report.Missing)
+	if len(report.RequiredMissing) != 0 {
+		reportSchemaDrift(endpoint, "requiredMissing", report.RequiredMissing)
+		return common.ProviderResponseSchemaChanged
+	}
+	return nil
+}
+
+func reportSchemaDrift(endpoint, kind string, fields []string) {
+	newFields := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if _, reported := reportedDrifts.LoadOrStore(endpoint+"|"+kind+"|"+field, true); !reported {
+			newFields = append(newFields, field)
+		}
+	}
+	if len(newFields) == 0 {
+		return
+	}
+	entry := logger.WithName(logtags.ProviderSchemaDrift).WithData(map[string]interface{}{
+		"endpoint": endpoint,
+		"kind":     kind,
+		"fields":   newFields,
+	})
+	if kind == "unknown" {
+		entry.Warn("provider response has fields that are not in the contract")
+		return
+	}
+	entry.Error("provider response does not match the contract")
+}
@@ -1,39 +1,127 @@
 package hotelProviderInterface
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"hotel-engine/core/common/logtags"
+	"hotel-engine/infrastructure/config"
+	"hotel-engine/infrastructure/hotelproviderinterface/dtos"
 	"hotel-engine/infrastructure/logger"
+	"strings"
 	"sync"
+	"time"
 )
 
+//tokenCall is a login in flight, concurrent callers wait on it instead of logging in again
+type tokenCall struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
 var token string
+var tokenExpiresAt time.Time
+var refreshing *tokenCall
 var mutex = &sync.Mutex{}
 
+//Token returns the cached access token. the token is refreshed when there is none or it is about to expire
 func Token() (string, error) {
-	if token != "" {
-		return token, nil
-	}
 	mutex.Lock()
-	defer mutex.Unlock()
-	if token != "" {
-		return token, nil
+	current, fresh, expiresAt := token, tokenIsFresh(), tokenExpiresAt
+	mutex.Unlock()
+	if fresh {
+		return current, nil
 	}
-	_, err := UpdateToken()
+	t, err := refreshToken(current)
 	if err != nil {
 		logger.WithName(logtags.CannotGetAccessTokenError).
 			ErrorException(err, "error in getting access token")
+		if current != "" && time.Now().Before(expiresAt) {
+			return current, nil
+		}
 		return "", err
 	}
-	return token, nil
+	return t, nil
 }
 
+//RefreshToken replaces a token rejected by the provider. when another caller already replaced it the new token is
+//returned without logging in again
+func RefreshToken(rejected string) (string, error) {
+	return refreshToken(rejected)
+}
+
+//UpdateToken logs in again regardless of the cached token
 func UpdateToken() (string, error) {
-	provider := NewHotelProvider()
-	t, err := provider.GetAccessToken()
+	mutex.Lock()
+	current := token
+	mutex.Unlock()
+	return refreshToken(current)
+}
+
+func refreshToken(stale string) (string, error) {
+	mutex.Lock()
+	if token != stale && tokenIsFresh() {
+		t := token
+		mutex.Unlock()
+		return t, nil
+	}
+	if call := refreshing; call != nil {
+		mutex.Unlock()
+		<-call.done
+		return call.token, call.err
+	}
+	call := &tokenCall{done: make(chan struct{})}
+	refreshing = call
+	mutex.Unlock()
+
+	issuedAt := time.Now()
+	result, err := newHotelProvider().login(context.Background())
+
+	mutex.Lock()
+	if err == nil {
+		token = result.Token
+		tokenExpiresAt = tokenExpiry(result, issuedAt)
+		logger.WithName(logtags.ProviderTokenRefreshed).WithData(map[string]interface{}{
+			"expiresAt": tokenExpiresAt,
+		}).Info("token has been updated")
+	}
+	call.token, call.err = result.Token, err
+	refreshing = nil
+	mutex.Unlock()
+	close(call.done)
+	return call.token, call.err
+}
+
+//tokenIsFresh must be called while holding the mutex. tokens without a known expiry stay fresh until rejected
+func tokenIsFresh() bool {
+	if token == "" {
+		return false
+	}
+	if tokenExpiresAt.IsZero() {
+		return true
+	}
+	return time.Now().Add(config.Get().TokenRefreshMargin).Before(tokenExpiresAt)
+}
+
+//tokenExpiry reads the expiry from the login response, falling back to the exp claim of a jwt token
+func tokenExpiry(result dtos.AccessTokenResult, issuedAt time.Time) time.Time {
+	if result.ExpiresIn > 0 {
+		return issuedAt.Add(time.Duration(result.ExpiresIn) * time.Second)
+	}
+	parts := strings.Split(result.Token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
 	if err != nil {
-		return "", err
+		return time.Time{}
 	}
-	token = t
-	logger.Info("token has been updated")
-	return t, nil
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err = json.Unmarshal(payload, &claims); err != nil || claims.Exp <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
 }
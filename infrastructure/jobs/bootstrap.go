@@ -18,10 +18,16 @@ func RegisterCronJobs(service core.SyncService, hotelService core.HotelService,
 	syncHotelsCronJob := newSyncHotelsCronJob(service, locker)
 	refundPullingCronJob := newRefundPullingCronJob(hotelService)
 	syncTokenCronJob := newSyncTokenCronJob()
+	orderSagaCronJob := newOrderSagaCronJob(hotelService, locker)
+	orderReconciliationCronJob := newOrderReconciliationCronJob(hotelService, locker)
+	hotelCalendarCronJob := newHotelCalendarCronJob(hotelService, locker)
 
 	c.AddFunc(syncHotelsCronJob.cronTab(), syncHotelsCronJob.do)
 	c.AddFunc(syncTokenCronJob.cronTab(), syncTokenCronJob.do)
 	c.AddFunc(refundPullingCronJob.cronTab(), refundPullingCronJob.do)
+	c.AddFunc(orderSagaCronJob.cronTab(), orderSagaCronJob.do)
+	c.AddFunc(orderReconciliationCronJob.cronTab(), orderReconciliationCronJob.do)
+	c.AddFunc(hotelCalendarCronJob.cronTab(), hotelCalendarCronJob.do)
 
 	c.Start()
 	fmt.Println("all cron jobs registered")
@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"context"
+	"hotel-engine/core"
+	"hotel-engine/infrastructure/config"
+	hotelProviderInterface "hotel-engine/infrastructure/hotelproviderinterface"
+	"hotel-engine/infrastructure/logger"
+	"time"
+)
+
+type hotelCalendarCronJob struct {
+	cron    string
+	lockKey string
+	runTime time.Duration
+	service core.HotelService
+	locker  core.DistributedLocker
+}
+
+//do prices the calendar of the hotels on one instance so the supplier is not asked twice for a night. a run ends at
+//its run time and the lock outlives the run, so a run is skipped while another one is still going
+func (h *hotelCalendarCronJob) do() {
+	err := h.locker.Lock(h.lockKey, h.runTime+time.Minute*5, func() {
+		ctx, cancel := context.WithTimeout(hotelProviderInterface.AsSyncTraffic(context.Background()), h.runTime)
+		defer cancel()
+		h.service.SyncHotelCalendars(ctx)
+	})
+	if err != nil {
+		logger.ErrorException(err, "error while trying to obtain a lock")
+	}
+}
+
+func (h *hotelCalendarCronJob) cronTab() string {
+	return h.cron
+}
+
+func newHotelCalendarCronJob(hotelService core.HotelService, locker core.DistributedLocker) job {
+	con := config.Get()
+	return &hotelCalendarCronJob{
+		cron:    con.SyncCalendar.CronTab,
+		lockKey: con.SyncLockKey + "-hotel-calendar",
+		runTime: con.SyncCalendar.RunTime,
+		service: hotelService,
+		locker:  locker,
+	}
+}
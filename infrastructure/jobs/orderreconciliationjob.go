@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"context"
+	"hotel-engine/core"
+	"hotel-engine/infrastructure/config"
+	hotelProviderInterface "hotel-engine/infrastructure/hotelproviderinterface"
+	"hotel-engine/infrastructure/logger"
+	"time"
+)
+
+type orderReconciliationCronJob struct {
+	cron    string
+	lockKey string
+	maxDays int
+	service core.HotelService
+	locker  core.DistributedLocker
+}
+
+//do reconciles the orders on one instance so a change is published once
+func (o *orderReconciliationCronJob) do() {
+	err := o.locker.Lock(o.lockKey, time.Hour, func() {
+		ctx := hotelProviderInterface.AsSyncTraffic(context.Background())
+		o.service.ReconcileOrders(ctx, time.Now().AddDate(0, 0, -o.maxDays))
+	})
+	if err != nil {
+		logger.ErrorException(err, "error while trying to obtain a lock")
+	}
+}
+
+func (o *orderReconciliationCronJob) cronTab() string {
+	return o.cron
+}
+
+func newOrderReconciliationCronJob(hotelService core.HotelService, locker core.DistributedLocker) job {
+	con := config.Get()
+	return &orderReconciliationCronJob{
+		cron:    con.OrderReconciliation.CronTab,
+		lockKey: con.SyncLockKey + "-order-reconciliation",
+		maxDays: con.OrderReconciliation.MaxDays,
+		service: hotelService,
+		locker:  locker,
+	}
+}
@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"context"
+	"hotel-engine/core"
+	"hotel-engine/infrastructure/config"
+	hotelProviderInterface "hotel-engine/infrastructure/hotelproviderinterface"
+	"hotel-engine/infrastructure/logger"
+	"time"
+)
+
+type orderSagaCronJob struct {
+	cron       string
+	lockKey    string
+	staleAfter time.Duration
+	service    core.HotelService
+	locker     core.DistributedLocker
+}
+
+//do resumes the stuck finalizations on one instance, the lock lasts until a resumed finalization is stale again
+func (o *orderSagaCronJob) do() {
+	err := o.locker.Lock(o.lockKey, o.staleAfter, func() {
+		o.service.ResumeOrderSagas(hotelProviderInterface.AsSyncTraffic(context.Background()))
+	})
+	if err != nil {
+		logger.ErrorException(err, "error while trying to obtain a lock")
+	}
+}
+
+func (o *orderSagaCronJob) cronTab() string {
+	return o.cron
+}
+
+func newOrderSagaCronJob(hotelService core.HotelService, locker core.DistributedLocker) job {
+	con := config.Get()
+	return &orderSagaCronJob{
+		cron:       con.OrderSaga.CronTab,
+		lockKey:    con.SyncLockKey + "-order-saga",
+		staleAfter: con.OrderSaga.StaleAfter,
+		service:    hotelService,
+		locker:     locker,
+	}
+}
@@ -1,8 +1,10 @@
 package jobs
 
 import (
+	"context"
 	"hotel-engine/core"
 	"hotel-engine/infrastructure/config"
+	hotelProviderInterface "hotel-engine/infrastructure/hotelproviderinterface"
 	"time"
 )
 
@@ -18,7 +20,7 @@ func (r *refundPullingCronJob) do() {
 	if fromDate.Before(r.refundPullingFromDate) {
 		fromDate = r.refundPullingFromDate
 	}
-	r.service.UpdateRefundedOrdersPaymentStatus(fromDate)
+	r.service.UpdateRefundedOrdersPaymentStatus(hotelProviderInterface.AsSyncTraffic(context.Background()), fromDate)
 }
 
 func (r *refundPullingCronJob) cronTab() string {
@@ -5,8 +5,11 @@ import (
 	"hotel-engine/core"
 	"hotel-engine/core/dbmodel"
 	"hotel-engine/core/dto"
+	"hotel-engine/utils/date"
 	"hotel-engine/utils/random"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type mapper struct{}
@@ -77,6 +80,7 @@ func (m *mapper) ToHotelModel(dto dto.HotelDto) *dbmodel.Hotel {
 		SeoTitle:           dto.Seo.Title,
 		SeoCanonical:       dto.Seo.Canonical,
 		SeoMetaDescription: dto.Seo.MetaDescription,
+		Supplier:           dto.Supplier,
 	}
 }
 
@@ -141,6 +145,7 @@ func (m *mapper) ToHotelDto(model dbmodel.Hotel) *dto.HotelDto {
 		OldPrice:        model.OldPrice,
 		Badges:          badges,
 		Sort:            model.Sort,
+		Supplier:        model.Supplier,
 		Seo: dto.HotelSeoDto{
 			Title:           model.SeoTitle,
 			H1:              model.SeoH1,
@@ -322,6 +327,18 @@ func (m *mapper) ToOrderDto(model dbmodel.Order) dto.OrderDetailDto {
 	for _, room := range model.Rooms {
 		rooms = append(rooms, m.ToOrderRoomDto(room))
 	}
+	rules := make([]dto.CancellationRuleDto, 0, len(model.CancellationRules))
+	for _, rule := range model.CancellationRules {
+		rules = append(rules, m.ToCancellationRuleDto(rule))
+	}
+	refunds := make([]dto.OrderRefundDto, 0, len(model.Refunds))
+	for _, refund := range model.Refunds {
+		refunds = append(refunds, m.ToOrderRefundDto(refund))
+	}
+	history := make([]dto.OrderStateTransitionDto, 0, len(model.StateHistory))
+	for _, transition := range model.StateHistory {
+		history = append(history, m.ToOrderStateTransitionDto(transition))
+	}
 	return dto.OrderDetailDto{
 		OrderId:                  model.ID,
 		ProviderOrderId:          model.ProviderOrderId,
@@ -330,6 +347,9 @@ func (m *mapper) ToOrderDto(model dbmodel.Order) dto.OrderDetailDto {
 		ProviderHotelId:          model.ProviderHotelId,
 		NonRefundable:            model.NonRefundable,
 		GeneralPolicies:          strings.Split(model.GeneralPolicies, ","),
+		CancellationRules:        rules,
+		CheckIn:                  formatOptionalDate(model.CheckIn),
+		CheckOut:                 formatOptionalDate(model.CheckOut),
 		TotalPrice:               model.TotalPrice,
 		Provider:                 model.Provider,
 		ProviderName:             model.ProviderName,
@@ -338,6 +358,10 @@ func (m *mapper) ToOrderDto(model dbmodel.Order) dto.OrderDetailDto {
 		RestrictedMarkupAmount:   model.RestrictedMarkupAmount,
 		RestrictedMarkupType:     model.RestrictedMarkupType,
 		Status:                   model.Status,
+		State:                    model.CurrentState(),
+		StateHistory:             history,
+		ReconciliationMismatch:   model.ReconciliationMismatch,
+		PhoneNumber:              model.PhoneNumber,
 		Rooms:                    rooms,
 		ApplicantRefundRequestId: model.ApplicantRefundRequestId,
 		ApplicantOrderId:         model.ApplicantOrderId,
@@ -348,14 +372,95 @@ func (m *mapper) ToOrderDto(model dbmodel.Order) dto.OrderDetailDto {
 		TotalPenaltyAmount:       model.TotalPenaltyAmount,
 		RefundRequestId:          model.RefundRequestId,
 		Confirmed:                model.Confirmed,
+		Supplier:                 model.Supplier,
+		Refunds:                  refunds,
 	}
 }
 
+func (m *mapper) ToOrderRefundDto(model dbmodel.OrderRefund) dto.OrderRefundDto {
+	return dto.OrderRefundDto{
+		RefundRequestId:          model.RefundRequestId,
+		ApplicantRefundRequestId: model.ApplicantRefundRequestId,
+		ReferenceCodes:           model.Codes(),
+		RefundStatus:             model.RefundStatus,
+		PaidAmount:               model.PaidAmount,
+		RefundableAmount:         model.RefundableAmount,
+		TotalPenaltyAmount:       model.TotalPenaltyAmount,
+		CreatedAt:                model.CreatedAt,
+	}
+}
+
+func (m *mapper) ToOrderStateTransitionDto(model dbmodel.OrderStateTransition) dto.OrderStateTransitionDto {
+	return dto.OrderStateTransitionDto{
+		FromState: model.FromState,
+		ToState:   model.ToState,
+		Event:     model.Event,
+		CreatedAt: model.CreatedAt,
+	}
+}
+
+func (m *mapper) ToOrderSagaDto(model dbmodel.OrderSaga) dto.OrderSagaDto {
+	steps := make([]dto.OrderSagaStepDto, 0, len(model.Steps))
+	for _, step := range model.Steps {
+		steps = append(steps, dto.OrderSagaStepDto{
+			Step:      step.Step,
+			Succeeded: step.Succeeded,
+			Error:     step.Error,
+			CreatedAt: step.CreatedAt,
+		})
+	}
+	return dto.OrderSagaDto{
+		OrderId:   strconv.FormatInt(model.IndraOrderId, 10),
+		Status:    model.Status,
+		Step:      model.Step,
+		Attempts:  model.Attempts,
+		LastError: model.LastError,
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+		Steps:     steps,
+	}
+}
+
+//ToOrderSummaryDto maps an order of a list, the hotel is empty when it is not stored anymore
+func (m *mapper) ToOrderSummaryDto(model dbmodel.Order, hotel *dbmodel.Hotel) dto.OrderSummaryDto {
+	rooms := make([]dto.OrderDetailRoomDto, 0, len(model.Rooms))
+	for _, room := range model.Rooms {
+		rooms = append(rooms, m.ToOrderRoomDto(room))
+	}
+	summary := dto.OrderSummaryDto{
+		IndraOrderId:     model.IndraOrderId,
+		ProviderOrderId:  model.ProviderOrderId,
+		ApplicantOrderId: model.ApplicantOrderId,
+		PhoneNumber:      model.PhoneNumber,
+		Supplier:         model.Supplier,
+		Status:           model.Status,
+		State:            model.CurrentState(),
+		TotalPrice:       model.TotalPrice,
+		Currency:         model.Currency,
+		CheckIn:          formatOptionalDate(model.CheckIn),
+		CheckOut:         formatOptionalDate(model.CheckOut),
+		CreatedAt:        model.CreatedAt,
+		Hotel:            dto.OrderHotelSummaryDto{PlaceId: model.ProviderHotelId},
+		Rooms:            rooms,
+	}
+	if hotel != nil {
+		summary.Hotel.Name = hotel.Name
+		summary.Hotel.NameEn = hotel.NameEn
+		summary.Hotel.City = hotel.City
+		summary.Hotel.Star = hotel.Star
+	}
+	return summary
+}
+
 func (m *mapper) ToOrderModel(item dto.OrderDetailDto) dbmodel.Order {
 	rooms := make([]dbmodel.OrderRoom, 0)
 	for _, room := range item.Rooms {
 		rooms = append(rooms, m.ToOrderRoomModel(room))
 	}
+	rules := make([]dbmodel.OrderCancellationRule, 0, len(item.CancellationRules))
+	for _, rule := range item.CancellationRules {
+		rules = append(rules, m.ToCancellationRuleModel(rule))
+	}
 	return dbmodel.Order{
 		Model:                  gorm.Model{},
 		ProviderOrderId:        item.ProviderOrderId,
@@ -373,7 +478,50 @@ func (m *mapper) ToOrderModel(item dto.OrderDetailDto) dbmodel.Order {
 		RestrictedMarkupType:   item.RestrictedMarkupType,
 		Status:                 item.Status,
 		Rooms:                  rooms,
+		Supplier:               item.Supplier,
+		CheckIn:                parseOptionalDate(item.CheckIn),
+		CheckOut:               parseOptionalDate(item.CheckOut),
+		CancellationRules:      rules,
+		PhoneNumber:            item.PhoneNumber,
+	}
+}
+
+func (m *mapper) ToCancellationRuleDto(model dbmodel.OrderCancellationRule) dto.CancellationRuleDto {
+	return dto.CancellationRuleDto{
+		FromBooking:        model.FromBooking,
+		HoursBeforeCheckIn: model.HoursBeforeCheckIn,
+		Deadline:           model.Deadline,
+		PenaltyPercent:     model.PenaltyPercent,
+		PenaltyAmount:      model.PenaltyAmount,
+		PenaltyNights:      model.PenaltyNights,
+	}
+}
+
+func (m *mapper) ToCancellationRuleModel(item dto.CancellationRuleDto) dbmodel.OrderCancellationRule {
+	return dbmodel.OrderCancellationRule{
+		FromBooking:        item.FromBooking,
+		HoursBeforeCheckIn: item.HoursBeforeCheckIn,
+		Deadline:           item.Deadline,
+		PenaltyPercent:     item.PenaltyPercent,
+		PenaltyAmount:      item.PenaltyAmount,
+		PenaltyNights:      item.PenaltyNights,
+	}
+}
+
+//parseOptionalDate returns nil for the orders that were stored before the dates were kept
+func parseOptionalDate(value string) *time.Time {
+	t, err := date.StringToDateUTC(value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+func formatOptionalDate(value *time.Time) string {
+	if value == nil {
+		return ""
 	}
+	return value.Format(date.LayoutISO)
 }
 
 func (m *mapper) ToOrderRoomDto(model dbmodel.OrderRoom) dto.OrderDetailRoomDto {
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"github.com/jinzhu/gorm"
+	"hotel-engine/core"
+	"hotel-engine/core/dbmodel"
+	"time"
+)
+
+type hotelPriceRepository struct {
+	DB *gorm.DB
+}
+
+//StoreDays replaces the given days of the hotel and removes its past days, the days that are not given are kept
+func (r *hotelPriceRepository) StoreDays(placeId, supplier string, today time.Time, days []dbmodel.HotelPrice) error {
+	dates := make([]time.Time, 0, len(days))
+	for _, day := range days {
+		dates = append(dates, day.Date)
+	}
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("PlaceId = ? AND Supplier = ?", placeId, supplier)
+		if len(dates) > 0 {
+			query = query.Where("Date < ? OR Date IN (?)", today, dates)
+		} else {
+			query = query.Where("Date < ?", today)
+		}
+		if err := query.Delete(&dbmodel.HotelPrice{}).Error; err != nil {
+			return err
+		}
+		for i := range days {
+			days[i].ID = 0
+			days[i].PlaceID = placeId
+			days[i].Supplier = supplier
+			if err := tx.Create(&days[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *hotelPriceRepository) GetDays(placeIds []string, supplier string,
+	from time.Time) ([]dbmodel.HotelPrice, error) {
+	var days []dbmodel.HotelPrice
+	db := r.DB.Where("PlaceId IN (?) AND Supplier = ? AND Date >= ?", placeIds, supplier, from).
+		Order("PlaceId").Order("Date").Find(&days)
+	return days, db.Error
+}
+
+func newHotelPriceRepository(DB *gorm.DB) core.HotelPriceRepository {
+	return &hotelPriceRepository{DB: DB}
+}
@@ -6,6 +6,7 @@ import (
 	"hotel-engine/core"
 	"hotel-engine/core/common"
 	"hotel-engine/core/dbmodel"
+	"hotel-engine/core/dto"
 	"time"
 )
 
@@ -13,31 +14,34 @@ type hotelRepository struct {
 	DB *gorm.DB
 }
 
-func (r *hotelRepository) FindByID(hotelId string) (*dbmodel.Hotel, error) {
+//FindByID finds the hotel of a supplier by its place id or its english name
+func (r *hotelRepository) FindByID(hotelId, supplier string) (*dbmodel.Hotel, error) {
 	var hotel dbmodel.Hotel
 
 	if r.DB.Preload("Amenities").Preload("Places").Preload("FAQList").
 		Preload("Badges").Preload("Amenities.AmenityCategory").
-		Find(&hotel, "PlaceId=? or NameEn=? COLLATE SQL_Latin1_General_CP1_CS_AS ", hotelId, hotelId).RecordNotFound() {
+		Find(&hotel, "(PlaceId=? or NameEn=? COLLATE SQL_Latin1_General_CP1_CS_AS) AND Supplier=?",
+			hotelId, hotelId, supplier).RecordNotFound() {
 		return nil, common.HotelNotFound
 	}
 	return &hotel, nil
 }
 
-func (r *hotelRepository) FindByIDForSync(hotelId string) (*dbmodel.Hotel, error) {
+//FindByIDForSync finds the hotel of a supplier, the place id of a hotel is only unique between the hotels of a supplier
+func (r *hotelRepository) FindByIDForSync(hotelId, supplier string) (*dbmodel.Hotel, error) {
 	var hotel dbmodel.Hotel
 
 	if r.DB.Preload("Amenities").Preload("Places").Preload("Badges").
-		Find(&hotel, "PlaceId=?", hotelId).RecordNotFound() {
+		Find(&hotel, "PlaceId=? AND Supplier=?", hotelId, supplier).RecordNotFound() {
 		return nil, common.HotelNotFound
 	}
 	return &hotel, nil
 }
 
-func (r *hotelRepository) GetHotel(hotelId string) (*dbmodel.Hotel, error) {
+func (r *hotelRepository) GetHotel(hotelId, supplier string) (*dbmodel.Hotel, error) {
 	var hotel dbmodel.Hotel
 	if r.DB.
-		Find(&hotel, "PlaceId=?", hotelId).RecordNotFound() {
+		Find(&hotel, "PlaceId=? AND Supplier=?", hotelId, supplier).RecordNotFound() {
 		return nil, common.HotelNotFound
 	}
 	return &hotel, nil
@@ -55,9 +59,9 @@ func (r *hotelRepository) Delete(hotel dbmodel.Hotel) error {
 	return db.Error
 }
 
-func (r *hotelRepository) GetAllHotelIds() ([]string, error) {
+func (r *hotelRepository) GetAllHotelIds(supplier string) ([]string, error) {
 	var hotels []dbmodel.Hotel
-	db := r.DB.Select("PlaceId").Find(&hotels)
+	db := r.DB.Select("PlaceId").Where("Supplier = ?", supplier).Find(&hotels)
 
 	hotelIds := make([]string, 0, len(hotels))
 	for _, hotel := range hotels {
@@ -74,11 +78,11 @@ func (r *hotelRepository) GetHotelsPageForSync(page int, size int) ([]dbmodel.Ho
 	return hotels, db.Error
 }
 
-func (r *hotelRepository) GetHotels(ids []string) ([]dbmodel.Hotel, error) {
+func (r *hotelRepository) GetHotels(ids []string, supplier string) ([]dbmodel.Hotel, error) {
 	var hotels []dbmodel.Hotel
 	db := r.DB.Preload("Places").
 		Preload("Badges").Preload("Amenities").
-		Preload("Amenities.AmenityCategory").Where("PlaceId IN (?)", ids).Find(&hotels)
+		Preload("Amenities.AmenityCategory").Where("PlaceId IN (?) AND Supplier = ?", ids, supplier).Find(&hotels)
 	return hotels, db.Error
 }
 
@@ -88,6 +92,31 @@ func (r *hotelRepository) GetAllHotels() ([]dbmodel.Hotel, error) {
 	return hotels, db.Error
 }
 
+//GetHotelsForCalendar returns the hotels with the oldest synced calendar first, the hotels that have no calendar yet
+//come before all of them
+func (r *hotelRepository) GetHotelsForCalendar() ([]dbmodel.Hotel, error) {
+	var hotels []dbmodel.Hotel
+	db := r.DB.Select("id, PlaceId, Supplier, RoomId, Price, OldPrice, CalendarSyncedAt").
+		Order("CalendarSyncedAt").Order("id").Find(&hotels)
+	return hotels, db.Error
+}
+
+func (r *hotelRepository) SetCalendarSynced(hotelId uint, at time.Time) error {
+	return r.DB.Model(&dbmodel.Hotel{}).Where("id = ?", hotelId).UpdateColumn("CalendarSyncedAt", at).Error
+}
+
+func (r *hotelRepository) GetAllLocations() ([]dbmodel.Hotel, error) {
+	var hotels []dbmodel.Hotel
+	db := r.DB.Select("PlaceId, City, GeoLocation").Find(&hotels)
+	return hotels, db.Error
+}
+
+func (r *hotelRepository) GetAllNames() ([]dbmodel.Hotel, error) {
+	var hotels []dbmodel.Hotel
+	db := r.DB.Select("PlaceId, Name, NameEn, City, Province, ProvinceEn").Find(&hotels)
+	return hotels, db.Error
+}
+
 func (r *hotelRepository) HasBeenSynced() (bool, error) {
 	now := time.Now()
 	year, month, day := now.Date()
@@ -117,9 +146,63 @@ func (r *hotelRepository) GetHotelsList(page int, size int, search string) ([]db
 	return <-data, total, db.Error
 }
 
-func (r *hotelRepository) RemoveFAQ(hotelId string, faq *dbmodel.FAQ) (*dbmodel.Hotel, error) {
+var catalogueSortColumns = map[string]string{
+	"score":    "RateReview_Score",
+	"minPrice": "Price",
+	"star":     "Star",
+}
+
+func (r *hotelRepository) SearchCatalogue(search dto.CatalogueSearchDto) ([]dbmodel.Hotel, int, error) {
+	query := r.DB.Model(dbmodel.Hotel{}).Where("City = ? AND Price > 0", search.City)
+	if search.Keyword != "" {
+		query = query.Where("Name like ? or NameEn like ?", "%"+search.Keyword+"%", "%"+search.Keyword+"%")
+	}
+	if search.MinPrice != 0 || search.MaxPrice != 0 {
+		query = query.Where("Price BETWEEN ? AND ?", search.MinPrice, search.MaxPrice)
+	}
+	if search.MinScore != 0 || search.MaxScore != 0 {
+		query = query.Where("RateReview_Score BETWEEN ? AND ?", search.MinScore, search.MaxScore)
+	}
+	if len(search.Stars) != 0 {
+		query = query.Where("Star IN (?)", search.Stars)
+	}
+	if len(search.HotelTypes) != 0 {
+		query = query.Where("Type IN (?)", search.HotelTypes)
+	}
+	if len(search.Regions) != 0 {
+		query = query.Where("Region IN (?)", search.Regions)
+	}
+	for _, amenity := range search.Amenities {
+		query = query.Where("id IN (SELECT hotel_id FROM hotel_amenity WHERE amenity_id = ?)", amenity)
+	}
+	for _, category := range search.Categories {
+		query = query.Where("id IN (SELECT ha.hotel_id FROM hotel_amenity ha "+
+			"JOIN amenities a ON a.id = ha.amenity_id WHERE a.amenity_category_id = ?)", category)
+	}
+
+	var total int
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	column, found := catalogueSortColumns[search.SortField]
+	if !found {
+		column = catalogueSortColumns["score"]
+	}
+	direction := " desc"
+	if search.Ascending {
+		direction = " asc"
+	}
+	var hotels []dbmodel.Hotel
+	db := query.Preload("Amenities").Preload("Badges").
+		Order(column + direction).Order("Sort desc").Order("id").
+		Limit(search.Limit).Offset(search.Skip).Find(&hotels)
+	return hotels, total, db.Error
+}
+
+func (r *hotelRepository) RemoveFAQ(hotelId, supplier string, faq *dbmodel.FAQ) (*dbmodel.Hotel, error) {
 	var hotel dbmodel.Hotel
-	err := r.DB.Find(&hotel, "PlaceId=? or NameEn=? COLLATE SQL_Latin1_General_CP1_CS_AS ", hotelId, hotelId).
+	err := r.DB.Find(&hotel, "(PlaceId=? or NameEn=? COLLATE SQL_Latin1_General_CP1_CS_AS) AND Supplier=?",
+		hotelId, hotelId, supplier).
 		Association("FAQList").Delete(faq).Error
 	return &hotel, err
 }
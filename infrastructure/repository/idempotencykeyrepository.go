@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"github.com/jinzhu/gorm"
+	"hotel-engine/core"
+	"hotel-engine/core/common"
+	"hotel-engine/core/dbmodel"
+	"time"
+)
+
+type idempotencyKeyRepository struct {
+	DB *gorm.DB
+}
+
+func (r *idempotencyKeyRepository) Get(key string) (*dbmodel.IdempotencyKey, error) {
+	var item dbmodel.IdempotencyKey
+	db := r.DB.Where("IdempotencyKey = ?", key).First(&item)
+	if db.RecordNotFound() {
+		return nil, common.IdempotencyKeyNotFound
+	}
+	return &item, db.Error
+}
+
+//Insert reserves the key, the unique index of the key fails the insert of a key that another request has reserved
+func (r *idempotencyKeyRepository) Insert(item dbmodel.IdempotencyKey) (*dbmodel.IdempotencyKey, error) {
+	err := r.DB.Create(&item).Error
+	return &item, err
+}
+
+func (r *idempotencyKeyRepository) StoreOrUpdate(item dbmodel.IdempotencyKey) error {
+	return r.DB.Save(&item).Error
+}
+
+//Delete releases the key for good so it can be reserved again
+func (r *idempotencyKeyRepository) Delete(item dbmodel.IdempotencyKey) error {
+	return r.DB.Unscoped().Delete(&item).Error
+}
+
+//Reclaim reserves the key again for the request only when it is not changed since it was read, a key that another
+//request has reclaimed or kept alive in the meantime returns IdempotencyKeyInProgress
+func (r *idempotencyKeyRepository) Reclaim(item dbmodel.IdempotencyKey, hash string) (*dbmodel.IdempotencyKey, error) {
+	now := time.Now()
+	db := r.DB.Model(&dbmodel.IdempotencyKey{}).Where("id = ? and updated_at = ?", item.ID, item.UpdatedAt).
+		UpdateColumns(map[string]interface{}{
+			"RequestHash": hash,
+			"Completed":   false,
+			"StatusCode":  0,
+			"Response":    "",
+			"created_at":  now,
+			"updated_at":  now,
+		})
+	if db.Error != nil {
+		return nil, db.Error
+	}
+	if db.RowsAffected == 0 {
+		return nil, common.IdempotencyKeyInProgress
+	}
+	item.RequestHash, item.Completed, item.StatusCode, item.Response = hash, false, 0, ""
+	item.CreatedAt, item.UpdatedAt = now, now
+	return &item, nil
+}
+
+//Touch keeps the key of a request in progress from getting stale
+func (r *idempotencyKeyRepository) Touch(item dbmodel.IdempotencyKey) error {
+	return r.DB.Model(&dbmodel.IdempotencyKey{}).
+		Where("id = ? and RequestHash = ? and Completed = ?", item.ID, item.RequestHash, false).
+		UpdateColumn("updated_at", time.Now()).Error
+}
+
+func newIdempotencyKeyRepository(DB *gorm.DB) core.IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{DB: DB}
+}
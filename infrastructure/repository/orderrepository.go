@@ -5,6 +5,8 @@ import (
 	"hotel-engine/core"
 	"hotel-engine/core/common"
 	"hotel-engine/core/dbmodel"
+	"hotel-engine/core/dto"
+	"hotel-engine/utils/date"
 	"strconv"
 	"time"
 )
@@ -13,17 +15,17 @@ type orderRepository struct {
 	DB *gorm.DB
 }
 
-func (r *orderRepository) getHotel(hotelId string) (*dbmodel.Hotel, error) {
+func (r *orderRepository) getHotel(hotelId, supplier string) (*dbmodel.Hotel, error) {
 	var hotel dbmodel.Hotel
 	if r.DB.
-		Find(&hotel, "PlaceId=?", hotelId).RecordNotFound() {
+		Find(&hotel, "PlaceId=? AND Supplier=?", hotelId, supplier).RecordNotFound() {
 		return nil, common.HotelNotFound
 	}
 	return &hotel, nil
 }
 
 func (r *orderRepository) Insert(order dbmodel.Order) (*dbmodel.Order, error) {
-	hotel, err := r.getHotel(order.ProviderHotelId)
+	hotel, err := r.getHotel(order.ProviderHotelId, order.Supplier)
 	if err != nil {
 		return nil, err
 	}
@@ -34,7 +36,7 @@ func (r *orderRepository) Insert(order dbmodel.Order) (*dbmodel.Order, error) {
 
 func (r *orderRepository) GetOneByIndraId(indraId string) (*dbmodel.Order, error) {
 	var order dbmodel.Order
-	if r.DB.Preload("Rooms").
+	if r.DB.Preload("Rooms").Preload("CancellationRules").Preload("Refunds").
 		Find(&order, "IndraOrderId=?", indraId).RecordNotFound() {
 		return nil, common.OrderNotFound
 	}
@@ -45,12 +47,38 @@ func (r *orderRepository) StoreOrUpdate(order dbmodel.Order) error {
 	return r.DB.Save(&order).Error
 }
 
+//UpdateReconciliation stores the status, the state and the mismatch of a reconciled order with its new transitions,
+//the other columns are left as they are so a finalization or a payment stored in the meantime is not overwritten
+func (r *orderRepository) UpdateReconciliation(order dbmodel.Order) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&dbmodel.Order{}).Where("id = ?", order.ID).UpdateColumns(map[string]interface{}{
+			"Status":                 order.Status,
+			"State":                  order.State,
+			"ReconciliationMismatch": order.ReconciliationMismatch,
+			"updated_at":             time.Now(),
+		}).Error
+		if err != nil {
+			return err
+		}
+		for i := range order.StateHistory {
+			if order.StateHistory[i].ID != 0 {
+				continue
+			}
+			if err := tx.Create(&order.StateHistory[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (r *orderRepository) GetProperOrderIdsForRefundUpdateStatus(
-	fromDate time.Time) ([]string, error) {
+	fromDate time.Time, supplier string) ([]string, error) {
 
 	var orders []dbmodel.Order
 	db := r.DB.Select("IndraOrderId").
-		Where("RefundRequestId != '' and updated_at > ? and RefundStatus != ?", fromDate, common.RefundStatus_PaymentFinalized).
+		Where("RefundRequestId != '' and updated_at > ? and RefundStatus != ? and Supplier = ?",
+			fromDate, common.RefundStatus_PaymentFinalized, supplier).
 		Order("id desc").Find(&orders)
 
 	indraOrderIds := make([]string, 0, len(orders))
@@ -61,6 +89,111 @@ func (r *orderRepository) GetProperOrderIdsForRefundUpdateStatus(
 	return indraOrderIds, db.Error
 }
 
+func (r *orderRepository) StoreRefund(refund dbmodel.OrderRefund) error {
+	return r.DB.Save(&refund).Error
+}
+
+//GetPendingRefunds returns the refund requests of the supplier that are not finalized yet with their orders
+func (r *orderRepository) GetPendingRefunds(fromDate time.Time, supplier string) ([]dbmodel.OrderRefund, error) {
+	var refunds []dbmodel.OrderRefund
+	db := r.DB.Preload("Order").
+		Where("RefundStatus not in (?) and updated_at > ? and OrderID in (select id from orders where Supplier = ?)",
+			common.RefundClosedStatuses, fromDate, supplier).
+		Order("id desc").Find(&refunds)
+	return refunds, db.Error
+}
+
+//GetStateHistory returns the state transitions of the order in the order they happened
+func (r *orderRepository) GetStateHistory(orderId uint) ([]dbmodel.OrderStateTransition, error) {
+	var history []dbmodel.OrderStateTransition
+	db := r.DB.Where("OrderID = ?", orderId).Order("id asc").Find(&history)
+	return history, db.Error
+}
+
+//GetOrdersToReconcile returns the orders of the supplier that are not in a final state, the orders that were stored
+//before the states were kept have no state and are returned too. an issued order is only returned while it has a
+//refund request that the supplier has not finished
+func (r *orderRepository) GetOrdersToReconcile(fromDate time.Time, supplier string) ([]dbmodel.Order, error) {
+	var orders []dbmodel.Order
+	db := r.DB.Where("created_at > ? and Supplier = ? and State not in (?)", fromDate, supplier,
+		[]string{common.OrderState_Refunded, common.OrderState_Failed, common.OrderState_Expired}).
+		Where("State <> ? or id in (select OrderID from order_refunds where RefundStatus not in (?) and deleted_at is null)",
+			common.OrderState_Issued, common.RefundClosedStatuses).
+		Order("id desc").Find(&orders)
+	return orders, db.Error
+}
+
+var orderSortColumns = map[string]string{
+	"createdAt":  "id",
+	"checkIn":    "CheckIn",
+	"totalPrice": "TotalPrice",
+}
+
+func (r *orderRepository) ordersQuery(filter dto.OrdersPageRequestDto) *gorm.DB {
+	query := r.DB.Model(dbmodel.Order{})
+	if filter.HotelId != "" {
+		query = query.Where("ProviderHotelId = ?", filter.HotelId)
+	}
+	if filter.PhoneNumber != "" {
+		query = query.Where("PhoneNumber = ?", filter.PhoneNumber)
+	}
+	if filter.Status != "" {
+		query = query.Where("Status = ?", filter.Status)
+	}
+	if filter.State != "" {
+		query = query.Where("State = ?", filter.State)
+	}
+	if filter.ApplicantOrderId != 0 {
+		query = query.Where("ApplicantOrderId = ? or id in (select OrderID from order_refunds where ApplicantOrderId = ?)",
+			filter.ApplicantOrderId, filter.ApplicantOrderId)
+	}
+	if filter.CheckInFrom != "" {
+		query = query.Where("CheckIn >= ?", date.StringToDateUTCOrDefault(filter.CheckInFrom))
+	}
+	if filter.CheckInTo != "" {
+		query = query.Where("CheckIn <= ?", date.StringToDateUTCOrDefault(filter.CheckInTo))
+	}
+	if filter.CreatedFrom != "" {
+		query = query.Where("created_at >= ?", date.StringToDateOrDefault(filter.CreatedFrom))
+	}
+	if filter.CreatedTo != "" {
+		query = query.Where("created_at < ?", date.StringToDateOrDefault(filter.CreatedTo).AddDate(0, 0, 1))
+	}
+	return query
+}
+
+//GetOrdersList returns a page of the orders of the filter with their rooms and the number of the orders
+func (r *orderRepository) GetOrdersList(filter dto.OrdersPageRequestDto) ([]dbmodel.Order, int, error) {
+	query := r.ordersQuery(filter)
+	var total int
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	column, found := orderSortColumns[filter.SortField]
+	if !found {
+		column = orderSortColumns["createdAt"]
+	}
+	direction := " desc"
+	if filter.Ascending {
+		direction = " asc"
+	}
+	query = query.Preload("Rooms").Order(column + direction)
+	if column != "id" {
+		query = query.Order("id desc")
+	}
+	var orders []dbmodel.Order
+	db := query.Limit(filter.PageSize).Offset(filter.PageSize * (filter.PageNumber - 1)).Find(&orders)
+	return orders, total, db.Error
+}
+
+//GetOrdersTotals returns the number and the price of the orders of the filter by their state
+func (r *orderRepository) GetOrdersTotals(filter dto.OrdersPageRequestDto) ([]dbmodel.OrderStateTotal, error) {
+	var totals []dbmodel.OrderStateTotal
+	db := r.ordersQuery(filter).Select("State, count(*) as Count, sum(TotalPrice) as TotalPrice").
+		Group("State").Scan(&totals)
+	return totals, db.Error
+}
+
 func newOrderRepository(DB *gorm.DB) core.OrderRepository {
 	return &orderRepository{DB: DB}
 }
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"github.com/jinzhu/gorm"
+	"hotel-engine/core"
+	"hotel-engine/core/common"
+	"hotel-engine/core/dbmodel"
+	"time"
+)
+
+type orderSagaRepository struct {
+	DB *gorm.DB
+}
+
+func (r *orderSagaRepository) GetByOrderId(orderId uint) (*dbmodel.OrderSaga, error) {
+	var saga dbmodel.OrderSaga
+	db := r.DB.Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id asc")
+	}).Where("OrderID = ?", orderId).First(&saga)
+	if db.RecordNotFound() {
+		return nil, common.OrderSagaNotFound
+	}
+	return &saga, db.Error
+}
+
+func (r *orderSagaRepository) StoreOrUpdate(saga *dbmodel.OrderSaga) error {
+	return r.DB.Save(saga).Error
+}
+
+//GetStuck returns the running sagas that are not changed since the time and the sagas that are left for the support
+func (r *orderSagaRepository) GetStuck(before time.Time) ([]dbmodel.OrderSaga, error) {
+	var sagas []dbmodel.OrderSaga
+	db := r.DB.Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id asc")
+	}).Where("(Status = ? and updated_at < ?) or Status in (?)", common.OrderSagaStatus_Running, before,
+		[]string{common.OrderSagaStatus_CompensationFailed, common.OrderSagaStatus_NeedsSupport}).
+		Order("id asc").Find(&sagas)
+	return sagas, db.Error
+}
+
+func newOrderSagaRepository(DB *gorm.DB) core.OrderSagaRepository {
+	return &orderSagaRepository{DB: DB}
+}
@@ -0,0 +1,76 @@
+package sql
+
+import (
+	"github.com/jinzhu/gorm"
+	"hotel-engine/core/dbmodel"
+	"hotel-engine/infrastructure/logger"
+)
+
+//migrate changes the tables that auto migrate cannot change, every step must be safe to run again on each start
+func migrate(db *gorm.DB) {
+	steps := []struct {
+		name string
+		run  func(db *gorm.DB) error
+	}{
+		{name: "drop the place id unique index of hotels", run: dropHotelPlaceIdIndex},
+		{name: "drop the hotel code unique index of hotels", run: dropHotelCodeIndex},
+		{name: "drop the place id and day index of hotel prices", run: dropHotelPriceDayIndex},
+		{name: "backfill the state of the orders", run: backfillOrderState},
+	}
+	for _, step := range steps {
+		if err := step.run(db); err != nil {
+			logger.WithException(err).WithData(step.name).
+				Fatal("Error migrating database")
+		}
+	}
+}
+
+//dropHotelPlaceIdIndex drops the index that made a place id unique between all the suppliers, it is replaced by the
+//place id and supplier index
+func dropHotelPlaceIdIndex(db *gorm.DB) error {
+	return dropHotelUniqueIndex(db, "PlaceId")
+}
+
+//dropHotelCodeIndex drops the index that made a hotel code unique between all the suppliers, it is replaced by the
+//hotel code and supplier index
+func dropHotelCodeIndex(db *gorm.DB) error {
+	return dropHotelUniqueIndex(db, "HotelCode")
+}
+
+func dropHotelUniqueIndex(db *gorm.DB, column string) error {
+	scope := db.NewScope(&dbmodel.Hotel{})
+	name := scope.Dialect().BuildKeyName("uix", scope.TableName(), column)
+	if !scope.Dialect().HasIndex(scope.TableName(), name) {
+		return nil
+	}
+	return db.Model(&dbmodel.Hotel{}).RemoveIndex(name).Error
+}
+
+//dropHotelPriceDayIndex drops the index that kept one price of a night for a place id between all the suppliers, it is
+//replaced by the place id, supplier and day index
+func dropHotelPriceDayIndex(db *gorm.DB) error {
+	scope := db.NewScope(&dbmodel.HotelPrice{})
+	if !scope.Dialect().HasIndex(scope.TableName(), "idx_hotel_price_day") {
+		return nil
+	}
+	return db.Model(&dbmodel.HotelPrice{}).RemoveIndex("idx_hotel_price_day").Error
+}
+
+//backfillOrderState keeps the state of the orders that were stored before the states were kept, so the state of every
+//order can be filtered and grouped in the database. the state is the one the order gets from its old status fields
+func backfillOrderState(db *gorm.DB) error {
+	for {
+		var orders []dbmodel.Order
+		err := db.Select("id, Status, TransactionStatus, TransactionRequestId, Confirmed, RefundRequestId, RefundStatus").
+			Where("State = ?", "").Order("id").Limit(500).Find(&orders).Error
+		if err != nil || len(orders) == 0 {
+			return err
+		}
+		for i := range orders {
+			err := db.Model(&orders[i]).UpdateColumn("State", orders[i].CurrentState()).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
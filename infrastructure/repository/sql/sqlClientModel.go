@@ -19,7 +19,10 @@ func newSqlClient(connectionString string) *gorm.DB {
 	db.DB().SetMaxOpenConns(10)
 	db.AutoMigrate(&dbmodel.Amenity{}, &dbmodel.Hotel{}, &dbmodel.City{},
 		&dbmodel.Place{}, &dbmodel.OrderRoom{}, &dbmodel.Order{}, &dbmodel.AmenityCategory{},
-		&dbmodel.Badge{}, &dbmodel.FAQ{})
+		&dbmodel.Badge{}, &dbmodel.FAQ{}, &dbmodel.HotelPrice{}, &dbmodel.OrderCancellationRule{},
+		&dbmodel.OrderRefund{}, &dbmodel.OrderStateTransition{}, &dbmodel.IdempotencyKey{},
+		&dbmodel.OrderSaga{}, &dbmodel.OrderSagaStep{})
+	migrate(db)
 	return db
 }
 
@@ -13,6 +13,9 @@ type unitOfWork struct {
 	place           core.PlaceRepository
 	amenityCategory core.AmenityCategoryRepository
 	badge           core.BadgeRepository
+	hotelPrice      core.HotelPriceRepository
+	idempotencyKey  core.IdempotencyKeyRepository
+	orderSaga       core.OrderSagaRepository
 }
 
 func (u *unitOfWork) Hotel() core.HotelRepository {
@@ -42,6 +45,18 @@ func (u *unitOfWork) Badge() core.BadgeRepository {
 	return u.badge
 }
 
+func (u *unitOfWork) HotelPrice() core.HotelPriceRepository {
+	return u.hotelPrice
+}
+
+func (u *unitOfWork) IdempotencyKey() core.IdempotencyKeyRepository {
+	return u.idempotencyKey
+}
+
+func (u *unitOfWork) OrderSaga() core.OrderSagaRepository {
+	return u.orderSaga
+}
+
 func NewUnitOfWork(DB *gorm.DB) core.UnitOfWork {
 	return &unitOfWork{
 		hotel:           newHotelRepository(DB),
@@ -51,5 +66,8 @@ func NewUnitOfWork(DB *gorm.DB) core.UnitOfWork {
 		place:           newPlaceRepository(DB),
 		amenityCategory: newAmenityCategory(DB),
 		badge:           newBadgeRepository(DB),
+		hotelPrice:      newHotelPriceRepository(DB),
+		idempotencyKey:  newIdempotencyKeyRepository(DB),
+		orderSaga:       newOrderSagaRepository(DB),
 	}
 }
@@ -0,0 +1,163 @@
+package circuitbreaker
+
+import (
+	"hotel-engine/core/common"
+	"sync"
+	"time"
+)
+
+type State string
+
+const (
+	Closed   State = "closed"
+	Open     State = "open"
+	HalfOpen State = "half-open"
+)
+
+type Settings struct {
+	FailureThreshold   int
+	OpenTimeout        time.Duration
+	HalfOpenMaxCalls   int
+	MaxConcurrentCalls int
+}
+
+//Outcome is the result of a guarded call. ignored calls release their slot without affecting the breaker state
+type Outcome int
+
+const (
+	Succeeded Outcome = iota
+	Failed
+	Ignored
+)
+
+type StateChangeHandler func(name string, from, to State)
+
+type Snapshot struct {
+	Name     string
+	State    State
+	Failures int
+	InFlight int
+	OpenedAt time.Time
+}
+
+//CircuitBreaker guards calls to a single endpoint. consecutive failures open the circuit, after the open timeout
+//a limited number of trial calls are allowed in half-open state and the first result decides the next state.
+//the bulkhead rejects calls when the endpoint already has MaxConcurrentCalls in flight.
+type CircuitBreaker struct {
+	name          string
+	settings      Settings
+	onStateChange StateChangeHandler
+	lock          sync.Mutex
+	state         State
+	failures      int
+	openedAt      time.Time
+	halfOpenCalls int
+	inFlight      int
+}
+
+//Allow reserves a slot for a call. the returned done function must be called exactly once with the call outcome
+func (b *CircuitBreaker) Allow() (done func(outcome Outcome), err error) {
+	b.lock.Lock()
+	from := b.state
+	if b.state == Open && time.Since(b.openedAt) >= b.settings.OpenTimeout {
+		b.setState(HalfOpen)
+	}
+	switch {
+	case b.state == Open:
+		err = common.ProviderCircuitOpenProblem
+	case b.state == HalfOpen && b.halfOpenCalls >= b.settings.HalfOpenMaxCalls:
+		err = common.ProviderCircuitOpenProblem
+	case b.settings.MaxConcurrentCalls > 0 && b.inFlight >= b.settings.MaxConcurrentCalls:
+		err = common.ProviderTooManyRequestsProblem
+	}
+	to := b.state
+	trial := b.state == HalfOpen
+	if err == nil {
+		b.inFlight++
+		if trial {
+			b.halfOpenCalls++
+		}
+	}
+	b.lock.Unlock()
+	b.notify(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var once sync.Once
+	return func(outcome Outcome) {
+		once.Do(func() { b.done(outcome, trial) })
+	}, nil
+}
+
+func (b *CircuitBreaker) done(outcome Outcome, trial bool) {
+	b.lock.Lock()
+	from := b.state
+	b.inFlight--
+	if trial && b.halfOpenCalls > 0 {
+		b.halfOpenCalls--
+	}
+	switch outcome {
+	case Succeeded:
+		b.failures = 0
+		if b.state == HalfOpen {
+			b.setState(Closed)
+		}
+	case Failed:
+		b.failures++
+		if b.state == HalfOpen || (b.state == Closed && b.failures >= b.settings.FailureThreshold) {
+			b.setState(Open)
+		}
+	}
+	to := b.state
+	b.lock.Unlock()
+	b.notify(from, to)
+}
+
+func (b *CircuitBreaker) setState(state State) {
+	b.state = state
+	switch state {
+	case Open:
+		b.openedAt = time.Now()
+	case Closed:
+		b.failures = 0
+		b.halfOpenCalls = 0
+	}
+}
+
+func (b *CircuitBreaker) notify(from, to State) {
+	if from != to && b.onStateChange != nil {
+		b.onStateChange(b.name, from, to)
+	}
+}
+
+func (b *CircuitBreaker) Snapshot() Snapshot {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	state := b.state
+	if state == Open && time.Since(b.openedAt) >= b.settings.OpenTimeout {
+		state = HalfOpen
+	}
+	return Snapshot{
+		Name:     b.name,
+		State:    state,
+		Failures: b.failures,
+		InFlight: b.inFlight,
+		OpenedAt: b.openedAt,
+	}
+}
+
+func NewCircuitBreaker(name string, settings Settings, onStateChange StateChangeHandler) *CircuitBreaker {
+	if settings.FailureThreshold <= 0 {
+		settings.FailureThreshold = 1
+	}
+	if settings.HalfOpenMaxCalls <= 0 {
+		settings.HalfOpenMaxCalls = 1
+	}
+	return &CircuitBreaker{
+		name:          name,
+		settings:      settings,
+		onStateChange: onStateChange,
+		state:         Closed,
+	}
+}
@@ -0,0 +1,52 @@
+package circuitbreaker
+
+import (
+	"sort"
+	"sync"
+)
+
+//Group keeps one circuit breaker per name, all of them created with the same settings
+type Group struct {
+	lock          sync.RWMutex
+	settings      Settings
+	onStateChange StateChangeHandler
+	breakers      map[string]*CircuitBreaker
+}
+
+func (g *Group) Get(name string) *CircuitBreaker {
+	g.lock.RLock()
+	breaker, found := g.breakers[name]
+	g.lock.RUnlock()
+	if found {
+		return breaker
+	}
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if breaker, found = g.breakers[name]; found {
+		return breaker
+	}
+	breaker = NewCircuitBreaker(name, g.settings, g.onStateChange)
+	g.breakers[name] = breaker
+	return breaker
+}
+
+func (g *Group) Snapshots() []Snapshot {
+	g.lock.RLock()
+	snapshots := make([]Snapshot, 0, len(g.breakers))
+	for _, breaker := range g.breakers {
+		snapshots = append(snapshots, breaker.Snapshot())
+	}
+	g.lock.RUnlock()
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Name < snapshots[j].Name
+	})
+	return snapshots
+}
+
+func NewGroup(settings Settings, onStateChange StateChangeHandler) *Group {
+	return &Group{
+		settings:      settings,
+		onStateChange: onStateChange,
+		breakers:      make(map[string]*CircuitBreaker),
+	}
+}
@@ -0,0 +1,22 @@
+package ctxtime
+
+import (
+	"context"
+	"time"
+)
+
+//Sleep pauses for the given duration or until the context is done, whichever happens first.
+//it returns the context error when the wait has been cut short
+func Sleep(ctx context.Context, duration time.Duration) error {
+	if duration <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
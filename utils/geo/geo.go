@@ -0,0 +1,65 @@
+package geo
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+const earthRadiusInKm = 6371.0
+
+type Point struct {
+	Lat float64
+	Lon float64
+}
+
+//ParsePoint reads a "lat,lon" location as it is stored for hotels and places
+func ParsePoint(location string) (Point, bool) {
+	parts := strings.Split(location, ",")
+	if len(parts) != 2 {
+		return Point{}, false
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return Point{}, false
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return Point{}, false
+	}
+	point := Point{Lat: lat, Lon: lon}
+	return point, point.IsValid() && (lat != 0 || lon != 0)
+}
+
+func (p Point) IsValid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lon >= -180 && p.Lon <= 180
+}
+
+//Distance returns the great circle distance between the points in kilometers
+func Distance(a, b Point) float64 {
+	lat1, lat2 := toRadians(a.Lat), toRadians(b.Lat)
+	dLat := lat2 - lat1
+	dLon := toRadians(b.Lon - a.Lon)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusInKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+//BoundingBox is a map area, boxes crossing the antimeridian are not supported
+type BoundingBox struct {
+	MinLat float64
+	MinLon float64
+	MaxLat float64
+	MaxLon float64
+}
+
+func (b BoundingBox) Contains(p Point) bool {
+	return p.Lat >= b.MinLat && p.Lat <= b.MaxLat && p.Lon >= b.MinLon && p.Lon <= b.MaxLon
+}
+
+func (b BoundingBox) Center() Point {
+	return Point{Lat: (b.MinLat + b.MaxLat) / 2, Lon: (b.MinLon + b.MaxLon) / 2}
+}
+
+func toRadians(degree float64) float64 {
+	return degree * math.Pi / 180
+}
@@ -1,6 +1,7 @@
 package httphelper
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,7 +9,10 @@ import (
 	"hotel-engine/core/common/logtags"
 	"hotel-engine/infrastructure/hotelproviderinterface/dtos"
 	"hotel-engine/infrastructure/logger"
+	"hotel-engine/utils/retry"
+	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"strings"
 )
@@ -36,10 +40,49 @@ func GetResponseError(res *http.Response) error {
 				return common.ProviderGateWayTimeOutProblem
 			}
 
-			return errors.New(fmt.Sprintf("irrelevant response data from provider, status code : %d", res.StatusCode))
+			return &ResponseError{
+				StatusCode: res.StatusCode,
+				Message:    fmt.Sprintf("irrelevant response data from provider, status code : %d", res.StatusCode),
+			}
 		}
 
-		return errors.New(errorDto.Message)
+		return &ResponseError{
+			StatusCode: res.StatusCode,
+			Message:    errorDto.Message,
+		}
 	}
 	return nil
 }
+
+//ResponseError is an unsuccessful provider response, the message is the one reported by the provider
+type ResponseError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *ResponseError) Error() string {
+	return e.Message
+}
+
+//IsRetryableError reports whether calling the provider again may succeed. rate limits, gateway problems,
+//network errors and not ready results are retryable, business errors and cancelled requests are not
+func IsRetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if err == retry.ErrNotReady || err == common.ProviderRateLimitProblem ||
+		err == common.ProviderGateWayTimeOutProblem {
+		return true
+	}
+	var responseError *ResponseError
+	if errors.As(err, &responseError) {
+		return responseError.StatusCode == http.StatusBadGateway ||
+			responseError.StatusCode == http.StatusServiceUnavailable ||
+			responseError.StatusCode == http.StatusGatewayTimeout
+	}
+	var netError net.Error
+	if errors.As(err, &netError) {
+		return true
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
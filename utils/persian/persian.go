@@ -0,0 +1,141 @@
+package persian
+
+import (
+	"strings"
+	"unicode"
+)
+
+var normalRunes = map[rune]rune{
+	'ي': 'ی',
+	'ى': 'ی',
+	'ئ': 'ی',
+	'ك': 'ک',
+	'ة': 'ه',
+	'ۀ': 'ه',
+	'أ': 'ا',
+	'إ': 'ا',
+	'آ': 'ا',
+	'ٱ': 'ا',
+	'ؤ': 'و',
+}
+
+//Normalize makes a text comparable, arabic letters are replaced with the persian ones, zero width joiners become
+//spaces, diacritics are removed, digits become latin and the latin letters are lower cased
+func Normalize(text string) string {
+	var builder strings.Builder
+	space := true
+	for _, r := range text {
+		if normal, found := normalRunes[r]; found {
+			r = normal
+		}
+		switch {
+		case r >= '۰' && r <= '۹':
+			r = '0' + r - '۰'
+		case r >= '٠' && r <= '٩':
+			r = '0' + r - '٠'
+		case r == '\u200c' || r == '\u200d':
+			r = ' '
+		case r == 'ـ' || unicode.Is(unicode.Mn, r):
+			continue
+		}
+		if unicode.IsSpace(r) || unicode.IsPunct(r) {
+			if !space {
+				builder.WriteRune(' ')
+			}
+			space = true
+			continue
+		}
+		builder.WriteRune(unicode.ToLower(r))
+		space = false
+	}
+	return strings.TrimRight(builder.String(), " ")
+}
+
+//the skeleton of a word is its consonants, letters with the same sound share a symbol. the vowels are not written in
+//persian so this is how "tehran" and "تهران" or "طهران" are compared
+var persianSkeleton = map[rune]string{
+	'ب': "b", 'پ': "p", 'ت': "t", 'ط': "t", 'ث': "s", 'س': "s", 'ص': "s", 'ج': "j", 'چ': "c", 'ح': "h", 'ه': "h",
+	'خ': "x", 'د': "d", 'ذ': "z", 'ز': "z", 'ض': "z", 'ظ': "z", 'ر': "r", 'ژ': "y", 'ش': "w", 'غ': "q", 'ق': "q",
+	'ف': "f", 'ک': "k", 'گ': "g", 'ل': "l", 'م': "m", 'ن': "n",
+}
+
+var latinDigraphs = map[string]string{"kh": "x", "gh": "q", "ch": "c", "sh": "w", "zh": "y"}
+
+var latinSkeleton = map[rune]string{
+	'b': "b", 'p': "p", 't': "t", 's': "s", 'c': "k", 'j': "j", 'h': "h", 'd': "d", 'z': "z", 'r': "r", 'q': "q",
+	'f': "f", 'k': "k", 'g': "g", 'l': "l", 'm': "m", 'n': "n", 'x': "x",
+}
+
+//Skeleton returns the consonants of a normalized persian or finglish text, spaces are kept between the words
+func Skeleton(text string) string {
+	var builder strings.Builder
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == ' ' {
+			builder.WriteRune(' ')
+			continue
+		}
+		if i+1 < len(runes) {
+			if symbol, found := latinDigraphs[string(runes[i:i+2])]; found {
+				builder.WriteString(symbol)
+				i++
+				continue
+			}
+		}
+		if symbol, found := persianSkeleton[r]; found {
+			builder.WriteString(symbol)
+		} else if symbol, found := latinSkeleton[r]; found {
+			builder.WriteString(symbol)
+		} else if unicode.IsDigit(r) {
+			builder.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
+//IsLatin reports whether the text has no persian letters
+func IsLatin(text string) bool {
+	for _, r := range text {
+		if unicode.Is(unicode.Arabic, r) {
+			return false
+		}
+	}
+	return true
+}
+
+//Distance is the damerau levenshtein distance of two texts, a swap of two letters is a single typo
+func Distance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	rows := make([][]int, len(s)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(t)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(s)][len(t)]
+}
+
+func min(values ...int) int {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+	return result
+}
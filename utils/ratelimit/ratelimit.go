@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"hotel-engine/utils/ctxtime"
+	"sync"
+	"time"
+)
+
+//ErrDeadlineTooShort is returned when the caller deadline passes before a token would be available
+var ErrDeadlineTooShort = errors.New("rate limiter wait would exceed the context deadline")
+
+//Limiter is a token bucket. the bucket holds up to Burst tokens and is refilled with Rate tokens per second.
+//a zero rate disables the limiter
+type Limiter struct {
+	lock   sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+//Wait takes a token, waiting until one is available or the context is done
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l.rate <= 0 {
+		return nil
+	}
+	l.lock.Lock()
+	l.refill(time.Now())
+	l.tokens--
+	wait := time.Duration(0)
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.lock.Unlock()
+	if wait == 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+		l.giveBack()
+		return ErrDeadlineTooShort
+	}
+	if err := ctxtime.Sleep(ctx, wait); err != nil {
+		l.giveBack()
+		return err
+	}
+	return nil
+}
+
+//Available returns the tokens that can be taken without waiting
+func (l *Limiter) Available() float64 {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.refill(time.Now())
+	if l.tokens < 0 {
+		return 0
+	}
+	return l.tokens
+}
+
+func (l *Limiter) refill(now time.Time) {
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+}
+
+func (l *Limiter) giveBack() {
+	l.lock.Lock()
+	l.tokens++
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.lock.Unlock()
+}
+
+func NewLimiter(rate float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
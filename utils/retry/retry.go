@@ -0,0 +1,122 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hotel-engine/utils/ctxtime"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Kind string
+
+const (
+	Fixed       Kind = "fixed"
+	Exponential Kind = "exponential"
+)
+
+//ErrNotReady is returned by an action when the supplier answered but the result is not final yet
+var ErrNotReady = errors.New("result is not ready yet")
+
+//Policy describes how an operation is retried. the first attempt waits InitialDelay, the next ones wait Delay
+//(multiplied by Multiplier on every attempt for exponential policies) capped by MaxDelay. Jitter spreads every
+//delay randomly by the given fraction. the policy gives up after MaxAttempts or when the next wait would pass MaxElapsed
+type Policy struct {
+	Kind         Kind
+	InitialDelay time.Duration
+	Delay        time.Duration
+	Multiplier   float64
+	MaxDelay     time.Duration
+	Jitter       float64
+	MaxAttempts  int
+	MaxElapsed   time.Duration
+}
+
+//Backoff returns the wait before the given zero based attempt
+func (p Policy) Backoff(attempt int) time.Duration {
+	delay := p.InitialDelay
+	if attempt > 0 {
+		delay = p.Delay
+		if p.Kind == Exponential && p.Multiplier > 0 {
+			delay = time.Duration(float64(p.Delay) * math.Pow(p.Multiplier, float64(attempt-1)))
+		}
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if p.Jitter > 0 && delay > 0 {
+		spread := float64(delay) * p.Jitter
+		delay = time.Duration(float64(delay) - spread + rand.Float64()*2*spread)
+	}
+	return delay
+}
+
+//Run calls the action until it succeeds, returns an error that is not retryable or the policy gives up.
+//the last error of the action is returned when the policy gives up
+func (p Policy) Run(ctx context.Context, retryable func(err error) bool, action func(attempt int) error) error {
+	start := time.Now()
+	var err error
+	for attempt := 0; p.MaxAttempts <= 0 || attempt < p.MaxAttempts; attempt++ {
+		backoff := p.Backoff(attempt)
+		if attempt > 0 && p.MaxElapsed > 0 && time.Since(start)+backoff > p.MaxElapsed {
+			return err
+		}
+		if sleepErr := ctxtime.Sleep(ctx, backoff); sleepErr != nil {
+			return sleepErr
+		}
+		err = action(attempt)
+		if err == nil || !retryable(err) {
+			return err
+		}
+	}
+	return err
+}
+
+//ParsePolicy reads a policy from a comma separated definition like
+//"exponential,initial=1s,delay=2s,multiplier=2,max-delay=2m,jitter=0.1,attempts=8,max-elapsed=5m"
+func ParsePolicy(definition string) (Policy, error) {
+	parts := strings.Split(definition, ",")
+	policy := Policy{
+		Kind:       Kind(strings.TrimSpace(parts[0])),
+		Multiplier: 1,
+	}
+	if policy.Kind != Fixed && policy.Kind != Exponential {
+		return Policy{}, fmt.Errorf("unknown retry policy kind %q", policy.Kind)
+	}
+	for _, part := range parts[1:] {
+		pair := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(pair) != 2 {
+			return Policy{}, fmt.Errorf("invalid retry policy option %q", part)
+		}
+		var err error
+		switch pair[0] {
+		case "initial":
+			policy.InitialDelay, err = time.ParseDuration(pair[1])
+		case "delay":
+			policy.Delay, err = time.ParseDuration(pair[1])
+		case "multiplier":
+			policy.Multiplier, err = strconv.ParseFloat(pair[1], 64)
+		case "max-delay":
+			policy.MaxDelay, err = time.ParseDuration(pair[1])
+		case "jitter":
+			policy.Jitter, err = strconv.ParseFloat(pair[1], 64)
+		case "attempts":
+			policy.MaxAttempts, err = strconv.Atoi(pair[1])
+		case "max-elapsed":
+			policy.MaxElapsed, err = time.ParseDuration(pair[1])
+		default:
+			err = fmt.Errorf("unknown retry policy option %q", pair[0])
+		}
+		if err != nil {
+			return Policy{}, err
+		}
+	}
+	if policy.MaxAttempts <= 0 && policy.MaxElapsed <= 0 {
+		return Policy{}, errors.New("retry policy needs attempts or max-elapsed")
+	}
+	return policy, nil
+}
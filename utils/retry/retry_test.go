@@ -0,0 +1,140 @@
+package retry
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+		want       Policy
+		wantErr    bool
+	}{
+		{
+			name:       "fixed",
+			definition: "fixed,delay=1s,attempts=3",
+			want:       Policy{Kind: Fixed, Delay: time.Second, Multiplier: 1, MaxAttempts: 3},
+		},
+		{
+			name:       "all options",
+			definition: "exponential,initial=1s,delay=2s,multiplier=2,max-delay=2m,jitter=0.1,attempts=8,max-elapsed=5m",
+			want: Policy{
+				Kind:         Exponential,
+				InitialDelay: time.Second,
+				Delay:        2 * time.Second,
+				Multiplier:   2,
+				MaxDelay:     2 * time.Minute,
+				Jitter:       0.1,
+				MaxAttempts:  8,
+				MaxElapsed:   5 * time.Minute,
+			},
+		},
+		{
+			name:       "spaces",
+			definition: " fixed , delay=100ms , max-elapsed=1s",
+			want:       Policy{Kind: Fixed, Delay: 100 * time.Millisecond, Multiplier: 1, MaxElapsed: time.Second},
+		},
+		{
+			name:       "unknown kind",
+			definition: "linear,delay=1s,attempts=3",
+			wantErr:    true,
+		},
+		{
+			name:       "empty",
+			definition: "",
+			wantErr:    true,
+		},
+		{
+			name:       "unknown option",
+			definition: "fixed,delay=1s,attempts=3,timeout=1s",
+			wantErr:    true,
+		},
+		{
+			name:       "option without value",
+			definition: "fixed,delay,attempts=3",
+			wantErr:    true,
+		},
+		{
+			name:       "invalid duration",
+			definition: "fixed,delay=1,attempts=3",
+			wantErr:    true,
+		},
+		{
+			name:       "invalid attempts",
+			definition: "fixed,delay=1s,attempts=three",
+			wantErr:    true,
+		},
+		{
+			name:       "no limit",
+			definition: "exponential,delay=1s,multiplier=2",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePolicy(tt.definition)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePolicy() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicy_Backoff(t *testing.T) {
+	policy := Policy{
+		Kind:         Exponential,
+		InitialDelay: time.Second,
+		Delay:        2 * time.Second,
+		Multiplier:   2,
+		MaxDelay:     10 * time.Second,
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second}
+	for attempt, delay := range want {
+		if got := policy.Backoff(attempt); got != delay {
+			t.Errorf("Backoff(%d) = %v, want %v", attempt, got, delay)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.Backoff(2); got < 2*time.Second || got > 6*time.Second {
+			t.Fatalf("Backoff(2) with jitter = %v, want between 2s and 6s", got)
+		}
+	}
+}
+
+//TestParsePolicy_devEnv keeps the retry schedules of dev.env valid
+func TestParsePolicy_devEnv(t *testing.T) {
+	env, err := godotenv.Read("../../dev.env")
+	if err != nil {
+		t.Fatalf("cannot read dev.env: %v", err)
+	}
+	found := 0
+	for key, definition := range env {
+		if !strings.HasPrefix(key, "HOTEL_ENGINE_RETRY_") {
+			continue
+		}
+		found++
+		t.Run(key, func(t *testing.T) {
+			policy, err := ParsePolicy(definition)
+			if err != nil {
+				t.Fatalf("ParsePolicy(%q) error = %v", definition, err)
+			}
+			if policy.Kind == Exponential && policy.Multiplier < 1 {
+				t.Errorf("ParsePolicy(%q) multiplier = %v, a delay must not shrink", definition, policy.Multiplier)
+			}
+		})
+	}
+	if found == 0 {
+		t.Error("dev.env has no retry policy")
+	}
+}
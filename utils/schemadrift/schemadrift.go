@@ -0,0 +1,133 @@
+package schemadrift
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+//Report lists the differences between a json document and the type it is decoded into. paths are dot separated
+//json keys and array elements are written as []. fields tagged `schema:"required"` are reported in RequiredMissing
+//and fields with omitempty in their json tag are optional
+type Report struct {
+	Unknown         []string
+	Missing         []string
+	RequiredMissing []string
+}
+
+func (r Report) HasDrift() bool {
+	return len(r.Unknown) != 0 || len(r.Missing) != 0 || len(r.RequiredMissing) != 0
+}
+
+type collector struct {
+	unknown         map[string]bool
+	missing         map[string]bool
+	requiredMissing map[string]bool
+}
+
+//Compare walks the json document along the type of v and reports the unknown and missing fields
+func Compare(data []byte, v interface{}) (Report, error) {
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return Report{}, err
+	}
+	c := &collector{
+		unknown:         make(map[string]bool),
+		missing:         make(map[string]bool),
+		requiredMissing: make(map[string]bool),
+	}
+	c.compare(document, reflect.TypeOf(v), "")
+	return Report{
+		Unknown:         sortedKeys(c.unknown),
+		Missing:         sortedKeys(c.missing),
+		RequiredMissing: sortedKeys(c.requiredMissing),
+	}, nil
+}
+
+func (c *collector) compare(value interface{}, t reflect.Type, path string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok || t == timeType {
+			return
+		}
+		known := make(map[string]bool)
+		c.compareFields(object, t, path, known)
+		for key := range object {
+			if !known[key] {
+				c.unknown[join(path, key)] = true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := value.([]interface{})
+		if !ok {
+			return
+		}
+		for _, item := range items {
+			c.compare(item, t.Elem(), path+"[]")
+		}
+	}
+}
+
+func (c *collector) compareFields(object map[string]interface{}, t reflect.Type, path string, known map[string]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options := tag, ""
+		if comma := strings.Index(tag, ","); comma >= 0 {
+			name, options = tag[:comma], tag[comma+1:]
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				c.compareFields(object, embedded, path, known)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		known[name] = true
+		child, found := object[name]
+		switch {
+		case (!found || child == nil) && field.Tag.Get("schema") == "required":
+			c.requiredMissing[join(path, name)] = true
+		case !found && !strings.Contains(options, "omitempty"):
+			c.missing[join(path, name)] = true
+		case found:
+			c.compare(child, field.Type, join(path, name))
+		}
+	}
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,23 @@
+package sideeffect
+
+import (
+	"context"
+	"hotel-engine/utils/atomicflag"
+)
+
+type key struct{}
+
+//Track returns a context that records the calls with a side effect made with it, the flag is set once the first of
+//them is started
+func Track(ctx context.Context) (context.Context, *atomicflag.AtomicFlag) {
+	started := atomicflag.NewAtomicFlag()
+	return context.WithValue(ctx, key{}, started), started
+}
+
+//Mark records that a call with a side effect is started with the context, a context that is not tracked is left as
+//it is
+func Mark(ctx context.Context) {
+	if started, ok := ctx.Value(key{}).(*atomicflag.AtomicFlag); ok {
+		started.Set(true)
+	}
+}
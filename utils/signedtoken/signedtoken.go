@@ -0,0 +1,47 @@
+package signedtoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+//ErrInvalidToken is returned when a token is malformed or is not signed with the secret
+var ErrInvalidToken = errors.New("signed token is not valid")
+
+//Sign encodes the claims as json and signs them with hmac sha256, the token is the encoded claims and the signature
+//separated by a dot. the claims are readable by anyone who has the token, only their integrity is protected
+func Sign(secret string, claims interface{}) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(signature(secret, encoded)), nil
+}
+
+//Verify checks the signature of the token and decodes its claims
+func Verify(secret, token string, claims interface{}) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return ErrInvalidToken
+	}
+	sign, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(sign, signature(secret, parts[0])) {
+		return ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || json.Unmarshal(payload, claims) != nil {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+func signature(secret, encoded string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
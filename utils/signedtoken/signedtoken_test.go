@@ -0,0 +1,56 @@
+package signedtoken
+
+import (
+	"strings"
+	"testing"
+)
+
+type claims struct {
+	OrderId string `json:"orderId"`
+	Amount  int64  `json:"amount"`
+}
+
+func TestSignAndVerify(t *testing.T) {
+	token, err := Sign("secret", claims{OrderId: "42", Amount: 1000})
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	var got claims
+	if err := Verify("secret", token, &got); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if got != (claims{OrderId: "42", Amount: 1000}) {
+		t.Errorf("Verify() claims = %+v", got)
+	}
+}
+
+func TestVerify_invalid(t *testing.T) {
+	token, err := Sign("secret", claims{OrderId: "42", Amount: 1000})
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	parts := strings.Split(token, ".")
+	other, _ := Sign("secret", claims{OrderId: "42", Amount: 1})
+	forged, _ := Sign("another secret", claims{OrderId: "42", Amount: 1})
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "claims of another token", token: strings.Split(other, ".")[0] + "." + parts[1]},
+		{name: "signature of another token", token: parts[0] + "." + strings.Split(other, ".")[1]},
+		{name: "signed with another secret", token: forged},
+		{name: "changed signature", token: parts[0] + "." + parts[1][:len(parts[1])-2] + "AA"},
+		{name: "no signature", token: parts[0]},
+		{name: "extra part", token: token + ".x"},
+		{name: "not base64", token: "!!." + parts[1]},
+		{name: "empty", token: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got claims
+			if err := Verify("secret", tt.token, &got); err != ErrInvalidToken {
+				t.Errorf("Verify() error = %v, want %v", err, ErrInvalidToken)
+			}
+		})
+	}
+}